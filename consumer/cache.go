@@ -1,11 +1,15 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
+	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/allegro/bigcache/v3"
@@ -16,24 +20,38 @@ import (
 var (
 	errMaxEntrySize       = errors.New("entry is to big")
 	errInvalidContentType = errors.New("obeject has a invalid Content Type")
-	errSmallBuffer        = errors.New("unable to get all template")
+	errStreamStarted      = errors.New("file was partially written")
+	errBucketDoesNotExist = errors.New("bucket does not exist")
+)
+
+const megabyte = 1000 * 1000
+
+const (
+	tierMemory = "memory"
+	tierDisk   = "disk"
 )
 
 type cache struct {
+	name              string
 	data              *bigcache.BigCache
+	disk              *disk
+	metrics           *metrics
 	bucket            string
 	minio             *minio.Client
 	maxEntrySize      int64
+	maxRetries        int
+	retryDelay        time.Duration
 	validContentTypes []string
+	loaded            atomic.Bool
 }
 
 func newCache(
+	name string,
 	configs *cacheConfig,
 	minioConfig *minioConfig,
+	metrics *metrics,
 	validContentType ...string,
 ) (*cache, error) {
-	const megabyte = 1000 * 1000
-
 	dataConfig := bigcache.Config{
 		Shards:             configs.Shards,
 		LifeWindow:         time.Duration(configs.LifeWindow) * time.Minute,
@@ -43,6 +61,9 @@ func newCache(
 		HardMaxCacheSize:   configs.MaxSize,
 		StatsEnabled:       configs.Statics,
 		Verbose:            configs.Verbose,
+		OnRemoveWithReason: func(_ string, _ []byte, reason bigcache.RemoveReason) {
+			metrics.cacheEviction(name, reason)
+		},
 	}
 
 	data, err := bigcache.New(context.Background(), dataConfig)
@@ -64,11 +85,29 @@ func newCache(
 		return nil, fmt.Errorf("error creating Minio client: %w", err)
 	}
 
+	var disk *disk
+
+	if configs.DiskPath != "" {
+		disk, err = newDisk(
+			configs.DiskPath,
+			int64(configs.DiskMaxSize)*megabyte,
+			time.Duration(configs.LifeWindow)*time.Minute,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error creating disk cache: %w", err)
+		}
+	}
+
 	return &cache{
+		name:              name,
 		data:              data,
+		disk:              disk,
+		metrics:           metrics,
 		bucket:            configs.Bucket,
 		minio:             minio,
 		maxEntrySize:      int64(configs.MaxEntrySize) * megabyte,
+		maxRetries:        minioConfig.MaxRetries,
+		retryDelay:        time.Duration(minioConfig.RetryDelay) * time.Millisecond,
 		validContentTypes: validContentType,
 	}, nil
 }
@@ -87,37 +126,147 @@ func validContentType(contentType string, contentTypes []string) bool {
 	return false
 }
 
-func (cache *cache) getFileFromMinio(name string) ([]byte, error) {
+// transientError reports if the error from Minio can be solved by trying
+// again.
+func transientError(err error) bool {
+	if errors.Is(err, errStreamStarted) {
+		return false
+	}
+
+	if errors.Is(err, errSizeMismatch) ||
+		errors.Is(err, errChecksumMismatch) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	errorResponse := minio.ErrorResponse{}
+	if errors.As(err, &errorResponse) {
+		return errorResponse.StatusCode >= http.StatusInternalServerError ||
+			errorResponse.StatusCode == http.StatusTooManyRequests ||
+			errorResponse.StatusCode == http.StatusRequestTimeout
+	}
+
+	netError := net.Error(nil)
+
+	return errors.As(err, &netError)
+}
+
+func fetchErrorClass(err error) string {
+	switch {
+	case errors.Is(err, errSizeMismatch) || errors.Is(err, errChecksumMismatch):
+		return "integrity"
+	case transientError(err):
+		return "transient"
+	default:
+		return "permanent"
+	}
+}
+
+// retries tries again the transient errors from Minio with exponential
+// backoff.
+func (cache *cache) retries(name string, try func() (int64, error)) error {
+	delay := cache.retryDelay
+
+	for retry := 0; ; retry++ {
+		timeInit := time.Now()
+
+		fetched, err := try()
+
+		cache.metrics.minioFetch(cache.name, time.Since(timeInit), fetched)
+
+		if err == nil {
+			return nil
+		}
+
+		cache.metrics.minioFetchError(cache.name, fetchErrorClass(err))
+
+		if retry >= cache.maxRetries || !transientError(err) {
+			return err
+		}
+
+		log.Printf("[ERROR] - Error fetching '%s' from Minio, trying again: %s", name, err)
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// fetchFromMinio reads the whole object verifying its size and checksum, it
+// returns the object ETag with the file.
+func (cache *cache) fetchFromMinio(name string) ([]byte, string, error) {
 	object, err := cache.minio.GetObject(
 		context.Background(),
 		cache.bucket,
 		name,
-		minio.GetObjectOptions{},
+		minio.GetObjectOptions{Checksum: true},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error getting object from minio: %w", err)
+		return nil, "", fmt.Errorf("error getting object from minio: %w", err)
 	}
 
+	defer object.Close()
+
 	objectInfo, err := object.Stat()
 	if err != nil {
-		return nil, fmt.Errorf("error getting object status: %w", err)
+		return nil, "", fmt.Errorf("error getting object status: %w", err)
 	}
 
 	if !validContentType(objectInfo.ContentType, cache.validContentTypes) {
-		return nil, fmt.Errorf("%w, %s", errInvalidContentType, objectInfo.ContentType)
+		return nil, "", fmt.Errorf("%w, %s", errInvalidContentType, objectInfo.ContentType)
 	}
 
 	if objectInfo.Size > cache.maxEntrySize {
-		return nil, errMaxEntrySize
+		return nil, "", errMaxEntrySize
 	}
 
 	file := make([]byte, objectInfo.Size)
 
-	_, err = object.Read(file)
-	if err != nil && !errors.Is(err, io.EOF) {
-		return nil, fmt.Errorf("error reading file: %w", err)
-	} else if err == nil {
-		return nil, errSmallBuffer
+	_, err = io.ReadFull(object, file)
+	if err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			return nil, "", fmt.Errorf("%w: %w", errSizeMismatch, err)
+		}
+
+		return nil, "", fmt.Errorf("error reading file: %w", err)
+	}
+
+	extra, _ := object.Read(make([]byte, 1))
+	if extra > 0 {
+		return nil, "", errSizeMismatch
+	}
+
+	checksum := newChecksum(&objectInfo)
+	_, _ = checksum.Write(file)
+
+	err = checksum.verify()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return file, objectInfo.ETag, nil
+}
+
+func (cache *cache) getFileFromMinio(name string) ([]byte, error) {
+	var file []byte
+
+	var etag string
+
+	err := cache.retries(name, func() (int64, error) {
+		var err error
+
+		file, etag, err = cache.fetchFromMinio(name)
+
+		return int64(len(file)), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cache.disk != nil {
+		err = cache.disk.set(name, etag, file)
+		if err != nil {
+			log.Printf("[ERROR] - Error setting '%s' on disk cache: %s", name, err)
+		}
 	}
 
 	err = cache.data.Set(name, file)
@@ -128,7 +277,175 @@ func (cache *cache) getFileFromMinio(name string) ([]byte, error) {
 	return file, nil
 }
 
-// getAllFromMinio get all files from minio bucket and put in the cache.
+// getFileFromDisk get the file from the disk cache, the file is fetched from
+// Minio only when it is not on the disk or its ETag is not the ETag of the
+// object.
+func (cache *cache) getFileFromDisk(name string) ([]byte, error) {
+	if cache.disk == nil {
+		return cache.getFileFromMinio(name)
+	}
+
+	objectInfo, err := cache.statFromMinio(name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := cache.disk.get(name, objectInfo.ETag)
+	if err != nil {
+		if !errors.Is(err, errDiskEntryNotFound) {
+			log.Printf("[ERROR] - Error getting '%s' from disk cache: %s", name, err)
+		}
+
+		cache.metrics.cacheMiss(cache.name, tierDisk)
+
+		return cache.getFileFromMinio(name)
+	}
+
+	cache.metrics.cacheHit(cache.name, tierDisk)
+
+	if int64(len(file)) > cache.maxEntrySize {
+		return nil, errMaxEntrySize
+	}
+
+	err = cache.data.Set(name, file)
+	if err != nil {
+		return nil, fmt.Errorf("error setting file on cache: %w", err)
+	}
+
+	return file, nil
+}
+
+func (cache *cache) statFromMinio(name string) (*minio.ObjectInfo, error) {
+	objectInfo, err := cache.minio.StatObject(
+		context.Background(),
+		cache.bucket,
+		name,
+		minio.StatObjectOptions{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting object status: %w", err)
+	}
+
+	if !validContentType(objectInfo.ContentType, cache.validContentTypes) {
+		return nil, fmt.Errorf("%w, %s", errInvalidContentType, objectInfo.ContentType)
+	}
+
+	return &objectInfo, nil
+}
+
+// streamOnceFromMinio writes the object on writer verifying its size and
+// checksum, the disk cache receives a copy when enabled.
+func (cache *cache) streamOnceFromMinio(name string, writer io.Writer) (int64, error) {
+	object, err := cache.minio.GetObject(
+		context.Background(),
+		cache.bucket,
+		name,
+		minio.GetObjectOptions{Checksum: true},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error getting object from minio: %w", err)
+	}
+
+	defer object.Close()
+
+	objectInfo, err := object.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("error getting object status: %w", err)
+	}
+
+	checksum := newChecksum(&objectInfo)
+	writers := []io.Writer{writer, checksum}
+
+	var file *diskFile
+
+	if cache.disk != nil {
+		file, err = cache.disk.create(name, objectInfo.ETag)
+		if err != nil {
+			log.Printf("[ERROR] - Error setting '%s' on disk cache: %s", name, err)
+		} else {
+			writers = append(writers, file)
+		}
+	}
+
+	written, err := io.Copy(io.MultiWriter(writers...), object)
+	if err == nil {
+		err = checksum.verify()
+	}
+
+	if err != nil {
+		if file != nil {
+			file.abort()
+		}
+
+		if written > 0 {
+			return written, fmt.Errorf("%w: %w", errStreamStarted, err)
+		}
+
+		return written, fmt.Errorf("error streaming file: %w", err)
+	}
+
+	if file != nil {
+		err = file.commit()
+		if err != nil {
+			log.Printf("[ERROR] - Error setting '%s' on disk cache: %s", name, err)
+		}
+	}
+
+	return written, nil
+}
+
+func (cache *cache) streamFromMinio(name string, writer io.Writer) (int64, error) {
+	var written int64
+
+	err := cache.retries(name, func() (int64, error) {
+		var err error
+
+		written, err = cache.streamOnceFromMinio(name, writer)
+
+		return written, err
+	})
+
+	return written, err
+}
+
+// streamFromDisk streams the file from the disk cache, the file is streamed
+// from Minio only when it is not on the disk or its ETag is not the ETag of the
+// object.
+func (cache *cache) streamFromDisk(name string, writer io.Writer) (int64, error) {
+	if cache.disk == nil {
+		return cache.streamFromMinio(name, writer)
+	}
+
+	objectInfo, err := cache.statFromMinio(name)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := cache.disk.open(name, objectInfo.ETag)
+	if err != nil {
+		if !errors.Is(err, errDiskEntryNotFound) {
+			log.Printf("[ERROR] - Error getting '%s' from disk cache: %s", name, err)
+		}
+
+		cache.metrics.cacheMiss(cache.name, tierDisk)
+
+		return cache.streamFromMinio(name, writer)
+	}
+
+	defer file.Close()
+
+	cache.metrics.cacheHit(cache.name, tierDisk)
+
+	written, err := io.Copy(writer, file)
+	if err != nil {
+		return written, fmt.Errorf("error streaming file from disk cache: %w", err)
+	}
+
+	return written, nil
+}
+
+// getAllFromMinio get all files from minio bucket and put in the cache, the
+// cache is loaded if all files were listed.
 func (cache *cache) getAllFromMinio() {
 	options := minio.ListObjectsOptions{
 		WithVersions: false,
@@ -138,11 +455,14 @@ func (cache *cache) getAllFromMinio() {
 	}
 
 	templatesQuantity := 0
+	loaded := true
 
 	for info := range cache.minio.ListObjects(context.Background(), cache.bucket, options) {
 		if info.Err != nil {
 			log.Printf("[ERROR] - Error getting '%s' template info: %s", info.Key, info.Err)
 
+			loaded = false
+
 			continue
 		}
 
@@ -155,13 +475,38 @@ func (cache *cache) getAllFromMinio() {
 	}
 
 	log.Printf("[INFO] - %d templates on cache", templatesQuantity)
+
+	cache.loaded.Store(loaded)
+}
+
+// loadAllFromMinio tries to get all files from the bucket until it is loaded.
+func (cache *cache) loadAllFromMinio(delay time.Duration) {
+	for !cache.loaded.Load() {
+		time.Sleep(delay)
+
+		cache.getAllFromMinio()
+	}
+}
+
+// reachable checks if the bucket can be accessed.
+func (cache *cache) reachable(ctx context.Context) error {
+	exist, err := cache.minio.BucketExists(ctx, cache.bucket)
+	if err != nil {
+		return fmt.Errorf("error checking bucket '%s': %w", cache.bucket, err)
+	}
+
+	if !exist {
+		return fmt.Errorf("%w: %s", errBucketDoesNotExist, cache.bucket)
+	}
+
+	return nil
 }
 
 func (cache *cache) get(name string) ([]byte, error) {
 	file, err := cache.data.Get(name)
 	if err != nil {
 		if errors.Is(err, bigcache.ErrEntryNotFound) {
-			return cache.getFileFromMinio(name)
+			return cache.getFileFromDisk(name)
 		}
 
 		return nil, fmt.Errorf("error getting file from minio: %w", err)
@@ -169,3 +514,49 @@ func (cache *cache) get(name string) ([]byte, error) {
 
 	return file, nil
 }
+
+// size returns the file size without keeping it in memory, files that are
+// not on the memory or disk cache are checked on Minio.
+func (cache *cache) size(name string) (int64, error) {
+	file, err := cache.data.Get(name)
+	if err == nil {
+		return int64(len(file)), nil
+	}
+
+	if !errors.Is(err, bigcache.ErrEntryNotFound) {
+		return 0, fmt.Errorf("error getting file from cache: %w", err)
+	}
+
+	if cache.disk != nil {
+		if size, exist := cache.disk.fileSize(name); exist {
+			return size, nil
+		}
+	}
+
+	objectInfo, err := cache.statFromMinio(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return objectInfo.Size, nil
+}
+
+// write writes the file on writer, files bigger than the max entry size are
+// streamed from Minio instead of being stored on the cache.
+func (cache *cache) write(name string, size int64, writer io.Writer) (int64, error) {
+	if size > cache.maxEntrySize {
+		return cache.streamFromDisk(name, writer)
+	}
+
+	file, err := cache.get(name)
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := bytes.NewReader(file).WriteTo(writer)
+	if err != nil {
+		return written, fmt.Errorf("error writing file: %w", err)
+	}
+
+	return written, nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"crypto/md5"  //nolint:gosec
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+)
+
+var (
+	errSizeMismatch     = errors.New("file size does not match the object size")
+	errChecksumMismatch = errors.New("file checksum does not match the object checksum")
+)
+
+const md5HexSize = 32
+
+// checksum verifies a file fetched from Minio against the object status,
+// using the strongest checksum that Minio returned.
+type checksum struct {
+	hash     hash.Hash
+	expected string
+	encode   func([]byte) string
+	size     int64
+	written  int64
+}
+
+// etagIsMD5 reports if the ETag is the MD5 of the object, it is not for
+// multipart uploads or encrypted objects.
+func etagIsMD5(objectInfo *minio.ObjectInfo) bool {
+	if len(objectInfo.ETag) != md5HexSize {
+		return false
+	}
+
+	if _, err := hex.DecodeString(objectInfo.ETag); err != nil {
+		return false
+	}
+
+	return objectInfo.Metadata.Get("X-Amz-Server-Side-Encryption") == "" &&
+		objectInfo.Metadata.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") == ""
+}
+
+func newChecksum(objectInfo *minio.ObjectInfo) *checksum {
+	checksum := &checksum{
+		size:   objectInfo.Size,
+		encode: base64.StdEncoding.EncodeToString,
+	}
+
+	// Multipart checksums are checksums of the parts checksums.
+	valid := func(value string) bool { return value != "" && !strings.Contains(value, "-") }
+
+	switch {
+	case valid(objectInfo.ChecksumSHA256):
+		checksum.hash = sha256.New()
+		checksum.expected = objectInfo.ChecksumSHA256
+	case valid(objectInfo.ChecksumSHA1):
+		checksum.hash = sha1.New() //nolint:gosec
+		checksum.expected = objectInfo.ChecksumSHA1
+	case valid(objectInfo.ChecksumCRC32C):
+		checksum.hash = crc32.New(crc32.MakeTable(crc32.Castagnoli))
+		checksum.expected = objectInfo.ChecksumCRC32C
+	case valid(objectInfo.ChecksumCRC32):
+		checksum.hash = crc32.NewIEEE()
+		checksum.expected = objectInfo.ChecksumCRC32
+	case etagIsMD5(objectInfo):
+		checksum.hash = md5.New() //nolint:gosec
+		checksum.expected = strings.ToLower(objectInfo.ETag)
+		checksum.encode = hex.EncodeToString
+	}
+
+	return checksum
+}
+
+func (checksum *checksum) Write(data []byte) (int, error) {
+	checksum.written += int64(len(data))
+
+	if checksum.hash != nil {
+		checksum.hash.Write(data)
+	}
+
+	return len(data), nil
+}
+
+func (checksum *checksum) verify() error {
+	if checksum.written != checksum.size {
+		return fmt.Errorf("%w, expected %d got %d", errSizeMismatch, checksum.size, checksum.written)
+	}
+
+	if checksum.hash == nil {
+		return nil
+	}
+
+	if checksum.encode(checksum.hash.Sum(nil)) != checksum.expected {
+		return errChecksumMismatch
+	}
+
+	return nil
+}
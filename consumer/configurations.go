@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -27,14 +28,20 @@ type smtp struct {
 }
 
 type rabbitConfig struct {
-	User       string `config:"user"        validate:"required"`
-	Password   string `config:"password"    validate:"required"`
-	Host       string `config:"host"        validate:"required"`
-	Port       int    `config:"port"        validate:"required"`
-	Vhost      string `config:"vhost"       validate:"required"`
-	Queue      string `config:"queue"       validate:"required"`
-	QueueDLX   string `config:"queue_dlx"   validate:"required"`
-	MaxRetries int64  `config:"max_retries" validate:"required"`
+	User              string `config:"user"               validate:"required"`
+	Password          string `config:"password"           validate:"required"`
+	Host              string `config:"host"               validate:"required"`
+	Port              int    `config:"port"               validate:"required"`
+	Vhost             string `config:"vhost"              validate:"required"`
+	Queue             string `config:"queue"              validate:"required_without_all=Queues QueuesFile Pattern"`
+	QueueDLX          string `config:"queue_dlx"          validate:"required_with=Queue"`
+	Queues            string `config:"queues"`
+	QueuesFile        string `config:"queues_file"`
+	Priority          bool   `config:"priority"`
+	Pattern           string `config:"pattern"`
+	ManagementPort    int    `config:"management_port"    validate:"required_with=Pattern"`
+	DiscoveryInterval int    `config:"discovery_interval" validate:"required"`
+	MaxRetries        int64  `config:"max_retries"        validate:"required"`
 }
 
 type buffer struct {
@@ -53,25 +60,56 @@ type cacheConfig struct {
 	MaxSize      int    `config:"max_size"       validate:"required"`
 	Statics      bool   `config:"statics"`
 	Verbose      bool   `config:"verbose"`
+	DiskPath     string `config:"disk_path"`
+	DiskMaxSize  int    `config:"disk_max_size"  validate:"required_with=DiskPath"`
+}
+
+type attachmentConfig struct {
+	MaxInFlightSize int `config:"max_in_flight_size" validate:"required"`
+}
+
+type metricsConfig struct {
+	MaxTemplates int `config:"max_templates" validate:"required"`
+	MaxDomains   int `config:"max_domains"   validate:"required"`
+}
+
+type healthConfig struct {
+	StalledTimeout int `config:"stalled_timeout"  validate:"required"`
+	LoadRetryDelay int `config:"load_retry_delay" validate:"required"`
+}
+
+type dedupConfig struct {
+	MaxIDs int `config:"max_ids" validate:"required"`
+}
+
+type shutdownConfig struct {
+	Timeout int `config:"timeout" validate:"required"`
 }
 
 type minioConfig struct {
-	Host      string `config:"host"       validate:"required"`
-	Port      int    `config:"port"       validate:"required"`
-	AccessKey string `config:"access_key" validate:"required"`
-	SecretKey string `config:"secret_key" validate:"required"`
-	Secure    bool   `config:"secure"`
+	Host       string `config:"host"        validate:"required"`
+	Port       int    `config:"port"        validate:"required"`
+	AccessKey  string `config:"access_key"  validate:"required"`
+	SecretKey  string `config:"secret_key"  validate:"required"`
+	Secure     bool   `config:"secure"`
+	MaxRetries int    `config:"max_retries" validate:"min=0"`
+	RetryDelay int    `config:"retry_delay" validate:"required"`
 }
 
 type configurations struct {
-	Sender   sender       `config:"sender"   validate:"required"`
-	SMTP     smtp         `config:"smtp"     validate:"required"`
-	Rabbit   rabbitConfig `config:"rabbit"   validate:"required"`
-	Buffer   buffer       `config:"buffer"   validate:"required"`
-	Timeout  int          `config:"timeout"  validate:"required"`
-	Cache    cacheConfig  `config:"cache"    validate:"required"`
-	Template cacheConfig  `config:"template" validate:"required"`
-	Minio    minioConfig  `config:"minio"    validate:"required"`
+	Sender     sender           `config:"sender"     validate:"required"`
+	SMTP       smtp             `config:"smtp"       validate:"required"`
+	Rabbit     rabbitConfig     `config:"rabbit"     validate:"required"`
+	Buffer     buffer           `config:"buffer"     validate:"required"`
+	Timeout    int              `config:"timeout"    validate:"required"`
+	Cache      cacheConfig      `config:"cache"      validate:"required"`
+	Template   cacheConfig      `config:"template"   validate:"required"`
+	Attachment attachmentConfig `config:"attachment" validate:"required"`
+	Minio      minioConfig      `config:"minio"      validate:"required"`
+	Metrics    metricsConfig    `config:"metrics"    validate:"required"`
+	Shutdown   shutdownConfig   `config:"shutdown"   validate:"required"`
+	Health     healthConfig     `config:"health"     validate:"required"`
+	Dedup      dedupConfig      `config:"dedup"      validate:"required"`
 }
 
 //nolint:gomnd
@@ -81,9 +119,10 @@ func defaultConfigurations() configurations {
 			Port: 587,
 		},
 		Rabbit: rabbitConfig{
-			Port:       5672,
-			Vhost:      "/",
-			MaxRetries: 4,
+			Port:              5672,
+			Vhost:             "/",
+			MaxRetries:        4,
+			DiscoveryInterval: 30,
 		},
 		Buffer: buffer{
 			Size:     100,
@@ -99,6 +138,7 @@ func defaultConfigurations() configurations {
 			MaxSize:      1000,
 			Statics:      false,
 			Verbose:      false,
+			DiskMaxSize:  10000,
 		},
 		Template: cacheConfig{
 			Shards:       64,
@@ -110,15 +150,57 @@ func defaultConfigurations() configurations {
 			MaxSize:      100,
 			Statics:      false,
 			Verbose:      false,
+			DiskMaxSize:  1000,
+		},
+		Attachment: attachmentConfig{
+			MaxInFlightSize: 100,
 		},
 		Minio: minioConfig{
-			Port:   9000,
-			Secure: true,
+			Port:       9000,
+			Secure:     true,
+			MaxRetries: 3,
+			RetryDelay: 200,
+		},
+		Metrics: metricsConfig{
+			MaxTemplates: 50,
+			MaxDomains:   20,
+		},
+		Shutdown: shutdownConfig{
+			Timeout: 30,
+		},
+		Dedup: dedupConfig{
+			MaxIDs: 100000,
+		},
+		Health: healthConfig{
+			StalledTimeout: 120,
+			LoadRetryDelay: 30,
 		},
 		Timeout: 2,
 	}
 }
 
+var errSharedDiskPath = errors.New("attachment and template caches can not share the disk path")
+
+// sharedDiskPath reports if both caches use the same disk directory, the LRU
+// of one cache would remove the files of the other.
+func sharedDiskPath(config *configurations) bool {
+	if config.Cache.DiskPath == "" || config.Template.DiskPath == "" {
+		return false
+	}
+
+	cache, err := filepath.Abs(config.Cache.DiskPath)
+	if err != nil {
+		return false
+	}
+
+	template, err := filepath.Abs(config.Template.DiskPath)
+	if err != nil {
+		return false
+	}
+
+	return cache == template
+}
+
 func parseEnv(env string) string {
 	keys := strings.SplitN(env, "_", 2) //nolint:gomnd
 	size := len(keys)
@@ -193,8 +275,10 @@ func getConfigurations() (*configurations, error) {
 		if len(validationErrs) > 0 {
 			return nil, fmt.Errorf("error on validating configurations: %w", validationErrs)
 		}
+	}
 
-		return config, nil
+	if sharedDiskPath(config) {
+		return nil, errSharedDiskPath
 	}
 
 	return config, nil
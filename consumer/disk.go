@@ -0,0 +1,368 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errDiskEntryNotFound = errors.New("entry not found on disk")
+
+const diskTempPrefix = ".tmp-"
+
+type diskEntry struct {
+	key       string
+	etag      string
+	size      int64
+	createdAt time.Time
+}
+
+// disk is a size bounded LRU cache stored in a directory, the files are named
+// by the hash of the object name and by the object ETag. A file is only used
+// when its ETag is the ETag of the object on Minio, so a replaced object is
+// fetched again. Like the memory cache, the files older than the life window
+// are fetched again, a zero life window keeps them until they are evicted.
+type disk struct {
+	path       string
+	maxSize    int64
+	lifeWindow time.Duration
+	size       int64
+	entries    *list.List
+	index      map[string]*list.Element
+	mutex      sync.Mutex
+}
+
+func newDisk(path string, maxSize int64, lifeWindow time.Duration) (*disk, error) {
+	err := os.MkdirAll(path, 0o750) //nolint:gomnd
+	if err != nil {
+		return nil, fmt.Errorf("error creating disk cache directory: %w", err)
+	}
+
+	disk := &disk{
+		path:       path,
+		maxSize:    maxSize,
+		lifeWindow: lifeWindow,
+		entries:    list.New(),
+		index:      map[string]*list.Element{},
+	}
+
+	err = disk.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return disk, nil
+}
+
+// load puts the files that already are on the directory in the LRU, the most
+// recently modified first.
+func (disk *disk) load() error {
+	dirEntries, err := os.ReadDir(disk.path)
+	if err != nil {
+		return fmt.Errorf("error reading disk cache directory: %w", err)
+	}
+
+	files := make([]os.FileInfo, 0, len(dirEntries))
+
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+
+		if strings.HasPrefix(dirEntry.Name(), diskTempPrefix) {
+			err := os.Remove(filepath.Join(disk.path, dirEntry.Name()))
+			if err != nil {
+				log.Printf("[ERROR] - Error removing temporary file from disk cache: %s", err)
+			}
+
+			continue
+		}
+
+		if _, _, valid := parseFileName(dirEntry.Name()); !valid {
+			err := os.Remove(filepath.Join(disk.path, dirEntry.Name()))
+			if err != nil {
+				log.Printf("[ERROR] - Error removing file without ETag from disk cache: %s", err)
+			}
+
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			return fmt.Errorf("error getting disk cache file info: %w", err)
+		}
+
+		files = append(files, info)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+
+	disk.mutex.Lock()
+	defer disk.mutex.Unlock()
+
+	for _, file := range files {
+		key, etag, _ := parseFileName(file.Name())
+		disk.add(key, etag, file.Size(), file.ModTime())
+	}
+
+	log.Printf("[INFO] - %d files on disk cache", disk.entries.Len())
+
+	return nil
+}
+
+func diskKey(name string) string {
+	hash := sha256.Sum256([]byte(name))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func (disk *disk) fileName(key, etag string) string {
+	return filepath.Join(disk.path, key+"."+hex.EncodeToString([]byte(etag)))
+}
+
+// parseFileName returns the key and the ETag of the file, the files of older
+// versions are not valid because they do not have the ETag.
+func parseFileName(name string) (string, string, bool) {
+	key, encoded, found := strings.Cut(name, ".")
+	if !found {
+		return "", "", false
+	}
+
+	etag, err := hex.DecodeString(encoded)
+	if err != nil {
+		return "", "", false
+	}
+
+	return key, string(etag), true
+}
+
+// add must be called with the mutex locked, the file of other ETag is removed.
+func (disk *disk) add(key, etag string, size int64, createdAt time.Time) {
+	if element, exist := disk.index[key]; exist {
+		entry, _ := element.Value.(*diskEntry)
+
+		if entry.etag != etag {
+			disk.removeFile(entry)
+		}
+
+		disk.size += size - entry.size
+		entry.etag = etag
+		entry.size = size
+		entry.createdAt = createdAt
+		disk.entries.MoveToFront(element)
+	} else {
+		entry := &diskEntry{key: key, etag: etag, size: size, createdAt: createdAt}
+		disk.index[key] = disk.entries.PushFront(entry)
+		disk.size += size
+	}
+
+	for disk.size > disk.maxSize && disk.entries.Len() > 1 {
+		disk.remove(disk.entries.Back())
+	}
+}
+
+// remove must be called with the mutex locked.
+func (disk *disk) remove(element *list.Element) {
+	entry, _ := disk.entries.Remove(element).(*diskEntry)
+
+	delete(disk.index, entry.key)
+	disk.size -= entry.size
+
+	disk.removeFile(entry)
+}
+
+func (disk *disk) removeFile(entry *diskEntry) {
+	err := os.Remove(disk.fileName(entry.key, entry.etag))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("[ERROR] - Error removing file from disk cache: %s", err)
+	}
+}
+
+// touch returns a copy of the entry, the entries older than the life window
+// are removed.
+func (disk *disk) touch(key string) (diskEntry, bool) {
+	disk.mutex.Lock()
+	defer disk.mutex.Unlock()
+
+	element, exist := disk.index[key]
+	if !exist {
+		return diskEntry{}, false
+	}
+
+	entry, _ := element.Value.(*diskEntry)
+	if disk.lifeWindow > 0 && time.Since(entry.createdAt) > disk.lifeWindow {
+		disk.remove(element)
+
+		return diskEntry{}, false
+	}
+
+	disk.entries.MoveToFront(element)
+
+	return *entry, true
+}
+
+// use returns the entry of the file when it has the ETag, the file of other
+// ETag is removed.
+func (disk *disk) use(name, etag string) (diskEntry, error) {
+	key := diskKey(name)
+
+	entry, exist := disk.touch(key)
+	if !exist {
+		return diskEntry{}, errDiskEntryNotFound
+	}
+
+	if entry.etag != etag {
+		disk.forget(key)
+
+		return diskEntry{}, errDiskEntryNotFound
+	}
+
+	return entry, nil
+}
+
+// fileSize returns the size of the file without checking its ETag.
+func (disk *disk) fileSize(name string) (int64, bool) {
+	entry, exist := disk.touch(diskKey(name))
+
+	return entry.size, exist
+}
+
+func (disk *disk) forget(key string) {
+	disk.mutex.Lock()
+	defer disk.mutex.Unlock()
+
+	if element, exist := disk.index[key]; exist {
+		disk.remove(element)
+	}
+}
+
+func (disk *disk) get(name, etag string) ([]byte, error) {
+	entry, err := disk.use(name, etag)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.ReadFile(disk.fileName(entry.key, entry.etag))
+	if err != nil {
+		disk.forget(entry.key)
+
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errDiskEntryNotFound
+		}
+
+		return nil, fmt.Errorf("error reading file from disk cache: %w", err)
+	}
+
+	return file, nil
+}
+
+func (disk *disk) open(name, etag string) (*os.File, error) {
+	entry, err := disk.use(name, etag)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(disk.fileName(entry.key, entry.etag))
+	if err != nil {
+		disk.forget(entry.key)
+
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errDiskEntryNotFound
+		}
+
+		return nil, fmt.Errorf("error opening file from disk cache: %w", err)
+	}
+
+	return file, nil
+}
+
+// diskFile is a file being written to the disk cache, it is only visible to
+// the cache after commit.
+type diskFile struct {
+	file *os.File
+	disk *disk
+	key  string
+	etag string
+	size int64
+}
+
+func (disk *disk) create(name, etag string) (*diskFile, error) {
+	file, err := os.CreateTemp(disk.path, diskTempPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("error creating file on disk cache: %w", err)
+	}
+
+	return &diskFile{file: file, disk: disk, key: diskKey(name), etag: etag}, nil
+}
+
+func (file *diskFile) Write(data []byte) (int, error) {
+	written, err := file.file.Write(data)
+	file.size += int64(written)
+
+	return written, err //nolint:wrapcheck
+}
+
+func (file *diskFile) commit() error {
+	err := file.file.Close()
+	if err != nil {
+		file.abort()
+
+		return fmt.Errorf("error closing file on disk cache: %w", err)
+	}
+
+	if file.size > file.disk.maxSize {
+		file.abort()
+
+		return nil
+	}
+
+	err = os.Rename(file.file.Name(), file.disk.fileName(file.key, file.etag))
+	if err != nil {
+		file.abort()
+
+		return fmt.Errorf("error renaming file on disk cache: %w", err)
+	}
+
+	file.disk.mutex.Lock()
+	defer file.disk.mutex.Unlock()
+
+	file.disk.add(file.key, file.etag, file.size, time.Now())
+
+	return nil
+}
+
+func (file *diskFile) abort() {
+	_ = file.file.Close()
+
+	err := os.Remove(file.file.Name())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("[ERROR] - Error removing temporary file from disk cache: %s", err)
+	}
+}
+
+func (disk *disk) set(name, etag string, data []byte) error {
+	file, err := disk.create(name, etag)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err != nil {
+		file.abort()
+
+		return fmt.Errorf("error writing file on disk cache: %w", err)
+	}
+
+	return file.commit()
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/thiago-felipe-99/mail/rabbit"
+)
+
+var (
+	errRabbitDisconnected = errors.New("RabbitMQ connection is closed")
+	errTemplatesNotLoaded = errors.New("templates were not loaded from Minio")
+	errQueueStalled       = errors.New("queue is not being consumed")
+)
+
+const healthTimeout = 5 * time.Second
+
+type dependencyHealth struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+type queueHealth struct {
+	Name      string     `json:"name"`
+	Consuming bool       `json:"consuming"`
+	Since     time.Time  `json:"since"`
+	LastSent  *time.Time `json:"lastSent,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+type healthStatus struct {
+	Healthy      bool               `json:"healthy"`
+	Dependencies []dependencyHealth `json:"dependencies,omitempty"`
+	Queues       []queueHealth      `json:"queues"`
+}
+
+// health reports the consumer state, a queue that is not consumed for longer
+// than stalledTimeout makes the consumer not alive.
+type health struct {
+	rabbit         *rabbit.Rabbit
+	queues         *queues
+	template       *cache
+	caches         []*cache
+	stalledTimeout time.Duration
+}
+
+func newHealth(
+	rabbit *rabbit.Rabbit,
+	queues *queues,
+	template *cache,
+	stalledTimeout time.Duration,
+	caches ...*cache,
+) *health {
+	return &health{
+		rabbit:         rabbit,
+		queues:         queues,
+		template:       template,
+		caches:         caches,
+		stalledTimeout: stalledTimeout,
+	}
+}
+
+// queuesHealth reports each queue, the queue is unhealthy if it is not being
+// consumed for longer than the timeout.
+func (health *health) queuesHealth(timeout time.Duration) ([]queueHealth, bool) {
+	health.queues.mutex.Lock()
+
+	running := make([]*queue, 0, len(health.queues.running))
+	for _, queue := range health.queues.running {
+		running = append(running, queue)
+	}
+
+	health.queues.mutex.Unlock()
+
+	sort.Slice(running, func(i, j int) bool { return running[i].Name < running[j].Name })
+
+	healthy := true
+	queues := make([]queueHealth, 0, len(running))
+
+	for _, queue := range running {
+		status := queueHealth{
+			Name:      queue.Name,
+			Consuming: queue.consuming.Load(),
+			Since:     time.Unix(0, queue.changedAt.Load()),
+		}
+
+		if lastSent := queue.send.lastSent.Load(); lastSent > 0 {
+			sent := time.Unix(0, lastSent)
+			status.LastSent = &sent
+		}
+
+		if !status.Consuming && time.Since(status.Since) > timeout {
+			status.Error = errQueueStalled.Error()
+			healthy = false
+		}
+
+		queues = append(queues, status)
+	}
+
+	return queues, healthy
+}
+
+func (health *health) live() healthStatus {
+	queues, healthy := health.queuesHealth(health.stalledTimeout)
+
+	return healthStatus{
+		Healthy: healthy,
+		Queues:  queues,
+	}
+}
+
+func (health *health) ready() healthStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	queues, healthy := health.queuesHealth(0)
+
+	status := healthStatus{
+		Healthy: healthy,
+		Queues:  queues,
+	}
+
+	check := func(name string, err error) {
+		dependency := dependencyHealth{Name: name, Healthy: err == nil}
+
+		if err != nil {
+			dependency.Error = err.Error()
+			status.Healthy = false
+		}
+
+		status.Dependencies = append(status.Dependencies, dependency)
+	}
+
+	var err error
+	if !health.rabbit.IsConnected() {
+		err = errRabbitDisconnected
+	}
+
+	check("rabbit", err)
+
+	for _, cache := range health.caches {
+		check(fmt.Sprintf("minio_%s", cache.name), cache.reachable(ctx))
+	}
+
+	err = nil
+	if !health.template.loaded.Load() {
+		err = errTemplatesNotLoaded
+	}
+
+	check("templates", err)
+
+	return status
+}
+
+func writeHealth(writer http.ResponseWriter, status healthStatus) {
+	writer.Header().Set("Content-Type", "application/json")
+
+	if !status.Healthy {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	err := json.NewEncoder(writer).Encode(status)
+	if err != nil {
+		log.Printf("[ERROR] - Error writing health status: %s", err)
+	}
+}
+
+func (health *health) handleLive(writer http.ResponseWriter, _ *http.Request) {
+	writeHealth(writer, health.live())
+}
+
+func (health *health) handleReady(writer http.ResponseWriter, _ *http.Request) {
+	writeHealth(writer, health.ready())
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/thiago-felipe-99/mail/rabbit"
@@ -17,26 +20,156 @@ func newRabbit(configs *configurations) *rabbit.Rabbit {
 		Vhost:    configs.Rabbit.Vhost,
 	}
 
+	if configs.Rabbit.ManagementPort != 0 {
+		config.ManagementPort = fmt.Sprint(configs.Rabbit.ManagementPort)
+	}
+
 	rabbit := rabbit.New(config)
 
 	return rabbit
 }
 
-func consumeMessages(rabbit *rabbit.Rabbit, configs *configurations, queue chan<- rabbit.Message) {
+// wait sleeps for the duration, it returns false if the queue was stopped.
+func wait(stop <-chan struct{}, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// consume consumes the high priority queue when it exists, even if the queue
+// was not configured with priority, so its messages are not left behind.
+func consume(rabbit *rabbit.Rabbit, queue *queue) (*rabbit.Consumer, error) {
+	bufferSize := queue.Buffer.Size * queue.Buffer.Quantity
+	priority := queue.Priority
+
+	if !priority {
+		exist, err := rabbit.HasHighPriority(queue.Name)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		priority = exist
+	}
+
+	return rabbit.Consume(queue.Name, bufferSize, priority) //nolint:wrapcheck
+}
+
+// drainMessages cancels the consumer and forwards the messages already
+// delivered, so they are sent instead of waiting to be requeued.
+func drainMessages(to chan<- rabbit.Message, consumer *rabbit.Consumer) {
+	err := consumer.Cancel()
+	if err != nil {
+		log.Printf("[ERROR] - Error canceling the consumer: %s", err)
+
+		return
+	}
+
+	high, messages := consumer.High, consumer.Messages
+
+	for high != nil || messages != nil {
+		select {
+		case message, ok := <-high:
+			if !ok {
+				high = nil
+
+				continue
+			}
+
+			to <- consumer.Track(message)
+		case message, ok := <-messages:
+			if !ok {
+				messages = nil
+
+				continue
+			}
+
+			to <- consumer.Track(message)
+		}
+	}
+}
+
+// forwardMessages always forwards the high priority messages first, it returns
+// when one of the queues is closed or the queue was stopped and drained.
+func forwardMessages(stop <-chan struct{}, to chan<- rabbit.Message, consumer *rabbit.Consumer) {
+	high, messages := consumer.High, consumer.Messages
+
+	for {
+		select {
+		case <-stop:
+			drainMessages(to, consumer)
+
+			return
+		case message, ok := <-high:
+			if !ok {
+				return
+			}
+
+			to <- consumer.Track(message)
+
+			continue
+		default:
+		}
+
+		select {
+		case <-stop:
+			drainMessages(to, consumer)
+
+			return
+		case message, ok := <-high:
+			if !ok {
+				return
+			}
+
+			to <- consumer.Track(message)
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			to <- consumer.Track(message)
+		}
+	}
+}
+
+func consumeMessages(rabbit *rabbit.Rabbit, queue *queue) {
+	defer close(queue.messages)
+
 	sleep := time.Second
 
 	for {
-		log.Printf("[INFO] - Creating the consumer")
+		select {
+		case <-queue.stop:
+			log.Printf("[INFO] - The consumer of '%s' was stopped", queue.Name)
+
+			return
+		default:
+		}
 
-		err := rabbit.CreateQueueWithDLX(
-			configs.Rabbit.Queue,
-			configs.Rabbit.QueueDLX,
-			configs.Rabbit.MaxRetries,
-		)
+		log.Printf("[INFO] - Creating the consumer of '%s'", queue.Name)
+
+		if queue.declare {
+			err := rabbit.CreateQueueWithDLX(queue.Name, queue.DLX, queue.MaxRetries, queue.Priority)
+			if err != nil {
+				log.Printf("[ERROR] - Erro creating consumer of '%s': %s", queue.Name, err)
+
+				wait(queue.stop, sleep)
+				sleep *= 2
+
+				continue
+			}
+		}
+
+		consumer, err := consume(rabbit, queue)
 		if err != nil {
-			log.Printf("[ERROR] - Erro creating consumer: %s", err)
+			log.Printf("[ERROR] - Error consuming the queue '%s': %s", queue.Name, err)
 
-			time.Sleep(sleep)
+			wait(queue.stop, sleep)
 			sleep *= 2
 
 			continue
@@ -44,23 +177,22 @@ func consumeMessages(rabbit *rabbit.Rabbit, configs *configurations, queue chan<
 
 		sleep = time.Second
 
-		messages, err := rabbit.Consume(
-			configs.Rabbit.Queue,
-			configs.Buffer.Size*configs.Buffer.Quantity,
-		)
-		if err != nil {
-			log.Printf("[ERROR] - Error consuming the queue: %s", err)
+		log.Printf("[INFO] - Consuming the queue '%s'", queue.Name)
 
-			continue
-		}
+		queue.setConsuming(true)
 
-		log.Printf("[INFO] - Consuming the queue")
+		forwardMessages(queue.stop, queue.messages, consumer)
 
-		for message := range messages {
-			queue <- message
+		queue.setConsuming(false)
+
+		// The forwarded messages are tracked, the channel is only closed after
+		// the batches holding them have acknowledged them.
+		err = consumer.Close()
+		if err != nil {
+			log.Printf("[ERROR] - Error closing the consumer of '%s': %s", queue.Name, err)
 		}
 
-		log.Printf("[INFO] - The queue was closed, restarting the consumer")
+		log.Printf("[INFO] - The queue '%s' was closed, restarting the consumer", queue.Name)
 	}
 }
 
@@ -73,9 +205,19 @@ func getMessages(
 	buffer := []rabbit.Message{}
 	ticker := time.NewTicker(timeout)
 
+	defer ticker.Stop()
+
 	for {
 		select {
-		case message := <-queue:
+		case message, ok := <-queue:
+			if !ok {
+				if len(buffer) > 0 {
+					send.copyQueueAndSendEmails(buffer)
+				}
+
+				return
+			}
+
 			buffer = append(buffer, message)
 
 			ticker.Reset(timeout)
@@ -92,14 +234,18 @@ func getMessages(
 	}
 }
 
-func logSend(send *send) {
-	for status := range send.status {
+func logSend(statuses <-chan sendStatus) {
+	for status := range statuses {
 		if status.successfully > 0 {
-			log.Printf("[INFO] - Were sent %d successfully emails", status.successfully)
+			log.Printf("[INFO] - Were sent %d successfully emails from '%s'", status.successfully, status.queue)
+		}
+
+		if status.duplicated > 0 {
+			log.Printf("[INFO] - Skipped %d duplicated emails from '%s'", status.duplicated, status.queue)
 		}
 
 		if status.failed > 0 {
-			log.Printf("[ERROR] - Failed to send %d emails", status.failed)
+			log.Printf("[ERROR] - Failed to send %d emails from '%s'", status.failed, status.queue)
 		}
 
 		for _, err := range status.errors {
@@ -116,14 +262,16 @@ func main() {
 		return
 	}
 
-	cache, err := newCache(&configs.Cache, &configs.Minio)
+	metrics := newMetrics(&configs.Metrics)
+
+	cache, err := newCache("attachment", &configs.Cache, &configs.Minio, metrics)
 	if err != nil {
 		log.Printf("[ERROR] - Error creating the files cache: %s", err)
 
 		return
 	}
 
-	template, err := newCache(&configs.Template, &configs.Minio, "text/markdown")
+	template, err := newCache("template", &configs.Template, &configs.Minio, metrics, "text/markdown")
 	if err != nil {
 		log.Printf("[ERROR] - Error creating the files cache: %s", err)
 
@@ -132,37 +280,62 @@ func main() {
 
 	template.getAllFromMinio()
 
-	queue := make(chan rabbit.Message)
+	go template.loadAllFromMinio(time.Duration(configs.Health.LoadRetryDelay) * time.Second)
 
 	rabbit := newRabbit(configs)
 
 	go rabbit.HandleConnection()
 
-	go consumeMessages(rabbit, configs, queue)
+	queues, err := newQueues(rabbit, configs, cache, template, metrics)
+	if err != nil {
+		log.Printf("[ERROR] - Error creating the queues: %s", err)
+
+		return
+	}
 
-	defer rabbit.Close()
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
 
-	metrics := newMetrics()
-	send := newSend(
+	health := newHealth(
+		rabbit,
+		queues,
+		template,
+		time.Duration(configs.Health.StalledTimeout)*time.Second,
 		cache,
 		template,
-		&configs.Sender,
-		&configs.SMTP,
-		metrics,
-		configs.Rabbit.MaxRetries,
 	)
-	timeout := time.Duration(configs.Timeout) * time.Second
 
-	var wait chan struct{}
+	go serverMetrics(metrics, health)
+
+	go cacheMetrics(metrics, cache, template)
 
-	go serverMetrics(metrics)
+	logged := make(chan struct{})
 
-	go cacheMetrics(cache, metrics)
+	go func() {
+		logSend(queues.status)
+		close(logged)
+	}()
 
-	go getMessages(queue, send, timeout, configs.Buffer.Size)
+	queues.startConfigured()
 
-	go logSend(send)
+	go queues.discoverForever(ctx)
 
 	log.Printf("[INFO] - Server started successfully")
-	<-wait
+	<-ctx.Done()
+
+	log.Printf("[INFO] - Shutting down the server")
+
+	err = queues.shutdown(time.Duration(configs.Shutdown.Timeout) * time.Second)
+	if err != nil {
+		log.Printf("[ERROR] - Error shutting down the queues: %s", err)
+	} else {
+		<-logged
+	}
+
+	err = rabbit.Close()
+	if err != nil {
+		log.Printf("[ERROR] - Error closing RabbitMQ connection: %s", err)
+	}
+
+	log.Printf("[INFO] - Server stopped")
 }
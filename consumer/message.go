@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+)
+
+const base64LineLength = 76
+
+var errInvalidMessage = errors.New("message without header")
+
+// lineBreaker breaks the base64 output in lines of base64LineLength.
+type lineBreaker struct {
+	writer io.Writer
+	used   int
+}
+
+func (breaker *lineBreaker) Write(data []byte) (int, error) {
+	written := 0
+
+	for len(data) > 0 {
+		size := base64LineLength - breaker.used
+		if size > len(data) {
+			size = len(data)
+		}
+
+		n, err := breaker.writer.Write(data[:size])
+		written += n
+
+		if err != nil {
+			return written, err //nolint:wrapcheck
+		}
+
+		data = data[size:]
+		breaker.used += size
+
+		if breaker.used == base64LineLength {
+			_, err := io.WriteString(breaker.writer, "\r\n")
+			if err != nil {
+				return written, err //nolint:wrapcheck
+			}
+
+			breaker.used = 0
+		}
+	}
+
+	return written, nil
+}
+
+func (breaker *lineBreaker) Close() error {
+	if breaker.used == 0 {
+		return nil
+	}
+
+	breaker.used = 0
+
+	_, err := io.WriteString(breaker.writer, "\r\n")
+
+	return err //nolint:wrapcheck
+}
+
+// splitBodyHeader splits the message header in the header of the message and
+// the header of the body, the body header is moved to the first part.
+func splitBodyHeader(header []byte) ([]byte, textproto.MIMEHeader) {
+	messageHeader := bytes.Buffer{}
+	bodyHeader := textproto.MIMEHeader{}
+	bodyKey := ""
+
+	for _, line := range strings.Split(string(header), "\r\n") {
+		if line == "" {
+			continue
+		}
+
+		if line[0] != ' ' && line[0] != '\t' {
+			key, value, _ := strings.Cut(line, ":")
+			key = textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(key))
+			bodyKey = ""
+
+			if key == "Content-Type" || key == "Content-Transfer-Encoding" {
+				bodyKey = key
+				bodyHeader.Set(key, strings.TrimSpace(value))
+
+				continue
+			}
+		} else if bodyKey != "" {
+			bodyHeader.Set(bodyKey, bodyHeader.Get(bodyKey)+" "+strings.TrimSpace(line))
+
+			continue
+		}
+
+		messageHeader.WriteString(line + "\r\n")
+	}
+
+	return messageHeader.Bytes(), bodyHeader
+}
+
+func attachmentHeader(name string) textproto.MIMEHeader {
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"name": name}))
+	header.Set("Content-Transfer-Encoding", "base64")
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+
+	return header
+}
+
+// writeMessage writes the message with the attachments base64 encoded straight
+// to the writer, so the attachments are not held in memory.
+func writeMessage(writer io.Writer, cache *cache, email *email) error {
+	if len(email.attachments) == 0 {
+		_, err := email.messageMail.WriteTo(writer)
+		if err != nil {
+			return fmt.Errorf("error writing message: %w", err)
+		}
+
+		return nil
+	}
+
+	message := bytes.Buffer{}
+
+	_, err := email.messageMail.WriteTo(&message)
+	if err != nil {
+		return fmt.Errorf("error writing message: %w", err)
+	}
+
+	header, body, found := bytes.Cut(message.Bytes(), []byte("\r\n\r\n"))
+	if !found {
+		return errInvalidMessage
+	}
+
+	messageHeader, bodyHeader := splitBodyHeader(header)
+	parts := multipart.NewWriter(writer)
+
+	_, err = writer.Write(messageHeader)
+	if err != nil {
+		return fmt.Errorf("error writing message header: %w", err)
+	}
+
+	_, err = fmt.Fprintf(writer, "Content-Type: multipart/mixed;\r\n boundary=%q\r\n\r\n", parts.Boundary())
+	if err != nil {
+		return fmt.Errorf("error writing message header: %w", err)
+	}
+
+	part, err := parts.CreatePart(bodyHeader)
+	if err != nil {
+		return fmt.Errorf("error creating message body: %w", err)
+	}
+
+	_, err = part.Write(body)
+	if err != nil {
+		return fmt.Errorf("error writing message body: %w", err)
+	}
+
+	for _, attachment := range email.attachments {
+		part, err := parts.CreatePart(attachmentHeader(attachment.name))
+		if err != nil {
+			return fmt.Errorf("error creating attachment part: %w", err)
+		}
+
+		lines := &lineBreaker{writer: part}
+		encoder := base64.NewEncoder(base64.StdEncoding, lines)
+
+		_, err = cache.write(attachment.name, attachment.size, encoder)
+		if err != nil {
+			return fmt.Errorf("error writing attachment: %w", err)
+		}
+
+		err = encoder.Close()
+		if err != nil {
+			return fmt.Errorf("error encoding attachment: %w", err)
+		}
+
+		err = lines.Close()
+		if err != nil {
+			return fmt.Errorf("error encoding attachment: %w", err)
+		}
+	}
+
+	err = parts.Close()
+	if err != nil {
+		return fmt.Errorf("error closing message parts: %w", err)
+	}
+
+	return nil
+}
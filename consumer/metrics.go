@@ -3,82 +3,213 @@ package main
 import (
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/allegro/bigcache/v3"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// labelLimit bounds the quantity of distinct values of a label, the values
+// after the limit are reported as "other".
+type labelLimit struct {
+	values map[string]struct{}
+	max    int
+	mutex  sync.Mutex
+}
+
+func newLabelLimit(maxValues int) *labelLimit {
+	return &labelLimit{
+		values: map[string]struct{}{},
+		max:    maxValues,
+	}
+}
+
+func (limit *labelLimit) value(value string) string {
+	limit.mutex.Lock()
+	defer limit.mutex.Unlock()
+
+	if _, exist := limit.values[value]; exist {
+		return value
+	}
+
+	if len(limit.values) >= limit.max {
+		return "other"
+	}
+
+	limit.values[value] = struct{}{}
+
+	return value
+}
+
 type metrics struct {
-	emailsReceived             prometheus.Counter
-	emailsReceivedBytes        prometheus.Counter
-	emailsSent                 prometheus.Counter
-	emailsSentBytes            prometheus.Counter
-	emailsSentAttachment       prometheus.Counter
-	emailsSentAttachmentBytes  prometheus.Counter
-	emailsSentWithAttachment   prometheus.Counter
-	emailsResent               prometheus.Counter
-	emailsSentMaxRetries       prometheus.Counter
-	emailsSentTimeSeconds      prometheus.Histogram
+	templates                  *labelLimit
+	domains                    *labelLimit
+	emailsReceived             *prometheus.CounterVec
+	emailsReceivedBytes        *prometheus.CounterVec
+	emailsSent                 *prometheus.CounterVec
+	emailsSentDomain           *prometheus.CounterVec
+	emailsSentBytes            *prometheus.CounterVec
+	emailsSentAttachment       *prometheus.CounterVec
+	emailsSentAttachmentBytes  *prometheus.CounterVec
+	emailsSentWithAttachment   *prometheus.CounterVec
+	emailsResent               *prometheus.CounterVec
+	emailsSentMaxRetries       *prometheus.CounterVec
+	emailsDuplicated           *prometheus.CounterVec
+	emailsSentTimeSeconds      *prometheus.HistogramVec
+	emailsCache                *prometheus.GaugeVec
+	emailsCacheBytes           *prometheus.GaugeVec
 	emailsCacheAttachment      prometheus.Gauge
 	emailsCacheAttachmentBytes prometheus.Gauge
+	emailsCacheHits            *prometheus.CounterVec
+	emailsCacheMisses          *prometheus.CounterVec
+	emailsCacheEvictions       *prometheus.CounterVec
+	emailsCacheCollisions      *prometheus.CounterVec
+	emailsMinioFetch           *prometheus.CounterVec
+	emailsMinioFetchBytes      *prometheus.CounterVec
+	emailsMinioFetchSeconds    *prometheus.HistogramVec
+	emailsMinioFetchErrors     *prometheus.CounterVec
 }
 
-func newMetrics() *metrics {
+func newMetrics(configs *metricsConfig) *metrics {
 	return &metrics{
-		emailsReceived: prometheus.NewCounter(prometheus.CounterOpts{
+		templates: newLabelLimit(configs.MaxTemplates),
+		domains:   newLabelLimit(configs.MaxDomains),
+		emailsReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "emails_recebidos",
 			Help: "A quantidade de emails recebidos pela fila do rabbit",
-		}),
-		emailsReceivedBytes: prometheus.NewCounter(prometheus.CounterOpts{
+		}, []string{"queue"}),
+		emailsReceivedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "emails_recebidos_bytes",
 			Help: "A quantidade em bytes de emails recebidos pela fila do rebbit",
-		}),
-		emailsSent: prometheus.NewCounter(prometheus.CounterOpts{
+		}, []string{"queue"}),
+		emailsSent: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "emails_enviados",
 			Help: "A quantidade de emails enviados com sucesso",
-		}),
-		emailsSentBytes: prometheus.NewCounter(prometheus.CounterOpts{
+		}, []string{"queue", "template"}),
+		emailsSentDomain: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "emails_enviados_dominio",
+			Help: "A quantidade de emails enviados com sucesso por domínio dos destinatários",
+		}, []string{"queue", "domain"}),
+		emailsSentBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "emails_enviados_bytes",
 			Help: "A quantidade em bytes de emails enviados com sucesso",
-		}),
-		emailsSentAttachment: prometheus.NewCounter(prometheus.CounterOpts{
+		}, []string{"queue"}),
+		emailsSentAttachment: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "emails_anexos_enviados",
 			Help: "A quantidade de anexos enviados",
-		}),
-		emailsSentAttachmentBytes: prometheus.NewCounter(prometheus.CounterOpts{
+		}, []string{"queue"}),
+		emailsSentAttachmentBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "emails_anexos_enviados_bytes",
 			Help: "A quantidade em bytes de anexos enviados com sucesso",
-		}),
-		emailsSentWithAttachment: prometheus.NewCounter(prometheus.CounterOpts{
+		}, []string{"queue"}),
+		emailsSentWithAttachment: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "emails_enviados_com_anexo",
 			Help: "A quantidade de emails enviados com sucesso e com anexo",
-		}),
-		emailsResent: prometheus.NewCounter(prometheus.CounterOpts{
+		}, []string{"queue"}),
+		emailsResent: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "emails_reenviados",
 			Help: "A quantidade de emails reeenviados para a fila do rabbit",
-		}),
-		emailsSentMaxRetries: prometheus.NewCounter(prometheus.CounterOpts{
+		}, []string{"queue", "template", "error"}),
+		emailsSentMaxRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "emails_tentativas_maximas",
 			Help: "A quantidade de emails enviados para a fila dos mortos",
-		}),
-		emailsSentTimeSeconds: prometheus.NewHistogram(prometheus.HistogramOpts{
+		}, []string{"queue", "template", "error"}),
+		emailsDuplicated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "emails_duplicados",
+			Help: "A quantidade de emails ignorados por já terem sido enviados",
+		}, []string{"queue"}),
+		emailsSentTimeSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Name: "emails_tempo_de_envio_segundos",
 			Help: "O tempo de envio de lotes de emails em segundos",
-		}),
+		}, []string{"queue"}),
+		emailsCache: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "emails_cache",
+			Help: "A quantidade de arquivos em cada cache",
+		}, []string{"cache"}),
+		emailsCacheBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "emails_cache_bytes",
+			Help: "A quantidade em bytes de arquivos em cada cache",
+		}, []string{"cache"}),
+		// Deprecated: the attachment cache is on emails_cache and
+		// emails_cache_bytes, the old names are kept for one release.
 		emailsCacheAttachment: prometheus.NewGauge(prometheus.GaugeOpts{
 			Name: "emails_cache_anexo",
-			Help: "A quantidade de anexos no cache",
+			Help: "A quantidade de anexos no cache, obsoleta: use emails_cache",
 		}),
 		emailsCacheAttachmentBytes: prometheus.NewGauge(prometheus.GaugeOpts{
 			Name: "emails_cache_anexo_bytes",
-			Help: "A quantidade em bytes de anexos no cache",
+			Help: "A quantidade em bytes de anexos no cache, obsoleta: use emails_cache_bytes",
 		}),
+		emailsCacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "emails_cache_acertos",
+			Help: "A quantidade de arquivos encontrados em cada camada do cache",
+		}, []string{"cache", "tier"}),
+		emailsCacheMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "emails_cache_falhas",
+			Help: "A quantidade de arquivos não encontrados em cada camada do cache",
+		}, []string{"cache", "tier"}),
+		emailsCacheEvictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "emails_cache_remocoes",
+			Help: "A quantidade de arquivos removidos da memória de cada cache",
+		}, []string{"cache", "reason"}),
+		emailsCacheCollisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "emails_cache_colisoes",
+			Help: "A quantidade de colisões de chaves na memória de cada cache",
+		}, []string{"cache"}),
+		emailsMinioFetch: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "emails_minio_buscas",
+			Help: "A quantidade de buscas de arquivos no Minio",
+		}, []string{"cache"}),
+		emailsMinioFetchBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "emails_minio_busca_bytes",
+			Help: "A quantidade em bytes de arquivos buscados no Minio",
+		}, []string{"cache"}),
+		emailsMinioFetchSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "emails_minio_busca_segundos",
+			Help: "O tempo de busca de arquivos no Minio em segundos",
+		}, []string{"cache"}),
+		emailsMinioFetchErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "emails_minio_busca_erros",
+			Help: "A quantidade de erros ao buscar arquivos no Minio",
+		}, []string{"cache", "error"}),
+	}
+}
+
+func (metrics *metrics) cacheHit(cache, tier string) {
+	metrics.emailsCacheHits.WithLabelValues(cache, tier).Inc()
+}
+
+func (metrics *metrics) cacheMiss(cache, tier string) {
+	metrics.emailsCacheMisses.WithLabelValues(cache, tier).Inc()
+}
+
+func (metrics *metrics) cacheEviction(cache string, reason bigcache.RemoveReason) {
+	label := "deleted"
+
+	switch reason {
+	case bigcache.Expired:
+		label = "expired"
+	case bigcache.NoSpace:
+		label = "no_space"
 	}
+
+	metrics.emailsCacheEvictions.WithLabelValues(cache, label).Inc()
+}
+
+func (metrics *metrics) minioFetch(cache string, duration time.Duration, bytes int64) {
+	metrics.emailsMinioFetch.WithLabelValues(cache).Inc()
+	metrics.emailsMinioFetchBytes.WithLabelValues(cache).Add(float64(bytes))
+	metrics.emailsMinioFetchSeconds.WithLabelValues(cache).Observe(duration.Seconds())
+}
+
+func (metrics *metrics) minioFetchError(cache, class string) {
+	metrics.emailsMinioFetchErrors.WithLabelValues(cache, class).Inc()
 }
 
-func serverMetrics(metrics *metrics) {
+func serverMetrics(metrics *metrics, health *health) {
 	const (
 		serverWriteTimeout = 10 * time.Second
 		serverReadTImeout  = 5 * time.Second
@@ -92,21 +223,36 @@ func serverMetrics(metrics *metrics) {
 		metrics.emailsReceived,
 		metrics.emailsReceivedBytes,
 		metrics.emailsSent,
+		metrics.emailsSentDomain,
 		metrics.emailsSentBytes,
 		metrics.emailsSentAttachment,
 		metrics.emailsSentAttachmentBytes,
 		metrics.emailsSentWithAttachment,
 		metrics.emailsResent,
 		metrics.emailsSentMaxRetries,
+		metrics.emailsDuplicated,
 		metrics.emailsSentTimeSeconds,
+		metrics.emailsCache,
+		metrics.emailsCacheBytes,
 		metrics.emailsCacheAttachment,
 		metrics.emailsCacheAttachmentBytes,
+		metrics.emailsCacheHits,
+		metrics.emailsCacheMisses,
+		metrics.emailsCacheEvictions,
+		metrics.emailsCacheCollisions,
+		metrics.emailsMinioFetch,
+		metrics.emailsMinioFetchBytes,
+		metrics.emailsMinioFetchSeconds,
+		metrics.emailsMinioFetchErrors,
 	)
 
 	http.Handle("/metrics", promhttp.HandlerFor(registryMetrics, promhttp.HandlerOpts{
 		EnableOpenMetrics: true,
 	}))
 
+	http.HandleFunc("/health/live", health.handleLive)
+	http.HandleFunc("/health/ready", health.handleReady)
+
 	server := &http.Server{
 		WriteTimeout: serverWriteTimeout,
 		ReadTimeout:  serverReadTImeout,
@@ -119,11 +265,32 @@ func serverMetrics(metrics *metrics) {
 	}
 }
 
-func cacheMetrics(cache *cache, metrics *metrics) {
+// cacheMetrics publishes the bigcache statistics, which are cumulative, as
+// the difference from the last read.
+func cacheMetrics(metrics *metrics, caches ...*cache) {
 	ticker := time.NewTicker(time.Second)
+	previous := make([]bigcache.Stats, len(caches))
 
 	for range ticker.C {
-		metrics.emailsCacheAttachment.Set(float64(cache.data.Len()))
-		metrics.emailsCacheAttachmentBytes.Set(float64(cache.data.Capacity()))
+		for index, cache := range caches {
+			stats := cache.data.Stats()
+
+			metrics.emailsCache.WithLabelValues(cache.name).Set(float64(cache.data.Len()))
+			metrics.emailsCacheBytes.WithLabelValues(cache.name).Set(float64(cache.data.Capacity()))
+
+			if cache.name == "attachment" {
+				metrics.emailsCacheAttachment.Set(float64(cache.data.Len()))
+				metrics.emailsCacheAttachmentBytes.Set(float64(cache.data.Capacity()))
+			}
+
+			metrics.emailsCacheHits.WithLabelValues(cache.name, tierMemory).
+				Add(float64(stats.Hits - previous[index].Hits))
+			metrics.emailsCacheMisses.WithLabelValues(cache.name, tierMemory).
+				Add(float64(stats.Misses - previous[index].Misses))
+			metrics.emailsCacheCollisions.WithLabelValues(cache.name).
+				Add(float64(stats.Collisions - previous[index].Collisions))
+
+			previous[index] = stats
+		}
 	}
 }
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/thiago-felipe-99/mail/rabbit"
+)
+
+var (
+	errDuplicatedQueue = errors.New("queue is duplicated on queues file")
+	errShutdownTimeout = errors.New("timeout waiting the emails being sent")
+)
+
+// queueConfig are the settings of one queue, the zero values are replaced by
+// the global configurations.
+type queueConfig struct {
+	Name       string  `json:"name"       validate:"required"`
+	DLX        string  `json:"dlx"`
+	MaxRetries int64   `json:"maxRetries" validate:"min=0"`
+	Priority   bool    `json:"priority"`
+	Buffer     buffer  `json:"buffer"     validate:"-"`
+	Timeout    int     `json:"timeout"    validate:"min=0"`
+	Sender     *sender `json:"sender"     validate:"omitempty"`
+	SMTP       *smtp   `json:"smtp"       validate:"omitempty"`
+}
+
+func readQueuesFile(path string) (map[string]queueConfig, error) {
+	overrides := map[string]queueConfig{}
+
+	if path == "" {
+		return overrides, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading queues file: %w", err)
+	}
+
+	configs := []queueConfig{}
+
+	err = json.Unmarshal(data, &configs)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding queues file: %w", err)
+	}
+
+	validate := validator.New()
+
+	for _, config := range configs {
+		err := validate.Struct(config)
+		if err != nil {
+			return nil, fmt.Errorf("error on validating queue '%s': %w", config.Name, err)
+		}
+
+		if _, exist := overrides[config.Name]; exist {
+			return nil, fmt.Errorf("%w: %s", errDuplicatedQueue, config.Name)
+		}
+
+		overrides[config.Name] = config
+	}
+
+	return overrides, nil
+}
+
+// queue is a queue being consumed, discovered queues are not declared because
+// they could be recreated after been deleted.
+type queue struct {
+	queueConfig
+	send      *send
+	messages  chan rabbit.Message
+	declare   bool
+	stop      chan struct{}
+	consuming atomic.Bool
+	changedAt atomic.Int64
+}
+
+func (queue *queue) setConsuming(consuming bool) {
+	queue.consuming.Store(consuming)
+	queue.changedAt.Store(time.Now().UnixNano())
+}
+
+type queues struct {
+	rabbit    *rabbit.Rabbit
+	configs   *configurations
+	overrides map[string]queueConfig
+	pattern   *regexp.Regexp
+	cache     *cache
+	template  *cache
+	metrics   *metrics
+	inFlight  *inFlight
+	seen      *seen
+	status    chan sendStatus
+	running   map[string]*queue
+	closed    bool
+	consumers sync.WaitGroup
+	batches   sync.WaitGroup
+	mutex     sync.Mutex
+}
+
+func newQueues(
+	rabbit *rabbit.Rabbit,
+	configs *configurations,
+	cache *cache,
+	template *cache,
+	metrics *metrics,
+) (*queues, error) {
+	overrides, err := readQueuesFile(configs.Rabbit.QueuesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var pattern *regexp.Regexp
+
+	if configs.Rabbit.Pattern != "" {
+		pattern, err = regexp.Compile(configs.Rabbit.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling queues pattern: %w", err)
+		}
+	}
+
+	return &queues{
+		rabbit:    rabbit,
+		configs:   configs,
+		overrides: overrides,
+		pattern:   pattern,
+		cache:     cache,
+		template:  template,
+		metrics:   metrics,
+		inFlight:  newInFlight(int64(configs.Attachment.MaxInFlightSize) * megabyte),
+		seen:      newSeen(configs.Dedup.MaxIDs),
+		status:    make(chan sendStatus),
+		running:   map[string]*queue{},
+	}, nil
+}
+
+// config merges the queue overrides with the global configurations.
+func (queues *queues) config(name string, dlx string, priority bool) queueConfig {
+	config := queueConfig{
+		Name:       name,
+		DLX:        dlx,
+		MaxRetries: queues.configs.Rabbit.MaxRetries,
+		Priority:   priority || queues.configs.Rabbit.Priority,
+		Buffer:     queues.configs.Buffer,
+		Timeout:    queues.configs.Timeout,
+		Sender:     &queues.configs.Sender,
+		SMTP:       &queues.configs.SMTP,
+	}
+
+	override, exist := queues.overrides[name]
+	if !exist {
+		return config
+	}
+
+	if override.DLX != "" {
+		config.DLX = override.DLX
+	}
+
+	if override.MaxRetries > 0 {
+		config.MaxRetries = override.MaxRetries
+	}
+
+	if override.Priority {
+		config.Priority = true
+	}
+
+	if override.Buffer.Size > 0 {
+		config.Buffer.Size = override.Buffer.Size
+	}
+
+	if override.Buffer.Quantity > 0 {
+		config.Buffer.Quantity = override.Buffer.Quantity
+	}
+
+	if override.Timeout > 0 {
+		config.Timeout = override.Timeout
+	}
+
+	if override.Sender != nil {
+		config.Sender = override.Sender
+	}
+
+	if override.SMTP != nil {
+		config.SMTP = override.SMTP
+	}
+
+	return config
+}
+
+func (queues *queues) start(name string, dlx string, priority bool, declare bool) {
+	queues.mutex.Lock()
+	defer queues.mutex.Unlock()
+
+	if _, exist := queues.running[name]; exist || queues.closed {
+		return
+	}
+
+	config := queues.config(name, dlx, priority)
+
+	queue := &queue{
+		queueConfig: config,
+		send: newSend(
+			queues.cache,
+			queues.template,
+			config.Sender,
+			config.SMTP,
+			queues.metrics,
+			queues.inFlight,
+			queues.status,
+			&queues.batches,
+			queues.seen,
+			name,
+			config.MaxRetries,
+		),
+		messages: make(chan rabbit.Message),
+		declare:  declare,
+		stop:     make(chan struct{}),
+	}
+
+	queue.setConsuming(false)
+
+	queues.running[name] = queue
+
+	log.Printf("[INFO] - Starting the consumer of '%s'", name)
+
+	go consumeMessages(queues.rabbit, queue)
+
+	queues.consumers.Add(1)
+
+	go func() {
+		defer queues.consumers.Done()
+
+		getMessages(
+			queue.messages,
+			queue.send,
+			time.Duration(config.Timeout)*time.Second,
+			config.Buffer.Size,
+		)
+	}()
+}
+
+// stop cancels the consumer of the queue, the messages already delivered are
+// still sent before the channel is closed.
+func (queues *queues) stop(name string) {
+	queues.mutex.Lock()
+	defer queues.mutex.Unlock()
+
+	queue, exist := queues.running[name]
+	if !exist {
+		return
+	}
+
+	log.Printf("[INFO] - Stopping the consumer of '%s'", name)
+
+	close(queue.stop)
+	delete(queues.running, name)
+}
+
+// dlxs returns the DLX of the queues that already exist, it is empty when the
+// management API was not configured.
+func (queues *queues) dlxs() map[string]string {
+	dlxs := map[string]string{}
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(queues.configs.Rabbit.DiscoveryInterval)*time.Second,
+	)
+	defer cancel()
+
+	infos, err := queues.rabbit.ListQueues(ctx)
+	if err != nil {
+		if !errors.Is(err, rabbit.ErrNoManagement) {
+			log.Printf("[ERROR] - Error getting the DLX of the queues: %s", err)
+		}
+
+		return dlxs
+	}
+
+	for _, info := range infos {
+		if info.HasDLX() {
+			dlxs[info.Name] = info.DLX()
+		}
+	}
+
+	return dlxs
+}
+
+// startConfigured starts the queues that were set on the configurations, the
+// queues that already exist keep their DLX.
+func (queues *queues) startConfigured() {
+	if queues.configs.Rabbit.Queue != "" {
+		queues.start(queues.configs.Rabbit.Queue, queues.configs.Rabbit.QueueDLX, false, true)
+	}
+
+	dlxs := queues.dlxs()
+
+	dlx := func(name string) string {
+		if dlx, exist := dlxs[name]; exist {
+			return dlx
+		}
+
+		return name + "-dlx"
+	}
+
+	for _, name := range strings.Split(queues.configs.Rabbit.Queues, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			queues.start(name, dlx(name), false, true)
+		}
+	}
+
+	for name := range queues.overrides {
+		queues.start(name, dlx(name), false, true)
+	}
+}
+
+// discover starts the queues that match the pattern and stops the ones that
+// were deleted, a queue with a high priority queue is consumed as a pair.
+func (queues *queues) discover() {
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(queues.configs.Rabbit.DiscoveryInterval)*time.Second,
+	)
+	defer cancel()
+
+	infos, err := queues.rabbit.ListQueues(ctx)
+	if err != nil {
+		log.Printf("[ERROR] - Error discovering queues: %s", err)
+
+		return
+	}
+
+	names := map[string]string{}
+
+	for _, info := range infos {
+		if info.HasDLX() {
+			names[info.Name] = info.DLX()
+		}
+	}
+
+	found := map[string]struct{}{}
+
+	for name, dlx := range names {
+		if !queues.pattern.MatchString(name) {
+			continue
+		}
+
+		base, isHigh := strings.CutSuffix(name, rabbit.HighPriorityQueue(""))
+		if _, exist := names[base]; isHigh && exist {
+			continue
+		}
+
+		_, priority := names[rabbit.HighPriorityQueue(name)]
+
+		found[name] = struct{}{}
+
+		queues.start(name, dlx, priority, false)
+	}
+
+	deleted := []string{}
+
+	queues.mutex.Lock()
+
+	for name, queue := range queues.running {
+		if _, exist := found[name]; !exist && !queue.declare {
+			deleted = append(deleted, name)
+		}
+	}
+
+	queues.mutex.Unlock()
+
+	for _, name := range deleted {
+		queues.stop(name)
+	}
+}
+
+func (queues *queues) discoverForever(ctx context.Context) {
+	if queues.pattern == nil {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(queues.configs.Rabbit.DiscoveryInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		queues.discover()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// shutdown stops consuming all queues, sends the buffered messages and waits
+// the emails being sent until the timeout.
+func (queues *queues) shutdown(timeout time.Duration) error {
+	queues.mutex.Lock()
+	queues.closed = true
+
+	names := make([]string, 0, len(queues.running))
+	for name := range queues.running {
+		names = append(names, name)
+	}
+
+	queues.mutex.Unlock()
+
+	for _, name := range names {
+		queues.stop(name)
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		queues.consumers.Wait()
+		queues.batches.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		close(queues.status)
+
+		return nil
+	case <-time.After(timeout):
+		return errShutdownTimeout
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+)
+
+// seen is a bounded set of the IDs of the messages that were sent, the oldest
+// IDs are forgotten first. The IDs of the messages being sent are reserved, so
+// the batches sending at the same time do not send the same message.
+type seen struct {
+	maxSize  int
+	ids      *list.List
+	index    map[string]*list.Element
+	inFlight map[string]struct{}
+	mutex    sync.Mutex
+}
+
+func newSeen(maxSize int) *seen {
+	return &seen{
+		maxSize:  maxSize,
+		ids:      list.New(),
+		index:    map[string]*list.Element{},
+		inFlight: map[string]struct{}{},
+	}
+}
+
+// reserve reports if the ID was reserved, it is not when the message was
+// already sent or is being sent.
+func (seen *seen) reserve(id string) bool {
+	seen.mutex.Lock()
+	defer seen.mutex.Unlock()
+
+	if _, exist := seen.index[id]; exist {
+		return false
+	}
+
+	if _, exist := seen.inFlight[id]; exist {
+		return false
+	}
+
+	seen.inFlight[id] = struct{}{}
+
+	return true
+}
+
+// release forgets the reserved ID of the message that was not sent.
+func (seen *seen) release(id string) {
+	seen.mutex.Lock()
+	defer seen.mutex.Unlock()
+
+	delete(seen.inFlight, id)
+}
+
+// add marks the reserved ID as sent.
+func (seen *seen) add(id string) {
+	seen.mutex.Lock()
+	defer seen.mutex.Unlock()
+
+	delete(seen.inFlight, id)
+
+	if _, exist := seen.index[id]; exist {
+		return
+	}
+
+	seen.index[id] = seen.ids.PushFront(id)
+
+	for seen.ids.Len() > seen.maxSize {
+		oldest, _ := seen.ids.Remove(seen.ids.Back()).(string)
+		delete(seen.index, oldest)
+	}
+}
@@ -3,21 +3,64 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	htmltemplate "html/template"
 	"log"
+	"net"
+	"os"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/russross/blackfriday/v2"
 	"github.com/thiago-felipe-99/mail/rabbit"
 	"github.com/wneessen/go-mail"
+	mailsmtp "github.com/wneessen/go-mail/smtp"
+	"golang.org/x/sync/semaphore"
 )
 
-var errKeyDontExist = errors.New("key dont exist")
+var (
+	errKeyDontExist         = errors.New("key dont exist")
+	errSMTPWithoutTLS       = errors.New("SMTP server does not support STARTTLS")
+	errSMTPWithoutPlainAuth = errors.New("SMTP server does not support PLAIN authentication")
+	errSMTPBroken           = errors.New("SMTP connection is broken")
+)
+
+// smtpTimeout is the timeout of the connection and how long the connection
+// can be idle, it is reset on each read and write, so large emails are not
+// limited by it.
+const smtpTimeout = 15 * time.Second
+
+// idleConn extends the deadline of the connection before each read and write.
+type idleConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (conn *idleConn) Read(bytes []byte) (int, error) {
+	err := conn.Conn.SetDeadline(time.Now().Add(conn.timeout))
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return conn.Conn.Read(bytes) //nolint:wrapcheck
+}
+
+func (conn *idleConn) Write(bytes []byte) (int, error) {
+	err := conn.Conn.SetDeadline(time.Now().Add(conn.timeout))
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return conn.Conn.Write(bytes) //nolint:wrapcheck
+}
 
 type receiver struct {
 	Name  string `json:"name"`
@@ -36,11 +79,22 @@ type email struct {
 	Message         string     `json:"message"`
 	Template        template   `json:"template"`
 	Attachments     []string   `json:"attachments"`
+	Unsubscribe     string     `json:"unsubscribe"`
 	contentType     mail.ContentType
+	attachments     []attachment
 	attachmentsSize int
 	messageQueue    rabbit.Message
 	messageMail     *mail.Msg
+	reserved        bool
 	error           error
+	errorClass      string
+}
+
+// attachment is written to the SMTP server when the message is sent, so the
+// batch does not hold all attachments in memory.
+type attachment struct {
+	name string
+	size int64
 }
 
 type errorQuantity struct {
@@ -49,19 +103,55 @@ type errorQuantity struct {
 }
 
 type sendStatus struct {
+	queue        string
 	successfully int
+	duplicated   int
 	failed       int
 	errors       []errorQuantity
 }
 
+// inFlight bounds the attachments bytes that are being written at the same
+// time by all batches.
+type inFlight struct {
+	bytes *semaphore.Weighted
+	max   int64
+}
+
+func newInFlight(maxBytes int64) *inFlight {
+	return &inFlight{
+		bytes: semaphore.NewWeighted(maxBytes),
+		max:   maxBytes,
+	}
+}
+
+// acquire waits the attachments bytes, an email bigger than the max waits all
+// bytes.
+func (inFlight *inFlight) acquire(size int64) (func(), error) {
+	if size > inFlight.max {
+		size = inFlight.max
+	}
+
+	err := inFlight.bytes.Acquire(context.Background(), size)
+	if err != nil {
+		return nil, fmt.Errorf("error waiting attachment bytes in flight: %w", err)
+	}
+
+	return func() { inFlight.bytes.Release(size) }, nil
+}
+
 type send struct {
 	*cache
 	templateCache *cache
 	*sender
 	*metrics
 	*smtp
+	inFlight  *inFlight
 	status    chan sendStatus
+	batches   *sync.WaitGroup
+	seen      *seen
+	queue     string
 	maxReties int64
+	lastSent  atomic.Int64
 }
 
 func newSend(
@@ -70,6 +160,11 @@ func newSend(
 	sender *sender,
 	smtp *smtp,
 	metrics *metrics,
+	inFlight *inFlight,
+	status chan sendStatus,
+	batches *sync.WaitGroup,
+	seen *seen,
+	queue string,
 	maxReties int64,
 ) *send {
 	return &send{
@@ -78,7 +173,11 @@ func newSend(
 		sender:        sender,
 		metrics:       metrics,
 		smtp:          smtp,
-		status:        make(chan sendStatus),
+		inFlight:      inFlight,
+		status:        status,
+		batches:       batches,
+		seen:          seen,
+		queue:         queue,
 		maxReties:     maxReties,
 	}
 }
@@ -95,6 +194,7 @@ func proccessQueue(queue []rabbit.Message) ([]email, []email) {
 		err := json.Unmarshal(message.Body, &email)
 		if err != nil {
 			email.error = fmt.Errorf("error converting a message to an email: %w", err)
+			email.errorClass = "decode"
 			failed = append(failed, email)
 		} else {
 			ready = append(ready, email)
@@ -158,6 +258,7 @@ func proccessEmailsTemplate(cache *cache, ready, failed []email) ([]email, []ema
 		message, err := getTemplateHTML(ready[index].Template, cache)
 		if err != nil {
 			ready[index].error = err
+			ready[index].errorClass = "template"
 			ready, failed = emailFailed(index, ready, failed)
 		} else {
 			ready[index].contentType = "text/html"
@@ -168,54 +269,75 @@ func proccessEmailsTemplate(cache *cache, ready, failed []email) ([]email, []ema
 	return ready, failed
 }
 
-func createMessageMail(cache *cache, sender *sender, email email) (*mail.Msg, int, error) {
+func createMessageMail(
+	cache *cache,
+	sender *sender,
+	email email,
+) (*mail.Msg, []attachment, int, error) {
 	message := mail.NewMsg()
 	attachmentsSize := 0
 
 	err := message.EnvelopeFromFormat(sender.Name, sender.Email)
 	if err != nil {
-		return nil, 0, fmt.Errorf("error adding email sender: %w", err)
+		return nil, nil, 0, fmt.Errorf("error adding email sender: %w", err)
 	}
 
 	for _, receiver := range email.Receivers {
 		err = message.AddToFormat(receiver.Name, receiver.Email)
 		if err != nil {
-			return nil, 0, fmt.Errorf("error adding email receiver: %w", err)
+			return nil, nil, 0, fmt.Errorf("error adding email receiver: %w", err)
 		}
 	}
 
 	for _, receiver := range email.BlindReceivers {
 		err = message.AddBccFormat(receiver.Name, receiver.Email)
 		if err != nil {
-			return nil, 0, fmt.Errorf("error adding email blind receiver: %w", err)
+			return nil, nil, 0, fmt.Errorf("error adding email blind receiver: %w", err)
 		}
 	}
 
-	for _, attachment := range email.Attachments {
-		file, err := cache.get(attachment)
+	attachments := make([]attachment, 0, len(email.Attachments))
+
+	for _, name := range email.Attachments {
+		size, err := cache.size(name)
 		if err != nil {
-			return nil, 0, fmt.Errorf("error getting attachment from cache: %w", err)
+			return nil, nil, 0, fmt.Errorf("error getting attachment size: %w", err)
 		}
 
-		attachmentsSize += len(file)
-		message.AttachReadSeeker(attachment, bytes.NewReader(file))
+		attachmentsSize += int(size)
+		attachments = append(attachments, attachment{name: name, size: size})
 	}
 
 	message.Subject(email.Subject)
 
+	// The email ID on the headers correlates the bounces with the email.
+	if id := email.messageQueue.MessageId; id != "" {
+		_, domain, _ := strings.Cut(sender.Email, "@")
+
+		message.SetMessageIDWithValue(id + "@" + domain)
+		message.SetGenHeader("X-Email-ID", id)
+	}
+
+	if email.Unsubscribe != "" {
+		message.SetGenHeader("List-Unsubscribe", "<"+email.Unsubscribe+">")
+		message.SetGenHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+
 	message.SetBodyString(email.contentType, email.Message)
 
-	return message, attachmentsSize, nil
+	return message, attachments, attachmentsSize, nil
 }
 
 func proccessEmails(cache *cache, sender *sender, ready, failed []email) ([]email, []email) {
 	for index := len(ready) - 1; index >= 0; index-- {
-		message, attachmentsSize, err := createMessageMail(cache, sender, ready[index])
+		message, attachments, attachmentsSize, err := createMessageMail(cache, sender, ready[index])
 		if err != nil {
 			ready[index].error = err
+			ready[index].errorClass = "message"
 			ready, failed = emailFailed(index, ready, failed)
 		} else {
 			ready[index].messageMail = message
+			ready[index].attachments = attachments
 			ready[index].attachmentsSize = attachmentsSize
 		}
 	}
@@ -223,59 +345,218 @@ func proccessEmails(cache *cache, sender *sender, ready, failed []email) ([]emai
 	return ready, failed
 }
 
-func emailFailedUniqErr(err error, ready, failed []email) []email {
+func emailFailedUniqErr(err error, class string, ready, failed []email) []email {
 	for _, email := range ready {
 		email.error = err
+		email.errorClass = class
 		failed = append(failed, email)
 	}
 
 	return failed
 }
 
-func sendEmails(smtp *smtp, ready, failed []email) ([]email, []email) {
-	clientOption := []mail.Option{
-		mail.WithPort(smtp.Port),
-		mail.WithSMTPAuth(mail.SMTPAuthPlain),
-		mail.WithUsername(smtp.User),
-		mail.WithPassword(smtp.Password),
-		mail.WithTLSPolicy(mail.TLSMandatory),
+func dialSMTP(config *smtp) (*mailsmtp.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), smtpTimeout)
+	defer cancel()
+
+	dialer := net.Dialer{}
+
+	connection, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(config.Host, strconv.Itoa(config.Port)))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to SMTP server: %w", err)
+	}
+
+	client, err := mailsmtp.NewClient(&idleConn{Conn: connection, timeout: smtpTimeout}, config.Host)
+	if err != nil {
+		connection.Close()
+
+		return nil, fmt.Errorf("error creating SMTP client: %w", err)
+	}
+
+	err = dialSMTPSession(client, config)
+	if err != nil {
+		client.Close()
+
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// dialSMTPSession requires STARTTLS before the plain authentication.
+func dialSMTPSession(client *mailsmtp.Client, config *smtp) error {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("error getting hostname: %w", err)
 	}
 
-	client, err := mail.NewClient(smtp.Host, clientOption...)
+	err = client.Hello(hostname)
 	if err != nil {
-		return []email{}, emailFailedUniqErr(err, ready, failed)
+		return fmt.Errorf("error greeting SMTP server: %w", err)
 	}
 
-	messages := []*mail.Msg{}
-	for _, email := range ready {
-		messages = append(messages, email.messageMail)
+	if supported, _ := client.Extension("STARTTLS"); !supported {
+		return errSMTPWithoutTLS
+	}
+
+	err = client.StartTLS(&tls.Config{ServerName: config.Host, MinVersion: tls.VersionTLS12})
+	if err != nil {
+		return fmt.Errorf("error starting SMTP TLS: %w", err)
+	}
+
+	if supported, auths := client.Extension("AUTH"); !supported || !strings.Contains(auths, "PLAIN") {
+		return errSMTPWithoutPlainAuth
+	}
+
+	err = client.Auth(mailsmtp.PlainAuth("", config.User, config.Password, config.Host))
+	if err != nil {
+		return fmt.Errorf("error authenticating on SMTP server: %w", err)
+	}
+
+	return nil
+}
+
+// resetSMTP resets the transaction after the error, the connection is broken
+// when the reset fails.
+func resetSMTP(client *mailsmtp.Client, err error) error {
+	errReset := client.Reset()
+	if errReset != nil {
+		return errors.Join(err, errSMTPBroken, errReset)
 	}
 
-	err = client.DialWithContext(context.Background())
+	return err
+}
+
+// sendEmail holds the attachments bytes until the message is flushed to the
+// SMTP server. The connection is broken after an error writing the data, the
+// server may have received part of the message.
+func sendEmail(client *mailsmtp.Client, cache *cache, inFlight *inFlight, email *email) error {
+	from, err := email.messageMail.GetSender(false)
 	if err != nil {
-		return []email{}, emailFailedUniqErr(err, ready, failed)
+		return fmt.Errorf("error getting email sender: %w", err)
 	}
 
-	err = client.Send(messages...)
+	receivers, err := email.messageMail.GetRecipients()
 	if err != nil {
-		for index := len(ready) - 1; index >= 0; index-- {
-			if ready[index].messageMail.HasSendError() {
-				ready[index].error = ready[index].messageMail.SendError()
-				ready, failed = emailFailed(index, ready, failed)
+		return fmt.Errorf("error getting email receivers: %w", err)
+	}
+
+	err = client.Mail(from)
+	if err != nil {
+		return resetSMTP(client, fmt.Errorf("error sending email sender: %w", err))
+	}
+
+	for _, receiver := range receivers {
+		err = client.Rcpt(receiver)
+		if err != nil {
+			return resetSMTP(client, fmt.Errorf("error sending email receiver: %w", err))
+		}
+	}
+
+	release, err := inFlight.acquire(int64(email.attachmentsSize))
+	if err != nil {
+		return resetSMTP(client, err)
+	}
+
+	defer release()
+
+	writer, err := client.Data()
+	if err != nil {
+		return resetSMTP(client, fmt.Errorf("error starting email data: %w", err))
+	}
+
+	err = writeMessage(writer, cache, email)
+	if err != nil {
+		return errors.Join(err, errSMTPBroken)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return errors.Join(fmt.Errorf("error sending email data: %w", err), errSMTPBroken)
+	}
+
+	return nil
+}
+
+// sendEmails drops the broken connections and sends the remaining emails on a
+// new connection.
+func sendEmails(smtp *smtp, cache *cache, inFlight *inFlight, ready, failed []email) ([]email, []email) {
+	var client *mailsmtp.Client
+
+	defer func() {
+		if client != nil {
+			client.Quit() //nolint:errcheck
+		}
+	}()
+
+	for index := len(ready) - 1; index >= 0; index-- {
+		if client == nil {
+			var err error
+
+			client, err = dialSMTP(smtp)
+			if err != nil {
+				failed = emailFailedUniqErr(err, "smtp", ready[:index+1], failed)
+
+				return ready[index+1:], failed
 			}
 		}
 
-		return ready, failed
+		err := sendEmail(client, cache, inFlight, &ready[index])
+		if err != nil {
+			if errors.Is(err, errSMTPBroken) {
+				client.Close()
+
+				client = nil
+			}
+
+			ready[index].error = err
+			ready[index].errorClass = "smtp"
+			ready, failed = emailFailed(index, ready, failed)
+		}
 	}
 
 	return ready, failed
 }
 
+// skipDuplicated acknowledges the emails that were already sent or are being
+// sent, the message ID is set by the publisher. The IDs of the unique emails
+// are reserved until they are sent or failed.
+func skipDuplicated(seen *seen, ready []email) ([]email, int) {
+	unique := make([]email, 0, len(ready))
+	duplicated := 0
+
+	for _, email := range ready {
+		id := email.messageQueue.MessageId
+		if id == "" {
+			unique = append(unique, email)
+
+			continue
+		}
+
+		if seen.reserve(id) {
+			email.reserved = true
+			unique = append(unique, email)
+
+			continue
+		}
+
+		duplicated++
+
+		err := email.messageQueue.Ack(false)
+		if err != nil {
+			log.Printf("[ERROR] - Error acknowledging duplicated message '%s': %s", id, err)
+		}
+	}
+
+	return unique, duplicated
+}
+
 func proccessAcknowledgment(ready, failed []email) ([]email, []email) {
 	for index := len(ready) - 1; index >= 0; index-- {
 		err := ready[index].messageQueue.Ack(false)
 		if err != nil {
 			ready[index].error = err
+			ready[index].errorClass = "ack"
 			ready, failed = emailFailed(index, ready, failed)
 		}
 	}
@@ -313,22 +594,52 @@ func proccessNotAcknowledgment(emails []email) []errorQuantity {
 	return errs
 }
 
-func setMetrics(metrics *metrics, timeInit time.Time, ready, failed []email, maxRetries int64) {
+func (email *email) templateName() string {
+	if email.Template.Name == "" {
+		return "none"
+	}
+
+	return email.Template.Name
+}
+
+func (email *email) domains() map[string]struct{} {
+	domains := map[string]struct{}{}
+
+	receivers := append(append([]receiver{}, email.Receivers...), email.BlindReceivers...)
+	for _, receiver := range receivers {
+		index := strings.LastIndex(receiver.Email, "@")
+		if index >= 0 {
+			domains[strings.ToLower(receiver.Email[index+1:])] = struct{}{}
+		}
+	}
+
+	return domains
+}
+
+func setMetrics(
+	metrics *metrics,
+	queue string,
+	timeInit time.Time,
+	ready, failed []email,
+	maxRetries int64,
+) {
 	receivedBytes := 0
-	sentEmails := 0
 	sentBytes := 0
 	sentAttachment := 0
 	sentAttachmentsBytes := 0
 	sentWithAttachemnt := 0
-	sentMaxRetries := 0
 
 	for _, email := range failed {
 		receivedBytes += len(email.messageQueue.Body)
 
+		template := metrics.templates.value(email.templateName())
+
+		metrics.emailsResent.WithLabelValues(queue, template, email.errorClass).Inc()
+
 		if value, okay := email.messageQueue.Headers["x-delivery-count"]; okay {
 			if retries, okay := value.(int64); okay {
 				if retries >= maxRetries {
-					sentMaxRetries++
+					metrics.emailsSentMaxRetries.WithLabelValues(queue, template, email.errorClass).Inc()
 				}
 			}
 		}
@@ -337,7 +648,12 @@ func setMetrics(metrics *metrics, timeInit time.Time, ready, failed []email, max
 	for _, email := range ready {
 		receivedBytes += len(email.messageQueue.Body)
 		sentBytes += len(email.Message)
-		sentEmails++
+
+		metrics.emailsSent.WithLabelValues(queue, metrics.templates.value(email.templateName())).Inc()
+
+		for domain := range email.domains() {
+			metrics.emailsSentDomain.WithLabelValues(queue, metrics.domains.value(domain)).Inc()
+		}
 
 		attachmentsSize := len(email.Attachments)
 		if attachmentsSize > 0 {
@@ -347,43 +663,68 @@ func setMetrics(metrics *metrics, timeInit time.Time, ready, failed []email, max
 		}
 	}
 
-	metrics.emailsReceived.Add(float64(len(ready) + len(failed)))
-	metrics.emailsReceivedBytes.Add(float64(receivedBytes))
-	metrics.emailsSent.Add(float64(sentEmails))
-	metrics.emailsSentBytes.Add(float64(sentBytes))
-	metrics.emailsSentAttachment.Add(float64(sentAttachment))
-	metrics.emailsSentAttachmentBytes.Add(float64(sentAttachmentsBytes))
-	metrics.emailsSentWithAttachment.Add(float64(sentWithAttachemnt))
-	metrics.emailsResent.Add(float64(len(failed)))
-	metrics.emailsSentMaxRetries.Add(float64(sentMaxRetries))
-	metrics.emailsSentTimeSeconds.Observe(time.Since(timeInit).Seconds())
+	metrics.emailsReceived.WithLabelValues(queue).Add(float64(len(ready) + len(failed)))
+	metrics.emailsReceivedBytes.WithLabelValues(queue).Add(float64(receivedBytes))
+	metrics.emailsSentBytes.WithLabelValues(queue).Add(float64(sentBytes))
+	metrics.emailsSentAttachment.WithLabelValues(queue).Add(float64(sentAttachment))
+	metrics.emailsSentAttachmentBytes.WithLabelValues(queue).Add(float64(sentAttachmentsBytes))
+	metrics.emailsSentWithAttachment.WithLabelValues(queue).Add(float64(sentWithAttachemnt))
+	metrics.emailsSentTimeSeconds.WithLabelValues(queue).Observe(time.Since(timeInit).Seconds())
 }
 
 func (send *send) emails(queue []rabbit.Message) {
+	defer send.batches.Done()
+
 	timeInit := time.Now()
 
 	ready, failed := proccessQueue(queue)
+	ready, duplicated := skipDuplicated(send.seen, ready)
 	ready, failed = proccessEmailsTemplate(send.templateCache, ready, failed)
 	ready, failed = proccessEmails(send.cache, send.sender, ready, failed)
-	ready, failed = sendEmails(send.smtp, ready, failed)
+	ready, failed = sendEmails(send.smtp, send.cache, send.inFlight, ready, failed)
+
+	// The sent emails are seen even when the acknowledgment fails, so the
+	// redelivered messages are not sent again.
+	for _, email := range ready {
+		if email.messageQueue.MessageId != "" {
+			send.seen.add(email.messageQueue.MessageId)
+		}
+	}
+
 	ready, failed = proccessAcknowledgment(ready, failed)
 
+	for _, email := range failed {
+		if email.reserved {
+			send.seen.release(email.messageQueue.MessageId)
+		}
+	}
+
+	if len(ready) > 0 {
+		send.lastSent.Store(time.Now().UnixNano())
+	}
+
 	err := proccessNotAcknowledgment(failed)
 
 	send.status <- sendStatus{
+		queue:        send.queue,
 		successfully: len(ready),
+		duplicated:   duplicated,
 		failed:       len(failed),
 		errors:       err,
 	}
 
-	setMetrics(send.metrics, timeInit, ready, failed, send.maxReties)
+	send.metrics.emailsDuplicated.WithLabelValues(send.queue).Add(float64(duplicated))
+
+	setMetrics(send.metrics, send.queue, timeInit, ready, failed, send.maxReties)
 }
 
 func (send *send) copyQueueAndSendEmails(queue []rabbit.Message) []rabbit.Message {
 	buffer := make([]rabbit.Message, len(queue))
 	copy(buffer, queue)
 
-	log.Printf("[INFO] - Sending %d emails", len(buffer))
+	log.Printf("[INFO] - Sending %d emails from '%s'", len(buffer), send.queue)
+
+	send.batches.Add(1)
 
 	go send.emails(buffer)
 
@@ -49,14 +49,73 @@ type sessionConfig struct {
 	DurationMinutes int `config:"duration_minutes" validate:"required,min=1"`
 }
 
+type outboxConfig struct {
+	Interval int `config:"interval" validate:"required"`
+}
+
+type unsubscribeConfig struct {
+	Secret         string `config:"secret"          validate:"required,min=32"`
+	URL            string `config:"url"             validate:"required,url"`
+	ExpirationDays int    `config:"expiration_days" validate:"required,min=1"`
+}
+
+type subscribeConfig struct {
+	Queue           string `config:"queue"`
+	Template        string `config:"template"`
+	Subject         string `config:"subject"          validate:"required"`
+	ExpirationHours int    `config:"expiration_hours" validate:"required,min=1"`
+	MaxPerIP        int    `config:"max_per_ip"       validate:"required,min=1"`
+	MaxPerEmail     int    `config:"max_per_email"    validate:"required,min=1"`
+	MaxPending      int    `config:"max_pending"      validate:"required,min=1"`
+}
+
+type resetConfig struct {
+	Queue             string `config:"queue"`
+	Template          string `config:"template"`
+	Subject           string `config:"subject"            validate:"required"`
+	URL               string `config:"url"                validate:"required_with=Queue,omitempty,url"`
+	ExpirationMinutes int    `config:"expiration_minutes" validate:"required,min=1"`
+	MaxPerIP          int    `config:"max_per_ip"         validate:"required,min=1"`
+	MaxPerEmail       int    `config:"max_per_email"      validate:"required,min=1"`
+}
+
+type bounceConfig struct {
+	Source       string `config:"source"        validate:"omitempty,oneof=maildir mbox imap"`
+	Path         string `config:"path"          validate:"required_if=Source maildir,required_if=Source mbox"`
+	State        string `config:"state"`
+	IMAPHost     string `config:"imap_host"     validate:"required_if=Source imap"`
+	IMAPPort     int    `config:"imap_port"     validate:"required"`
+	IMAPUser     string `config:"imap_user"     validate:"required_if=Source imap"`
+	IMAPPassword string `config:"imap_password"`
+	IMAPTLS      bool   `config:"imap_tls"`
+	IMAPMailbox  string `config:"imap_mailbox"  validate:"required"`
+	Interval     int    `config:"interval"      validate:"required,min=1"`
+}
+
+type serverConfig struct {
+	BodyLimit   int `config:"body_limit"   validate:"required"`
+	ImportLimit int `config:"import_limit" validate:"required"`
+}
+
+type shutdownConfig struct {
+	Timeout int `config:"timeout" validate:"required"`
+}
+
 type adminConfig = model.UserPartial
 
 type configurations struct {
-	Rabbit  rabbitConfig  `config:"rabbit"  validate:"required"`
-	Minio   minioConfig   `config:"minio"   validate:"required"`
-	Mongo   mongoConfig   `config:"mongo"   validate:"required"`
-	Session sessionConfig `config:"session" validate:"required"`
-	Admin   adminConfig   `config:"admin"   validate:"required"`
+	Rabbit      rabbitConfig      `config:"rabbit"      validate:"required"`
+	Minio       minioConfig       `config:"minio"       validate:"required"`
+	Mongo       mongoConfig       `config:"mongo"       validate:"required"`
+	Session     sessionConfig     `config:"session"     validate:"required"`
+	Admin       adminConfig       `config:"admin"       validate:"required"`
+	Server      serverConfig      `config:"server"      validate:"required"`
+	Shutdown    shutdownConfig    `config:"shutdown"    validate:"required"`
+	Outbox      outboxConfig      `config:"outbox"      validate:"required"`
+	Unsubscribe unsubscribeConfig `config:"unsubscribe" validate:"required"`
+	Subscribe   subscribeConfig   `config:"subscribe"   validate:"required"`
+	Reset       resetConfig       `config:"reset"       validate:"required"`
+	Bounce      bounceConfig      `config:"bounce"      validate:"required"`
 }
 
 //nolint:gomnd
@@ -80,6 +139,38 @@ func defaultConfigurations() configurations {
 		Session: sessionConfig{
 			DurationMinutes: 5,
 		},
+		Server: serverConfig{
+			BodyLimit:   64,
+			ImportLimit: 1024,
+		},
+		Shutdown: shutdownConfig{
+			Timeout: 10,
+		},
+		Outbox: outboxConfig{
+			Interval: 10,
+		},
+		Unsubscribe: unsubscribeConfig{
+			ExpirationDays: 90,
+		},
+		Subscribe: subscribeConfig{
+			Subject:         "Confirm your subscription",
+			ExpirationHours: 48,
+			MaxPerIP:        10,
+			MaxPerEmail:     3,
+			MaxPending:      1000,
+		},
+		Reset: resetConfig{
+			Subject:           "Reset your password",
+			ExpirationMinutes: 60,
+			MaxPerIP:          10,
+			MaxPerEmail:       3,
+		},
+		Bounce: bounceConfig{
+			IMAPPort:    993,
+			IMAPTLS:     true,
+			IMAPMailbox: "INBOX",
+			Interval:    60,
+		},
 	}
 }
 
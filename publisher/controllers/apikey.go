@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/gofiber/fiber/v2"
+	"github.com/thiago-felipe-99/mail/publisher/core"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+type APIKey struct {
+	core       *core.APIKey
+	translator *ut.UniversalTranslator
+	languages  []string
+}
+
+func (controller *APIKey) getTranslator(handler *fiber.Ctx) ut.Translator { //nolint:ireturn
+	accept := handler.AcceptsLanguages(controller.languages...)
+	if accept == "" {
+		accept = controller.languages[0]
+	}
+
+	language, _ := controller.translator.GetTranslator(accept)
+
+	return language
+}
+
+type apiKeyRoute struct {
+	method string
+	path   string
+	scope  string
+}
+
+// apiKeyRoutes are the only routes accessed with API keys, the user and admin
+// routes can not be accessed with API keys.
+var apiKeyRoutes = []apiKeyRoute{ //nolint:gochecknoglobals
+	{fiber.MethodPost, "/email/queue/:name/send", model.APIKeyScopeSend},
+	{fiber.MethodPost, "/email/queue/:name/bulk", model.APIKeyScopeSend},
+	{fiber.MethodPost, "/email/queue/:name/bulk/csv", model.APIKeyScopeSend},
+	{fiber.MethodGet, "/email/bulk/:id", model.APIKeyScopeSend},
+	{fiber.MethodGet, "/email/attachment", model.APIKeyScopeSend},
+	{fiber.MethodPost, "/email/attachment", model.APIKeyScopeSend},
+	{fiber.MethodGet, "/email/attachment/:id", model.APIKeyScopeSend},
+	{fiber.MethodPost, "/email/attachment/:id", model.APIKeyScopeSend},
+	{fiber.MethodPost, "/email/attachment/:id/confirm", model.APIKeyScopeSend},
+	{fiber.MethodGet, "/email/template", model.APIKeyScopeTemplates},
+	{fiber.MethodPost, "/email/template", model.APIKeyScopeTemplates},
+	{fiber.MethodGet, "/email/template/:name", model.APIKeyScopeTemplates},
+	{fiber.MethodPut, "/email/template/:name", model.APIKeyScopeTemplates},
+	{fiber.MethodDelete, "/email/template/:name", model.APIKeyScopeTemplates},
+	{fiber.MethodGet, "/email/list", model.APIKeyScopeLists},
+	{fiber.MethodPost, "/email/list", model.APIKeyScopeLists},
+	{fiber.MethodGet, "/email/list/:name", model.APIKeyScopeLists},
+	{fiber.MethodPut, "/email/list/:name", model.APIKeyScopeLists},
+	{fiber.MethodDelete, "/email/list/:name", model.APIKeyScopeLists},
+	{fiber.MethodPost, "/email/list/:name/add", model.APIKeyScopeLists},
+	{fiber.MethodDelete, "/email/list/:name/remove", model.APIKeyScopeLists},
+	{fiber.MethodPost, "/email/list/:name/import", model.APIKeyScopeLists},
+	{fiber.MethodGet, "/email/list/:name/export", model.APIKeyScopeLists},
+	{fiber.MethodGet, "/suppression", model.APIKeyScopeLists},
+	{fiber.MethodPost, "/suppression", model.APIKeyScopeLists},
+	{fiber.MethodPost, "/suppression/bulk", model.APIKeyScopeLists},
+	{fiber.MethodPost, "/suppression/bulk/csv", model.APIKeyScopeLists},
+	{fiber.MethodDelete, "/suppression/:email", model.APIKeyScopeLists},
+}
+
+// apiKeyScope returns the scope needed to access the route with an API key,
+// it is empty when the route can not be accessed with API keys.
+func apiKeyScope(method string, path string) string {
+	for _, route := range apiKeyRoutes {
+		if route.method == method && matchRoute(route.path, path) {
+			return route.scope
+		}
+	}
+
+	return ""
+}
+
+// authenticate uses the API key of the Authorization header instead of the
+// session, it does not have a cookie to be refreshed.
+func (controller *APIKey) authenticate(handler *fiber.Ctx, key string) error {
+	apiKey, err := controller.core.Authenticate(strings.TrimSpace(key))
+	if err != nil {
+		if errors.Is(err, core.ErrAPIKeyDoesNotExist) || errors.Is(err, core.ErrAPIKeyExpired) {
+			return handler.Status(fiber.StatusUnauthorized).JSON(sent{err.Error()})
+		}
+
+		log.Printf("[ERROR] - error authenticating API key: %s", err)
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error authenticating API key"})
+	}
+
+	scope := apiKeyScope(handler.Method(), handler.Path())
+	if scope == "" || !controller.core.HasScope(apiKey, scope) {
+		return handler.Status(fiber.StatusForbidden).
+			JSON(sent{"API key does not have access to the route"})
+	}
+
+	handler.Locals("userID", apiKey.UserID)
+	handler.Locals("apiKeyID", apiKey.ID)
+
+	return handler.Next()
+}
+
+// Create an API key
+//
+//	@Summary		Create API key
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Success		201		{object}	model.APIKeyCreated	"API key created, the key is only returned now"
+//	@Failure		400		{object}	sent				"an invalid API key param was sent"
+//	@Failure		401		{object}	sent				"user session has expired"
+//	@Failure		500		{object}	sent				"internal server error"
+//	@Param			apiKey	body		model.APIKeyPartial	true	"API key params"
+//	@Router			/user/apikey [post]
+//	@Description	Create an API key, it is sent on the header 'Authorization: Bearer <key>'. A key
+//	@Description	without scopes can access the send, templates and lists routes. The send scope only sends emails
+//	@Description	and uploads attachments, the admin routes can not be accessed with API keys.
+func (controller *APIKey) create(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	body := &model.APIKeyPartial{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() (*model.APIKeyCreated, error) { return controller.core.Create(*body, userID) }
+
+	handler.Status(fiber.StatusCreated)
+
+	return callingCoreWithReturn(
+		funcCore,
+		[]expectError{},
+		"error creating API key",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Get all user API keys
+//
+//	@Summary		Get API keys
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{array}		model.APIKey	"user API keys"
+//	@Failure		401	{object}	sent			"user session has expired"
+//	@Failure		500	{object}	sent			"internal server error"
+//	@Router			/user/apikey [get]
+//	@Description	Get the API keys of the user that were not deleted.
+func (controller *APIKey) getAll(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	funcCore := func() ([]model.APIKey, error) { return controller.core.GetAll(userID) }
+
+	return callingCoreWithReturn(
+		funcCore,
+		[]expectError{},
+		"error getting API keys",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Delete an API key
+//
+//	@Summary		Delete API key
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{object}	sent	"API key deleted"
+//	@Failure		400	{object}	sent	"was sent a invalid API key ID"
+//	@Failure		401	{object}	sent	"user session has expired"
+//	@Failure		404	{object}	sent	"API key does not exist"
+//	@Failure		500	{object}	sent	"internal server error"
+//	@Param			id	path		string	true	"API key ID"
+//	@Router			/user/apikey/{id} [delete]
+//	@Description	Delete an API key, the key can not be used anymore.
+func (controller *APIKey) delete(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	keyID, err := model.ParseID(handler.Params("id"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid API key ID"})
+	}
+
+	funcCore := func() error { return controller.core.Delete(keyID, userID) }
+
+	expectErrors := []expectError{{core.ErrAPIKeyDoesNotExist, fiber.StatusNotFound}}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error deleting API key",
+		okay{"API key deleted", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+func TestAPIKeyScope(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		method string
+		path   string
+	}
+
+	scopes := map[request]string{
+		{fiber.MethodPost, "/email/queue/emails/send"}:        model.APIKeyScopeSend,
+		{fiber.MethodPost, "/email/queue/emails/bulk/csv"}:    model.APIKeyScopeSend,
+		{fiber.MethodPost, "/email/queue/emails/send/"}:       model.APIKeyScopeSend,
+		{fiber.MethodGet, "/email/attachment/1"}:              model.APIKeyScopeSend,
+		{fiber.MethodPut, "/email/template/welcome"}:          model.APIKeyScopeTemplates,
+		{fiber.MethodGet, "/email/list"}:                      model.APIKeyScopeLists,
+		{fiber.MethodDelete, "/suppression/user@example.com"}: model.APIKeyScopeLists,
+		{fiber.MethodDelete, "/email/queue/emails/send"}:      "",
+		{fiber.MethodPost, "/email/queue//send"}:              "",
+		{fiber.MethodPost, "/email/queue/emails/send/other"}:  "",
+		{fiber.MethodPost, "/email/queue"}:                    "",
+		{fiber.MethodGet, "/user"}:                            "",
+		{fiber.MethodPost, "/user/admin"}:                     "",
+	}
+
+	for request, want := range scopes {
+		if got := apiKeyScope(request.method, request.path); got != want {
+			t.Errorf("apiKeyScope(%s, %q) = %q, want %q", request.method, request.path, got, want)
+		}
+	}
+}
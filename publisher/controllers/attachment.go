@@ -32,11 +32,12 @@ func (controller *Attachment) getTranslator(handler *fiber.Ctx) ut.Translator {
 //	@Tags			attachment
 //	@Accept			json
 //	@Produce		json
-//	@Success		200			{object}	model.AttachmentURL		"create attachment successfully"
-//	@Failure		400			{object}	sent					"an invalid attachment param was sent"
-//	@Failure		401			{object}	sent					"user session has expired"
-//	@Failure		500			{object}	sent					"internal server error"
-//	@Param			attachment	body		model.AttachmentPartial	true	"attachment params"
+//	@Success		200					{object}	model.AttachmentURL		"create attachment successfully"
+//	@Failure		400					{object}	sent					"an invalid attachment param was sent"
+//	@Failure		401					{object}	sent					"user session has expired"
+//	@Failure		500					{object}	sent					"internal server error"
+//	@Param			attachment			body		model.AttachmentPartial	true	"attachment params"
+//	@Param			X-Organization-ID	header		string					false	"organization of the resources"
 //	@Router			/email/attachment [post]
 //	@Description	Create a upload attachment url.
 func (controller *Attachment) create(handler *fiber.Ctx) error {
@@ -48,6 +49,14 @@ func (controller *Attachment) create(handler *fiber.Ctx) error {
 			JSON(sent{"error refreshing session"})
 	}
 
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
 	body := &model.AttachmentPartial{}
 
 	err := handler.BodyParser(body)
@@ -55,7 +64,7 @@ func (controller *Attachment) create(handler *fiber.Ctx) error {
 		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
 	}
 
-	funcCore := func() (*model.AttachmentURL, error) { return controller.core.Create(*body, userID) }
+	funcCore := func() (*model.AttachmentURL, error) { return controller.core.Create(*body, ownerID, userID) }
 
 	expectErrors := []expectError{{core.ErrMaxSizeAttachment, fiber.StatusBadRequest}}
 
@@ -76,18 +85,19 @@ func (controller *Attachment) create(handler *fiber.Ctx) error {
 //	@Tags			attachment
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	model.AttachmentURL	"attachment url"
-//	@Failure		400	{object}	sent				"was sent a invalid attachment ID"
-//	@Failure		401	{object}	sent				"user session has expired"
-//	@Failure		404	{object}	sent				"attachment does not exist"
-//	@Failure		500	{object}	sent				"internal server error"
-//	@Param			id	path		string				true	"attachment id"
+//	@Success		200					{object}	model.AttachmentURL	"attachment url"
+//	@Failure		400					{object}	sent				"was sent a invalid attachment ID"
+//	@Failure		401					{object}	sent				"user session has expired"
+//	@Failure		404					{object}	sent				"attachment does not exist"
+//	@Failure		500					{object}	sent				"internal server error"
+//	@Param			id					path		string				true	"attachment id"
+//	@Param			X-Organization-ID	header		string				false	"organization of the resources"
 //	@Router			/email/attachment/{id} [get]
 //	@Description	Get a download attachment url.
 func (controller *Attachment) get(handler *fiber.Ctx) error {
-	userID, ok := handler.Locals("userID").(model.ID)
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
 	if !ok {
-		log.Printf("[ERROR] - error getting user ID")
+		log.Printf("[ERROR] - error getting owner ID")
 
 		return handler.Status(fiber.StatusInternalServerError).
 			JSON(sent{"error refreshing session"})
@@ -99,7 +109,7 @@ func (controller *Attachment) get(handler *fiber.Ctx) error {
 			JSON(sent{"was sent a invalid attachment ID"})
 	}
 
-	funcCore := func() (*model.AttachmentURL, error) { return controller.core.Get(attachmentID, userID) }
+	funcCore := func() (*model.AttachmentURL, error) { return controller.core.Get(attachmentID, ownerID) }
 
 	expectErrors := []expectError{{core.ErrAttachmentDoesNotExist, fiber.StatusNotFound}}
 
@@ -118,21 +128,22 @@ func (controller *Attachment) get(handler *fiber.Ctx) error {
 //	@Tags			attachment
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{array}		model.Attachment	"all attachments"
-//	@Failure		401	{object}	sent				"user session has expired"
-//	@Failure		500	{object}	sent				"internal server error"
+//	@Success		200					{array}		model.Attachment	"all attachments"
+//	@Failure		401					{object}	sent				"user session has expired"
+//	@Failure		500					{object}	sent				"internal server error"
+//	@Param			X-Organization-ID	header		string				false	"organization of the resources"
 //	@Router			/email/attachment [get]
 //	@Description	Get all user attachments.
 func (controller *Attachment) getAttachments(handler *fiber.Ctx) error {
-	userID, ok := handler.Locals("userID").(model.ID)
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
 	if !ok {
-		log.Printf("[ERROR] - error getting user ID")
+		log.Printf("[ERROR] - error getting owner ID")
 
 		return handler.Status(fiber.StatusInternalServerError).
 			JSON(sent{"error refreshing session"})
 	}
 
-	funcCore := func() ([]model.Attachment, error) { return controller.core.GetAttachments(userID) }
+	funcCore := func() ([]model.Attachment, error) { return controller.core.GetAttachments(ownerID) }
 
 	return callingCoreWithReturn(
 		funcCore,
@@ -149,18 +160,19 @@ func (controller *Attachment) getAttachments(handler *fiber.Ctx) error {
 //	@Tags			attachment
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	sent	"upload confimed"
-//	@Failure		400	{object}	sent	"was sent a invalid attachment ID"
-//	@Failure		401	{object}	sent	"user session has expired"
-//	@Failure		404	{object}	sent	"attachment does not exist"
-//	@Failure		500	{object}	sent	"internal server error"
-//	@Param			id	path		string	true	"attachment id"
+//	@Success		200					{object}	sent	"upload confimed"
+//	@Failure		400					{object}	sent	"was sent a invalid attachment ID"
+//	@Failure		401					{object}	sent	"user session has expired"
+//	@Failure		404					{object}	sent	"attachment does not exist"
+//	@Failure		500					{object}	sent	"internal server error"
+//	@Param			id					path		string	true	"attachment id"
+//	@Param			X-Organization-ID	header		string	false	"organization of the resources"
 //	@Router			/email/attachment/{id}/confirm [post]
 //	@Description	Confirm upload.
 func (controller *Attachment) confirm(handler *fiber.Ctx) error {
-	userID, ok := handler.Locals("userID").(model.ID)
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
 	if !ok {
-		log.Printf("[ERROR] - error getting user ID")
+		log.Printf("[ERROR] - error getting owner ID")
 
 		return handler.Status(fiber.StatusInternalServerError).
 			JSON(sent{"error refreshing session"})
@@ -172,7 +184,7 @@ func (controller *Attachment) confirm(handler *fiber.Ctx) error {
 			JSON(sent{"was sent a invalid attachment ID"})
 	}
 
-	funcCore := func() error { return controller.core.ConfirmUpload(attachmentID, userID) }
+	funcCore := func() error { return controller.core.ConfirmUpload(attachmentID, ownerID) }
 
 	expectErrors := []expectError{
 		{core.ErrAttachmentDoesNotExist, fiber.StatusNotFound},
@@ -197,18 +209,19 @@ func (controller *Attachment) confirm(handler *fiber.Ctx) error {
 //	@Tags			attachment
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	model.AttachmentURL	"attachment url"
-//	@Failure		400	{object}	sent				"was sent a invalid attachment ID"
-//	@Failure		401	{object}	sent				"user session has expired"
-//	@Failure		404	{object}	sent				"attachment does not exist"
-//	@Failure		500	{object}	sent				"internal server error"
-//	@Param			id	path		string				true	"attachment id"
+//	@Success		200					{object}	model.AttachmentURL	"attachment url"
+//	@Failure		400					{object}	sent				"was sent a invalid attachment ID"
+//	@Failure		401					{object}	sent				"user session has expired"
+//	@Failure		404					{object}	sent				"attachment does not exist"
+//	@Failure		500					{object}	sent				"internal server error"
+//	@Param			id					path		string				true	"attachment id"
+//	@Param			X-Organization-ID	header		string				false	"organization of the resources"
 //	@Router			/email/attachment/{id} [post]
 //	@Description	Refresh a upload attachment url.
 func (controller *Attachment) refresh(handler *fiber.Ctx) error {
-	userID, ok := handler.Locals("userID").(model.ID)
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
 	if !ok {
-		log.Printf("[ERROR] - error getting user ID")
+		log.Printf("[ERROR] - error getting owner ID")
 
 		return handler.Status(fiber.StatusInternalServerError).
 			JSON(sent{"error refreshing session"})
@@ -220,7 +233,7 @@ func (controller *Attachment) refresh(handler *fiber.Ctx) error {
 			JSON(sent{"was sent a invalid attachment ID"})
 	}
 
-	funcCore := func() (*model.AttachmentURL, error) { return controller.core.RefreshUploadURL(attachmentID, userID) }
+	funcCore := func() (*model.AttachmentURL, error) { return controller.core.RefreshUploadURL(attachmentID, ownerID) }
 
 	expectErrors := []expectError{
 		{core.ErrAttachmentDoesNotExist, fiber.StatusNotFound},
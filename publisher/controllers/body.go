@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// streamedRoutes read the request body as a stream, without the body limit of
+// the other routes.
+var streamedRoutes = []string{"/email/list/:name/import"} //nolint:gochecknoglobals
+
+// matchRoute compares the path with the route segment by segment, a parameter
+// matches any non empty segment.
+func matchRoute(route string, path string) bool {
+	routeSegments := strings.Split(strings.Trim(route, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(routeSegments) != len(pathSegments) {
+		return false
+	}
+
+	for index, segment := range routeSegments {
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[index] == "" {
+				return false
+			}
+
+			continue
+		}
+
+		if segment != pathSegments[index] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// limitBody reads the body of the routes that are not streamed, with the
+// request body streaming the server does not apply the body limit.
+func limitBody(bodyLimit int) fiber.Handler {
+	return func(handler *fiber.Ctx) error {
+		request := handler.Request()
+
+		if !request.IsBodyStream() {
+			return handler.Next()
+		}
+
+		for _, route := range streamedRoutes {
+			if matchRoute(route, handler.Path()) {
+				return handler.Next()
+			}
+		}
+
+		body, err := io.ReadAll(io.LimitReader(request.BodyStream(), int64(bodyLimit)+1))
+		if err != nil {
+			return handler.Status(fiber.StatusBadRequest).JSON(sent{"error reading request body"})
+		}
+
+		if len(body) > bodyLimit {
+			// The body not read would be parsed as the next request.
+			handler.Set(fiber.HeaderConnection, "close")
+
+			return handler.Status(fiber.StatusRequestEntityTooLarge).
+				JSON(sent{fiber.ErrRequestEntityTooLarge.Message})
+		}
+
+		request.SetBody(body)
+
+		return handler.Next()
+	}
+}
+
+// limitedReader returns fiber.ErrRequestEntityTooLarge when more than limit
+// bytes are read.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (limited *limitedReader) Read(buffer []byte) (int, error) {
+	if limited.remaining < 0 {
+		return 0, fiber.ErrRequestEntityTooLarge
+	}
+
+	if int64(len(buffer)) > limited.remaining+1 {
+		buffer = buffer[:limited.remaining+1]
+	}
+
+	read, err := limited.reader.Read(buffer)
+	if int64(read) > limited.remaining {
+		limited.remaining = -1
+
+		return read - 1, fiber.ErrRequestEntityTooLarge
+	}
+
+	limited.remaining -= int64(read)
+
+	return read, err //nolint:wrapcheck
+}
@@ -124,8 +124,21 @@ func createTranslator(validate *validator.Validate) (*ut.UniversalTranslator, er
 	return translator, nil
 }
 
-func CreateHTTPServer(validate *validator.Validate, cores *core.Cores) (*fiber.App, error) {
-	app := fiber.New()
+// CreateHTTPServer creates the API, bodyLimit is the max size in megabytes of
+// the requests body and importLimit of the streamed CSV imports.
+func CreateHTTPServer(
+	validate *validator.Validate,
+	cores *core.Cores,
+	bodyLimit int,
+	importLimit int,
+) (*fiber.App, error) {
+	const megabyte = 1024 * 1024
+
+	app := fiber.New(fiber.Config{
+		BodyLimit:                    bodyLimit * megabyte,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
+	})
 
 	prometheus := fiberprometheus.New("publisher")
 	prometheus.RegisterAt(app, "/metrics")
@@ -136,6 +149,7 @@ func CreateHTTPServer(validate *validator.Validate, cores *core.Cores) (*fiber.A
 		TimeFormat: "2006/01/02 15:04:05",
 	}))
 	app.Use(recover.New(recover.Config{EnableStackTrace: true}))
+	app.Use(limitBody(bodyLimit * megabyte))
 	app.Use(prometheus.Middleware)
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "*",
@@ -161,8 +175,15 @@ func CreateHTTPServer(validate *validator.Validate, cores *core.Cores) (*fiber.A
 
 	languages := []string{"en", "pt_BR", "pt"}
 
+	apiKey := APIKey{
+		core:       cores.APIKey,
+		translator: translator,
+		languages:  languages,
+	}
+
 	user := User{
 		core:       cores.User,
+		apiKey:     &apiKey,
 		translator: translator,
 		languages:  languages,
 	}
@@ -174,9 +195,10 @@ func CreateHTTPServer(validate *validator.Validate, cores *core.Cores) (*fiber.A
 	}
 
 	emailList := EmailList{
-		core:       cores.EmailList,
-		translator: translator,
-		languages:  languages,
+		core:        cores.EmailList,
+		translator:  translator,
+		languages:   languages,
+		importLimit: importLimit * megabyte,
 	}
 
 	template := Template{
@@ -191,28 +213,111 @@ func CreateHTTPServer(validate *validator.Validate, cores *core.Cores) (*fiber.A
 		languages:  languages,
 	}
 
+	suppression := Suppression{
+		core:       cores.Suppression,
+		translator: translator,
+		languages:  languages,
+	}
+
+	subscription := Subscription{
+		core:       cores.Subscription,
+		translator: translator,
+		languages:  languages,
+	}
+
+	permission := Permission{
+		core:       cores.Permission,
+		translator: translator,
+		languages:  languages,
+	}
+
+	organization := Organization{
+		core:       cores.Organization,
+		translator: translator,
+		languages:  languages,
+	}
+
+	passwordReset := PasswordReset{
+		core:       cores.PasswordReset,
+		translator: translator,
+		languages:  languages,
+	}
+
+	health := Health{
+		core: cores.Health,
+	}
+
+	app.Get("/health/live", health.live)
+	app.Get("/health/ready", health.ready)
+
 	app.Post("/user/session", user.newSession)
+	app.Post("/user/password/forgot", passwordReset.forgot)
+	app.Post("/user/password/reset", passwordReset.reset)
 	app.Delete("/email/list/:user_id/:name/:email_id", emailList.removeEmail)
+	app.Get("/unsubscribe/:token", emailList.unsubscribePage)
+	app.Post("/unsubscribe/:token", emailList.unsubscribe)
+	app.Get("/subscribe/confirm/:token", subscription.confirmPage)
+	app.Post("/subscribe/confirm/:token", subscription.confirm)
+	app.Post("/subscribe/:user_id/:name", subscription.subscribe)
 
 	app.Use(user.refreshSession)
+	app.Use(organization.scope)
 
 	app.Get("/user", user.get)
 	app.Post("/user", user.isAdmin, user.create)
 	app.Put("/user", user.update)
 	app.Delete("/user", user.delete)
 
+	app.Get("/user/session", user.getSessions)
 	app.Put("/user/session", func(c *fiber.Ctx) error { return c.JSON(sent{"session refreshed"}) })
+	app.Delete("/user/session", user.logout)
+	app.Delete("/user/session/all", user.revokeSessions)
+	app.Delete("/user/session/:id", user.revokeSession)
+
+	app.Post("/user/totp", user.enrollTOTP)
+	app.Post("/user/totp/verify", user.enableTOTP)
+	app.Delete("/user/totp", user.disableTOTP)
+	app.Post("/user/totp/recovery", user.regenerateRecoveryCodes)
+
+	app.Get("/user/apikey", apiKey.getAll)
+	app.Post("/user/apikey", apiKey.create)
+	app.Delete("/user/apikey/:id", apiKey.delete)
 
 	app.Get("/user/admin/:userID", user.isAdmin, user.getByAdmin)
 	app.Post("/user/admin/:userID", user.isAdmin, user.newAdmin)
 	app.Delete("/user/admin/:userID", user.isAdmin, user.removeAdminRole)
 	app.Delete("/user/admin/:userID/user", user.isAdmin, user.deleteUserAdmin)
+	app.Delete("/user/admin/:userID/session", user.isAdmin, user.revokeUserSessions)
 	app.Get("/user/all", user.isAdmin, user.getAll)
 
+	app.Get("/settings", user.isAdmin, user.getSettings)
+	app.Put("/settings", user.isAdmin, user.updateSettings)
+
 	app.Get("/email/queue", queue.getAll)
 	app.Post("/email/queue", user.isAdmin, queue.create)
 	app.Delete("/email/queue/:name", user.isAdmin, queue.delete)
 	app.Post("/email/queue/:name/send", queue.sendEmail)
+	app.Post("/email/queue/:name/bulk", queue.sendBulk)
+	app.Post("/email/queue/:name/bulk/csv", queue.sendBulkCSV)
+	app.Get("/email/queue/:name/emails", queue.getEmails)
+	app.Get("/email/queue/:name/dlx", queue.getDLX)
+	app.Post("/email/queue/:name/dlx/requeue", queue.requeueDLX)
+	app.Delete("/email/queue/:name/dlx", queue.purgeDLX)
+	app.Get("/email/queue/:name/grant", user.isAdmin, permission.getGrants)
+	app.Post("/email/queue/:name/grant/:userID", user.isAdmin, permission.grant)
+	app.Delete("/email/queue/:name/grant/:userID", user.isAdmin, permission.revoke)
+	app.Get("/email/bulk/:id", queue.getBulkJob)
+
+	app.Get("/organization", organization.getAll)
+	app.Post("/organization", organization.create)
+	app.Get("/organization/:id", organization.get)
+	app.Delete("/organization/:id", organization.delete)
+	app.Post("/organization/:id/member/:userID", organization.addMember)
+	app.Delete("/organization/:id/member/:userID", organization.removeMember)
+
+	app.Get("/role", user.isAdmin, permission.getRoles)
+	app.Post("/role", user.isAdmin, permission.createRole)
+	app.Delete("/role/:name", user.isAdmin, permission.deleteRole)
 
 	app.Get("/email/list", emailList.getAll)
 	app.Post("/email/list", emailList.create)
@@ -221,6 +326,19 @@ func CreateHTTPServer(validate *validator.Validate, cores *core.Cores) (*fiber.A
 	app.Delete("/email/list/:name", emailList.delete)
 	app.Post("/email/list/:name/add", emailList.addEmail)
 	app.Delete("/email/list/:name/remove", emailList.removeEmails)
+	app.Post("/email/list/:name/import", emailList.importCSV)
+	app.Get("/email/list/:name/export", emailList.exportCSV)
+
+	app.Get("/suppression", suppression.getAll)
+	app.Post("/suppression", suppression.create)
+	app.Post("/suppression/bulk", suppression.bulk)
+	app.Post("/suppression/bulk/csv", suppression.bulkCSV)
+	app.Get("/suppression/global", user.isAdmin, suppression.getAllGlobal)
+	app.Post("/suppression/global", user.isAdmin, suppression.createGlobal)
+	app.Post("/suppression/global/bulk", user.isAdmin, suppression.bulkGlobal)
+	app.Post("/suppression/global/bulk/csv", user.isAdmin, suppression.bulkCSVGlobal)
+	app.Delete("/suppression/global/:email", user.isAdmin, suppression.deleteGlobal)
+	app.Delete("/suppression/:email", suppression.delete)
 
 	app.Get("/email/template", template.getByUser)
 	app.Post("/email/template", template.create)
@@ -1,7 +1,15 @@
 package controllers
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"mime"
+	"mime/multipart"
+	"strconv"
 
 	ut "github.com/go-playground/universal-translator"
 	"github.com/gofiber/fiber/v2"
@@ -10,9 +18,10 @@ import (
 )
 
 type EmailList struct {
-	core       *core.EmailList
-	translator *ut.UniversalTranslator
-	languages  []string
+	core        *core.EmailList
+	translator  *ut.UniversalTranslator
+	languages   []string
+	importLimit int
 }
 
 func (controller *EmailList) getTranslator(handler *fiber.Ctx) ut.Translator { //nolint:ireturn
@@ -32,12 +41,13 @@ func (controller *EmailList) getTranslator(handler *fiber.Ctx) ut.Translator { /
 //	@Tags			emailList
 //	@Accept			json
 //	@Produce		json
-//	@Success		201			{object}	sent					"create email list successfully"
-//	@Failure		400			{object}	sent					"an invalid email list param was sent"
-//	@Failure		401			{object}	sent					"user session has expired"
-//	@Failure		409			{object}	sent					"email list already exist"
-//	@Failure		500			{object}	sent					"internal server error"
-//	@Param			emailList	body		model.EmailListPartial	true	"email list params"
+//	@Success		201					{object}	sent					"create email list successfully"
+//	@Failure		400					{object}	sent					"an invalid email list param was sent"
+//	@Failure		401					{object}	sent					"user session has expired"
+//	@Failure		409					{object}	sent					"email list already exist"
+//	@Failure		500					{object}	sent					"internal server error"
+//	@Param			emailList			body		model.EmailListPartial	true	"email list params"
+//	@Param			X-Organization-ID	header		string					false	"organization of the resources"
 //	@Router			/email/list [post]
 //	@Description	Create a email list to user.
 func (controller *EmailList) create(handler *fiber.Ctx) error {
@@ -49,6 +59,14 @@ func (controller *EmailList) create(handler *fiber.Ctx) error {
 			JSON(sent{"error refreshing session"})
 	}
 
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
 	body := &model.EmailListPartial{}
 
 	err := handler.BodyParser(body)
@@ -56,7 +74,7 @@ func (controller *EmailList) create(handler *fiber.Ctx) error {
 		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
 	}
 
-	funcCore := func() error { return controller.core.Create(userID, *body) }
+	funcCore := func() error { return controller.core.Create(ownerID, userID, *body) }
 
 	expectErrors := []expectError{{core.ErrEmailListAlreadyExist, fiber.StatusConflict}}
 
@@ -80,23 +98,26 @@ func (controller *EmailList) create(handler *fiber.Ctx) error {
 //	@Tags			emailList
 //	@Accept			json
 //	@Produce		json
-//	@Success		200		{object}	model.EmailList	"email list"
-//	@Failure		401		{object}	sent			"user session has expired"
-//	@Failure		404		{object}	sent			"email list not found"
-//	@Failure		500		{object}	sent			"internal server error"
-//	@Param			name	path		string			true	"email list name"
+//	@Success		200					{object}	model.EmailListContacts	"email list"
+//	@Failure		401					{object}	sent					"user session has expired"
+//	@Failure		404					{object}	sent					"email list not found"
+//	@Failure		500					{object}	sent					"internal server error"
+//	@Param			name				path		string					true	"email list name"
+//	@Param			X-Organization-ID	header		string					false	"organization of the resources"
 //	@Router			/email/list/{name} [get]
 //	@Description	Get a user email list.
 func (controller *EmailList) get(handler *fiber.Ctx) error {
-	userID, ok := handler.Locals("userID").(model.ID)
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
 	if !ok {
-		log.Printf("[ERROR] - error getting user ID")
+		log.Printf("[ERROR] - error getting owner ID")
 
 		return handler.Status(fiber.StatusInternalServerError).
 			JSON(sent{"error refreshing session"})
 	}
 
-	funcCore := func() (*model.EmailList, error) { return controller.core.Get(handler.Params("name"), userID) }
+	funcCore := func() (*model.EmailListContacts, error) {
+		return controller.core.GetWithContacts(handler.Params("name"), ownerID)
+	}
 
 	expectErrors := []expectError{{core.ErrEmailListDoesNotExist, fiber.StatusNotFound}}
 
@@ -117,21 +138,22 @@ func (controller *EmailList) get(handler *fiber.Ctx) error {
 //	@Tags			emailList
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{array}		model.EmailList	"email list"
-//	@Failure		401	{object}	sent			"user session has expired"
-//	@Failure		500	{object}	sent			"internal server error"
+//	@Success		200					{array}		model.EmailList	"email list"
+//	@Failure		401					{object}	sent			"user session has expired"
+//	@Failure		500					{object}	sent			"internal server error"
+//	@Param			X-Organization-ID	header		string			false	"organization of the resources"
 //	@Router			/email/list [get]
-//	@Description	Get all user email list.
+//	@Description	Get all user email list, the emails of a list are returned by the list route.
 func (controller *EmailList) getAll(handler *fiber.Ctx) error {
-	userID, ok := handler.Locals("userID").(model.ID)
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
 	if !ok {
-		log.Printf("[ERROR] - error getting user ID")
+		log.Printf("[ERROR] - error getting owner ID")
 
 		return handler.Status(fiber.StatusInternalServerError).
 			JSON(sent{"error refreshing session"})
 	}
 
-	funcCore := func() ([]model.EmailList, error) { return controller.core.GetAll(userID) }
+	funcCore := func() ([]model.EmailList, error) { return controller.core.GetAll(ownerID) }
 
 	expectErrors := []expectError{}
 
@@ -152,20 +174,21 @@ func (controller *EmailList) getAll(handler *fiber.Ctx) error {
 //	@Tags			emailList
 //	@Accept			json
 //	@Produce		json
-//	@Success		200		{object}	sent				"update email list successfully"
-//	@Failure		400		{object}	sent				"an invalid email list param was sent"
-//	@Failure		401		{object}	sent				"user session has expired"
-//	@Failure		404		{object}	sent				"email list does not exist"
-//	@Failure		409		{object}	sent				"email list already exist"
-//	@Failure		500		{object}	sent				"internal server error"
-//	@Param			name	path		string				true	"email list name"
-//	@Param			info	body		model.EmailListInfo	true	"email list info"
+//	@Success		200					{object}	sent				"update email list successfully"
+//	@Failure		400					{object}	sent				"an invalid email list param was sent"
+//	@Failure		401					{object}	sent				"user session has expired"
+//	@Failure		404					{object}	sent				"email list does not exist"
+//	@Failure		409					{object}	sent				"email list already exist"
+//	@Failure		500					{object}	sent				"internal server error"
+//	@Param			name				path		string				true	"email list name"
+//	@Param			info				body		model.EmailListInfo	true	"email list info"
+//	@Param			X-Organization-ID	header		string				false	"organization of the resources"
 //	@Router			/email/list/{name} [put]
 //	@Description	Update information for an email list.
 func (controller *EmailList) updateInfo(handler *fiber.Ctx) error {
-	userID, ok := handler.Locals("userID").(model.ID)
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
 	if !ok {
-		log.Printf("[ERROR] - error getting user ID")
+		log.Printf("[ERROR] - error getting owner ID")
 
 		return handler.Status(fiber.StatusInternalServerError).
 			JSON(sent{"error refreshing session"})
@@ -178,7 +201,7 @@ func (controller *EmailList) updateInfo(handler *fiber.Ctx) error {
 		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
 	}
 
-	funcCore := func() error { return controller.core.UpdateInfo(handler.Params("name"), userID, *body) }
+	funcCore := func() error { return controller.core.UpdateInfo(handler.Params("name"), ownerID, *body) }
 
 	expectErrors := []expectError{
 		{core.ErrEmailListDoesNotExist, fiber.StatusNotFound},
@@ -205,11 +228,12 @@ func (controller *EmailList) updateInfo(handler *fiber.Ctx) error {
 //	@Tags			emailList
 //	@Accept			json
 //	@Produce		json
-//	@Success		200		{object}	sent	"delete email list successfully"
-//	@Failure		401		{object}	sent	"user session has expired"
-//	@Failure		404		{object}	sent	"email list does not exist"
-//	@Failure		500		{object}	sent	"internal server error"
-//	@Param			name	path		string	true	"email list name"
+//	@Success		200					{object}	sent	"delete email list successfully"
+//	@Failure		401					{object}	sent	"user session has expired"
+//	@Failure		404					{object}	sent	"email list does not exist"
+//	@Failure		500					{object}	sent	"internal server error"
+//	@Param			name				path		string	true	"email list name"
+//	@Param			X-Organization-ID	header		string	false	"organization of the resources"
 //	@Router			/email/list/{name} [delete]
 //	@Description	Delete an email list.
 func (controller *EmailList) delete(handler *fiber.Ctx) error {
@@ -221,7 +245,15 @@ func (controller *EmailList) delete(handler *fiber.Ctx) error {
 			JSON(sent{"error refreshing session"})
 	}
 
-	funcCore := func() error { return controller.core.Delete(handler.Params("name"), userID, userID) }
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	funcCore := func() error { return controller.core.Delete(handler.Params("name"), ownerID, userID) }
 
 	expectErrors := []expectError{{core.ErrEmailListDoesNotExist, fiber.StatusNotFound}}
 
@@ -245,19 +277,20 @@ func (controller *EmailList) delete(handler *fiber.Ctx) error {
 //	@Tags			emailList
 //	@Accept			json
 //	@Produce		json
-//	@Success		200		{object}	sent					"update email list successfully"
-//	@Failure		400		{object}	sent					"an invalid email param was sent"
-//	@Failure		401		{object}	sent					"user session has expired"
-//	@Failure		404		{object}	sent					"email list does not exist"
-//	@Failure		500		{object}	sent					"internal server error"
-//	@Param			name	path		string					true	"email list name"
-//	@Param			emails	body		model.EmailListEmails	true	"emails"
+//	@Success		200					{object}	sent					"update email list successfully"
+//	@Failure		400					{object}	sent					"an invalid email param was sent"
+//	@Failure		401					{object}	sent					"user session has expired"
+//	@Failure		404					{object}	sent					"email list does not exist"
+//	@Failure		500					{object}	sent					"internal server error"
+//	@Param			name				path		string					true	"email list name"
+//	@Param			emails				body		model.EmailListEmails	true	"emails"
+//	@Param			X-Organization-ID	header		string					false	"organization of the resources"
 //	@Router			/email/list/{name}/add [post]
 //	@Description	Add emails to email list.
 func (controller *EmailList) addEmail(handler *fiber.Ctx) error { //nolint: dupl
-	userID, ok := handler.Locals("userID").(model.ID)
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
 	if !ok {
-		log.Printf("[ERROR] - error getting user ID")
+		log.Printf("[ERROR] - error getting owner ID")
 
 		return handler.Status(fiber.StatusInternalServerError).
 			JSON(sent{"error refreshing session"})
@@ -270,7 +303,7 @@ func (controller *EmailList) addEmail(handler *fiber.Ctx) error { //nolint: dupl
 		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
 	}
 
-	funcCore := func() error { return controller.core.AddEmails(handler.Params("name"), userID, *body) }
+	funcCore := func() error { return controller.core.AddEmails(handler.Params("name"), ownerID, *body) }
 
 	expectErrors := []expectError{{core.ErrEmailListDoesNotExist, fiber.StatusNotFound}}
 
@@ -294,19 +327,20 @@ func (controller *EmailList) addEmail(handler *fiber.Ctx) error { //nolint: dupl
 //	@Tags			emailList
 //	@Accept			json
 //	@Produce		json
-//	@Success		200		{object}	sent					"update email list successfully"
-//	@Failure		400		{object}	sent					"an invalid email param was sent"
-//	@Failure		401		{object}	sent					"user session has expired"
-//	@Failure		404		{object}	sent					"email list does not exist"
-//	@Failure		500		{object}	sent					"internal server error"
-//	@Param			name	path		string					true	"email list name"
-//	@Param			emails	body		model.EmailListEmails	true	"emails"
+//	@Success		200					{object}	sent					"update email list successfully"
+//	@Failure		400					{object}	sent					"an invalid email param was sent"
+//	@Failure		401					{object}	sent					"user session has expired"
+//	@Failure		404					{object}	sent					"email list does not exist"
+//	@Failure		500					{object}	sent					"internal server error"
+//	@Param			name				path		string					true	"email list name"
+//	@Param			emails				body		model.EmailListEmails	true	"emails"
+//	@Param			X-Organization-ID	header		string					false	"organization of the resources"
 //	@Router			/email/list/{name}/remove [delete]
 //	@Description	Remove emails to email list.
 func (controller *EmailList) removeEmails(handler *fiber.Ctx) error { //nolint: dupl
-	userID, ok := handler.Locals("userID").(model.ID)
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
 	if !ok {
-		log.Printf("[ERROR] - error getting user ID")
+		log.Printf("[ERROR] - error getting owner ID")
 
 		return handler.Status(fiber.StatusInternalServerError).
 			JSON(sent{"error refreshing session"})
@@ -319,7 +353,7 @@ func (controller *EmailList) removeEmails(handler *fiber.Ctx) error { //nolint:
 		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
 	}
 
-	funcCore := func() error { return controller.core.RemoveEmails(handler.Params("name"), userID, *body) }
+	funcCore := func() error { return controller.core.RemoveEmails(handler.Params("name"), ownerID, *body) }
 
 	expectErrors := []expectError{{core.ErrEmailListDoesNotExist, fiber.StatusNotFound}}
 
@@ -346,7 +380,7 @@ func (controller *EmailList) removeEmails(handler *fiber.Ctx) error { //nolint:
 //	@Success		200			{object}	sent	"remove email successfully"
 //	@Failure		404			{object}	sent	"email list does not exist"
 //	@Failure		500			{object}	sent	"internal server error"
-//	@Param			user_id		path		string	true	"user id from emailist"
+//	@Param			user_id		path		string	true	"user or organization id from email list"
 //	@Param			name		path		string	true	"email list name"
 //	@Param			email_id	path		string	true	"email id from emailist"
 //	@Router			/email/list/{user_id}/{name}/{email_id} [delete]
@@ -381,3 +415,235 @@ func (controller *EmailList) removeEmail(handler *fiber.Ctx) error {
 		handler,
 	)
 }
+
+// maxImportOptionSize is the max size of the import form fields.
+const maxImportOptionSize = 1024
+
+// readImportOption reads a form field of the import, the unknown fields are
+// ignored.
+func readImportOption(options *model.EmailListImportOptions, part *multipart.Part) error {
+	value, err := io.ReadAll(io.LimitReader(part, maxImportOptionSize))
+	if err != nil {
+		return fmt.Errorf("error reading form field: %w", err)
+	}
+
+	switch part.FormName() {
+	case "emailColumn":
+		options.EmailColumn = string(value)
+	case "nameColumn":
+		options.NameColumn = string(value)
+	case "attributes":
+		options.Attributes = append(options.Attributes, string(value))
+	case "dryRun":
+		options.DryRun, err = strconv.ParseBool(string(value))
+		if err != nil {
+			return fmt.Errorf("invalid dryRun field: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Import emails from CSV to email list.
+//
+//	@Summary		Import emails from CSV
+//	@Tags			emailList
+//	@Accept			mpfd
+//	@Produce		json
+//	@Success		200					{object}	model.EmailListImport	"import report"
+//	@Failure		400					{object}	sent					"an invalid CSV was sent"
+//	@Failure		401					{object}	sent					"user session has expired"
+//	@Failure		404					{object}	sent					"email list does not exist"
+//	@Failure		500					{object}	sent					"internal server error"
+//	@Param			name				path		string					true	"email list name"
+//	@Param			emails				formData	file					true	"CSV with the emails"
+//	@Param			emailColumn			formData	string					false	"email column, default is email"
+//	@Param			nameColumn			formData	string					false	"name column, default is name"
+//	@Param			attributes			formData	[]string				false	"columns saved as contact attributes, default is all"
+//	@Param			dryRun				formData	bool					false	"only validates the CSV"
+//	@Param			X-Organization-ID	header		string					false	"organization of the resources"
+//	@Router			/email/list/{name}/import [post]
+//	@Description	Import emails from CSV to email list, the invalid and duplicated rows are reported and ignored.
+//	@Description	The upload is streamed, the form fields must be sent before the file. The emails are compared
+//	@Description	without case and the rows that do not change the contacts are counted as unchanged.
+func (controller *EmailList) importCSV(handler *fiber.Ctx) error {
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	_, params, err := mime.ParseMediaType(handler.Get(fiber.HeaderContentType))
+	if err != nil || params["boundary"] == "" {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{"was sent an invalid multipart form"})
+	}
+
+	var body io.Reader = bytes.NewReader(handler.Body())
+	if handler.Request().IsBodyStream() {
+		body = handler.Request().BodyStream()
+
+		// The body not read would be parsed as the next request and read by the
+		// logger after the handler.
+		handler.Set(fiber.HeaderConnection, "close")
+
+		defer handler.Request().CloseBodyStream() //nolint:errcheck
+	}
+
+	parts := multipart.NewReader(
+		&limitedReader{reader: body, remaining: int64(controller.importLimit)},
+		params["boundary"],
+	)
+
+	options := model.EmailListImportOptions{}
+
+	var file io.Reader
+
+	for file == nil {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			return handler.Status(fiber.StatusBadRequest).JSON(sent{"the emails file was not sent"})
+		}
+
+		if errors.Is(err, fiber.ErrRequestEntityTooLarge) {
+			return handler.Status(fiber.StatusRequestEntityTooLarge).
+				JSON(sent{fiber.ErrRequestEntityTooLarge.Message})
+		}
+
+		if err != nil {
+			return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+		}
+
+		if part.FormName() == "emails" {
+			file = part
+
+			continue
+		}
+
+		err = readImportOption(&options, part)
+		if err != nil {
+			return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+		}
+	}
+
+	funcCore := func() (*model.EmailListImport, error) {
+		return controller.core.ImportCSV(handler.Params("name"), ownerID, file, options)
+	}
+
+	expectErrors := []expectError{
+		{core.ErrEmailListDoesNotExist, fiber.StatusNotFound},
+		{fiber.ErrRequestEntityTooLarge, fiber.StatusRequestEntityTooLarge},
+		{core.ErrInvalidCSV, fiber.StatusBadRequest},
+	}
+
+	unexpectMessageError := "error importing emails"
+
+	return callingCoreWithReturn(
+		funcCore,
+		expectErrors,
+		unexpectMessageError,
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Export email list to CSV.
+//
+//	@Summary		Export email list to CSV
+//	@Tags			emailList
+//	@Produce		text/csv
+//	@Success		200					{file}		file	"CSV with email, name and the contact attributes columns"
+//	@Failure		401					{object}	sent	"user session has expired"
+//	@Failure		404					{object}	sent	"email list does not exist"
+//	@Failure		500					{object}	sent	"internal server error"
+//	@Param			name				path		string	true	"email list name"
+//	@Param			X-Organization-ID	header		string	false	"organization of the resources"
+//	@Router			/email/list/{name}/export [get]
+//	@Description	Export email list to CSV.
+func (controller *EmailList) exportCSV(handler *fiber.Ctx) error {
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	emailList, err := controller.core.Get(handler.Params("name"), ownerID)
+	if errors.Is(err, core.ErrEmailListDoesNotExist) {
+		return handler.Status(fiber.StatusNotFound).JSON(sent{err.Error()})
+	}
+
+	if err != nil {
+		log.Printf("[ERROR] - error exporting email list: %s", err)
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error exporting email list"})
+	}
+
+	handler.Attachment(emailList.Name + ".csv")
+	handler.Set(fiber.HeaderContentType, "text/csv")
+
+	handler.Context().SetBodyStreamWriter(func(writer *bufio.Writer) {
+		err := controller.core.WriteCSV(emailList, writer)
+		if err != nil {
+			log.Printf("[ERROR] - error exporting email list: %s", err)
+		}
+	})
+
+	return nil
+}
+
+// Unsubscribe confirmation page.
+//
+//	@Summary		Unsubscribe confirmation page
+//	@Tags			emailList
+//	@Produce		html
+//	@Success		200		{string}	string	"confirmation page"
+//	@Failure		400		{string}	string	"invalid or expired token"
+//	@Failure		404		{string}	string	"email list does not exist"
+//	@Failure		500		{string}	string	"internal server error"
+//	@Param			token	path		string	true	"unsubscribe token"
+//	@Router			/unsubscribe/{token} [get]
+//	@Description	Page asking to confirm removing the email from the email list.
+func (controller *EmailList) unsubscribePage(handler *fiber.Ctx) error {
+	unsubscribe, err := controller.core.GetUnsubscribe(handler.Params("token"))
+	if err != nil {
+		return linkPageError(handler, err, "error unsubscribing email")
+	}
+
+	if !unsubscribe.Subscribed {
+		return renderLinkPage(handler, fiber.StatusOK, linkPageData{
+			Message: fmt.Sprintf("The email is not subscribed to '%s'.", unsubscribe.List),
+		})
+	}
+
+	return renderLinkPage(handler, fiber.StatusOK, linkPageData{
+		Title:   "Unsubscribe",
+		Message: fmt.Sprintf("Unsubscribe %s from '%s'?", unsubscribe.Email, unsubscribe.List),
+		Button:  "Unsubscribe",
+	})
+}
+
+// One-click unsubscribe.
+//
+//	@Summary		One-click unsubscribe
+//	@Tags			emailList
+//	@Accept			x-www-form-urlencoded
+//	@Produce		html
+//	@Success		200		{string}	string	"email unsubscribed"
+//	@Failure		400		{string}	string	"invalid or expired token"
+//	@Failure		404		{string}	string	"email list does not exist"
+//	@Failure		500		{string}	string	"internal server error"
+//	@Param			token	path		string	true	"unsubscribe token"
+//	@Router			/unsubscribe/{token} [post]
+//	@Description	Removes the email from the email list, it is the List-Unsubscribe-Post (RFC 8058) endpoint.
+func (controller *EmailList) unsubscribe(handler *fiber.Ctx) error {
+	err := controller.core.Unsubscribe(handler.Params("token"))
+	if err != nil {
+		return linkPageError(handler, err, "error unsubscribing email")
+	}
+
+	return renderLinkPage(handler, fiber.StatusOK, linkPageData{Message: "Email unsubscribed."})
+}
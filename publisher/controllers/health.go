@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/thiago-felipe-99/mail/publisher/core"
+)
+
+type Health struct {
+	core *core.Health
+}
+
+// Check if the publisher is alive
+//
+//	@Summary		Liveness
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	sent	"publisher is alive"
+//	@Router			/health/live [get]
+//	@Description	Check if the publisher is alive.
+func (controller *Health) live(handler *fiber.Ctx) error {
+	return handler.JSON(sent{"publisher is alive"})
+}
+
+// Check if the publisher dependencies are ready
+//
+//	@Summary		Readiness
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	model.Health	"all dependencies are healthy"
+//	@Failure		503	{object}	model.Health	"some dependency is unhealthy"
+//	@Router			/health/ready [get]
+//	@Description	Check MongoDB, RabbitMQ connection and Minio buckets, returning the status of each one.
+func (controller *Health) ready(handler *fiber.Ctx) error {
+	health := controller.core.Ready()
+
+	if !health.Healthy {
+		return handler.Status(fiber.StatusServiceUnavailable).JSON(health)
+	}
+
+	return handler.JSON(health)
+}
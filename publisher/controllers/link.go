@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thiago-felipe-99/mail/publisher/core"
+)
+
+// linkPage is the page of the links sent on the emails, the button posts to
+// the same link.
+//
+//nolint:lll
+var linkPage = template.Must(template.New("link").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>{{.Title}}</title></head>
+<body>
+<p>{{.Message}}</p>
+{{if .Button}}<form method="post"><button type="submit">{{.Button}}</button></form>{{end}}
+</body>
+</html>
+`))
+
+type linkPageData struct {
+	Title   string
+	Message string
+	Button  string
+}
+
+func renderLinkPage(handler *fiber.Ctx, status int, data linkPageData) error {
+	handler.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+
+	err := linkPage.Execute(handler.Status(status), data)
+	if err != nil {
+		return fmt.Errorf("error rendering link page: %w", err)
+	}
+
+	return nil
+}
+
+func linkPageError(handler *fiber.Ctx, err error, unexpectMessageError string) error {
+	switch {
+	case errors.Is(err, core.ErrInvalidLinkToken), errors.Is(err, core.ErrLinkTokenExpired):
+		return renderLinkPage(handler, fiber.StatusBadRequest, linkPageData{Message: err.Error()})
+	case errors.Is(err, core.ErrEmailListDoesNotExist), errors.Is(err, core.ErrSubscriptionDoesNotExist):
+		return renderLinkPage(handler, fiber.StatusNotFound, linkPageData{Message: err.Error()})
+	}
+
+	log.Printf("[ERROR] - %s: %s", unexpectMessageError, err)
+
+	return renderLinkPage(
+		handler,
+		fiber.StatusInternalServerError,
+		linkPageData{Message: unexpectMessageError},
+	)
+}
@@ -0,0 +1,350 @@
+package controllers
+
+import (
+	"log"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/gofiber/fiber/v2"
+	"github.com/thiago-felipe-99/mail/publisher/core"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+type Organization struct {
+	core       *core.Organization
+	translator *ut.UniversalTranslator
+	languages  []string
+}
+
+func (controller *Organization) getTranslator(handler *fiber.Ctx) ut.Translator { //nolint:ireturn
+	accept := handler.AcceptsLanguages(controller.languages...)
+	if accept == "" {
+		accept = controller.languages[0]
+	}
+
+	language, _ := controller.translator.GetTranslator(accept)
+
+	return language
+}
+
+// scope sets the owner of the resources of the request, it is the organization
+// of the X-Organization-ID header or the current user.
+func (controller *Organization) scope(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	header := handler.Get("X-Organization-ID")
+	if header == "" {
+		handler.Locals("ownerID", userID)
+
+		return handler.Next()
+	}
+
+	organizationID, err := model.ParseID(header)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid organization ID"})
+	}
+
+	member, err := controller.core.IsMember(organizationID, userID)
+	if err != nil {
+		log.Printf("[ERROR] - error checking if user is organization member: %s", err)
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error checking if user is organization member"})
+	}
+
+	if !member {
+		return handler.Status(fiber.StatusForbidden).
+			JSON(sent{core.ErrOrganizationNotMember.Error()})
+	}
+
+	handler.Locals("ownerID", organizationID)
+
+	return handler.Next()
+}
+
+// Create a organization
+//
+//	@Summary		Create organization
+//	@Tags			organization
+//	@Accept			json
+//	@Produce		json
+//	@Success		201				{object}	sent						"organization created"
+//	@Failure		400				{object}	sent						"an invalid organization param was sent"
+//	@Failure		401				{object}	sent						"user session has expired"
+//	@Failure		500				{object}	sent						"internal server error"
+//	@Param			organization	body		model.OrganizationPartial	true	"organization params"
+//	@Router			/organization [post]
+//	@Description	Create a organization, the current user is its owner.
+func (controller *Organization) create(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	body := &model.OrganizationPartial{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() error { return controller.core.Create(*body, userID) }
+
+	return callingCore(
+		funcCore,
+		[]expectError{},
+		"error creating organization",
+		okay{"organization created", fiber.StatusCreated},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Get the user organizations
+//
+//	@Summary		Get organizations
+//	@Tags			organization
+//	@Produce		json
+//	@Success		200	{array}		model.Organization	"user organizations"
+//	@Failure		401	{object}	sent				"user session has expired"
+//	@Failure		500	{object}	sent				"internal server error"
+//	@Router			/organization [get]
+//	@Description	Get the organizations of the current user.
+func (controller *Organization) getAll(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	funcCore := func() ([]model.Organization, error) { return controller.core.GetAll(userID) }
+
+	return callingCoreWithReturn(
+		funcCore,
+		[]expectError{},
+		"error getting organizations",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Get a organization
+//
+//	@Summary		Get organization
+//	@Tags			organization
+//	@Produce		json
+//	@Success		200	{object}	model.Organization	"organization"
+//	@Failure		400	{object}	sent				"was sent a invalid organization ID"
+//	@Failure		401	{object}	sent				"user session has expired"
+//	@Failure		404	{object}	sent				"organization does not exist"
+//	@Failure		500	{object}	sent				"internal server error"
+//	@Param			id	path		string				true	"organization ID"
+//	@Router			/organization/{id} [get]
+//	@Description	Get a organization of the current user.
+func (controller *Organization) get(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	organizationID, err := model.ParseID(handler.Params("id"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid organization ID"})
+	}
+
+	funcCore := func() (*model.Organization, error) { return controller.core.Get(organizationID, userID) }
+
+	expectErrors := []expectError{{core.ErrOrganizationDoesNotExist, fiber.StatusNotFound}}
+
+	return callingCoreWithReturn(
+		funcCore,
+		expectErrors,
+		"error getting organization",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Delete a organization
+//
+//	@Summary		Delete organization
+//	@Tags			organization
+//	@Produce		json
+//	@Success		200	{object}	sent	"organization deleted"
+//	@Failure		400	{object}	sent	"was sent a invalid organization ID"
+//	@Failure		401	{object}	sent	"user session has expired"
+//	@Failure		403	{object}	sent	"user is not owner of the organization"
+//	@Failure		404	{object}	sent	"organization does not exist"
+//	@Failure		500	{object}	sent	"internal server error"
+//	@Param			id	path		string	true	"organization ID"
+//	@Router			/organization/{id} [delete]
+//	@Description	Delete a organization, only the owners can delete it.
+func (controller *Organization) delete(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	organizationID, err := model.ParseID(handler.Params("id"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid organization ID"})
+	}
+
+	funcCore := func() error { return controller.core.Delete(organizationID, userID) }
+
+	expectErrors := []expectError{
+		{core.ErrOrganizationDoesNotExist, fiber.StatusNotFound},
+		{core.ErrOrganizationNotOwner, fiber.StatusForbidden},
+	}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error deleting organization",
+		okay{"organization deleted", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Add a member to a organization
+//
+//	@Summary		Add organization member
+//	@Tags			organization
+//	@Accept			json
+//	@Produce		json
+//	@Success		201		{object}	sent							"member added"
+//	@Failure		400		{object}	sent							"an invalid member param was sent"
+//	@Failure		401		{object}	sent							"user session has expired"
+//	@Failure		403		{object}	sent							"user is not owner of the organization"
+//	@Failure		404		{object}	sent							"organization or user does not exist"
+//	@Failure		409		{object}	sent							"organization must have an owner"
+//	@Failure		500		{object}	sent							"internal server error"
+//	@Param			id		path		string							true	"organization ID"
+//	@Param			userID	path		string							true	"user ID"
+//	@Param			member	body		model.OrganizationMemberPartial	true	"member params"
+//	@Router			/organization/{id}/member/{userID} [post]
+//	@Description	Add a user to the organization as owner or member, it replaces the current role of the user.
+func (controller *Organization) addMember(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	organizationID, err := model.ParseID(handler.Params("id"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid organization ID"})
+	}
+
+	memberID, err := model.ParseID(handler.Params("userID"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid user ID"})
+	}
+
+	body := &model.OrganizationMemberPartial{}
+
+	err = handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() error {
+		return controller.core.AddMember(organizationID, memberID, *body, userID)
+	}
+
+	expectErrors := []expectError{
+		{core.ErrOrganizationDoesNotExist, fiber.StatusNotFound},
+		{core.ErrUserDoesNotExist, fiber.StatusNotFound},
+		{core.ErrOrganizationNotOwner, fiber.StatusForbidden},
+		{core.ErrOrganizationLastOwner, fiber.StatusConflict},
+	}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error adding organization member",
+		okay{"member added", fiber.StatusCreated},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Remove a member from a organization
+//
+//	@Summary		Remove organization member
+//	@Tags			organization
+//	@Produce		json
+//	@Success		200		{object}	sent	"member removed"
+//	@Failure		400		{object}	sent	"was sent a invalid ID"
+//	@Failure		401		{object}	sent	"user session has expired"
+//	@Failure		403		{object}	sent	"user is not owner of the organization"
+//	@Failure		404		{object}	sent	"organization does not exist or user is not member"
+//	@Failure		409		{object}	sent	"organization must have an owner"
+//	@Failure		500		{object}	sent	"internal server error"
+//	@Param			id		path		string	true	"organization ID"
+//	@Param			userID	path		string	true	"user ID"
+//	@Router			/organization/{id}/member/{userID} [delete]
+//	@Description	Remove a user from the organization, the owners can remove any member and a member can leave.
+func (controller *Organization) removeMember(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	organizationID, err := model.ParseID(handler.Params("id"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid organization ID"})
+	}
+
+	memberID, err := model.ParseID(handler.Params("userID"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid user ID"})
+	}
+
+	funcCore := func() error { return controller.core.RemoveMember(organizationID, memberID, userID) }
+
+	expectErrors := []expectError{
+		{core.ErrOrganizationDoesNotExist, fiber.StatusNotFound},
+		{core.ErrOrganizationNotMember, fiber.StatusNotFound},
+		{core.ErrOrganizationNotOwner, fiber.StatusForbidden},
+		{core.ErrOrganizationLastOwner, fiber.StatusConflict},
+	}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error removing organization member",
+		okay{"member removed", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
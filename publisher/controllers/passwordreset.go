@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	ut "github.com/go-playground/universal-translator"
+	"github.com/gofiber/fiber/v2"
+	"github.com/thiago-felipe-99/mail/publisher/core"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+type PasswordReset struct {
+	core       *core.PasswordReset
+	translator *ut.UniversalTranslator
+	languages  []string
+}
+
+func (controller *PasswordReset) getTranslator(handler *fiber.Ctx) ut.Translator { //nolint:ireturn
+	accept := handler.AcceptsLanguages(controller.languages...)
+	if accept == "" {
+		accept = controller.languages[0]
+	}
+
+	language, _ := controller.translator.GetTranslator(accept)
+
+	return language
+}
+
+// Request a password reset
+//
+//	@Summary		Forgot password
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Success		202		{object}	sent					"reset email sent"
+//	@Failure		400		{object}	sent					"an invalid email was sent"
+//	@Failure		404		{object}	sent					"password reset is disabled"
+//	@Failure		429		{object}	sent					"too many password reset requests"
+//	@Failure		500		{object}	sent					"internal server error"
+//	@Param			email	body		model.PasswordForgot	true	"user email"
+//	@Router			/user/password/forgot [post]
+//	@Description	Sends an email with a single use token to reset the password in background, the response is the same when the email does not have a user. The requests are limited by IP and by email in an hour.
+func (controller *PasswordReset) forgot(handler *fiber.Ctx) error {
+	body := &model.PasswordForgot{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() error { return controller.core.Forgot(*body, handler.IP()) }
+
+	expectErrors := []expectError{
+		{core.ErrPasswordResetDisabled, fiber.StatusNotFound},
+		{core.ErrPasswordResetLimit, fiber.StatusTooManyRequests},
+	}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error requesting password reset",
+		okay{"reset email sent", fiber.StatusAccepted},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Reset the password
+//
+//	@Summary		Reset password
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	sent						"password updated"
+//	@Failure		400		{object}	sent						"invalid or expired token"
+//	@Failure		500		{object}	sent						"internal server error"
+//	@Param			reset	body		model.PasswordResetPartial	true	"token and new password"
+//	@Router			/user/password/reset [post]
+//	@Description	Sets the new password with the token sent by email, the sessions of the user are revoked.
+func (controller *PasswordReset) reset(handler *fiber.Ctx) error {
+	body := &model.PasswordResetPartial{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() error { return controller.core.Reset(*body) }
+
+	expectErrors := []expectError{{core.ErrPasswordResetInvalid, fiber.StatusBadRequest}}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error resetting password",
+		okay{"password updated", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"log"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/gofiber/fiber/v2"
+	"github.com/thiago-felipe-99/mail/publisher/core"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+type Permission struct {
+	core       *core.Permission
+	translator *ut.UniversalTranslator
+	languages  []string
+}
+
+func (controller *Permission) getTranslator(handler *fiber.Ctx) ut.Translator { //nolint:ireturn
+	accept := handler.AcceptsLanguages(controller.languages...)
+	if accept == "" {
+		accept = controller.languages[0]
+	}
+
+	language, _ := controller.translator.GetTranslator(accept)
+
+	return language
+}
+
+// Create a role
+//
+//	@Summary		Create role
+//	@Tags			permission
+//	@Accept			json
+//	@Produce		json
+//	@Success		201		{object}	sent				"role created"
+//	@Failure		400		{object}	sent				"an invalid role param was sent"
+//	@Failure		401		{object}	sent				"user session has expired"
+//	@Failure		403		{object}	sent				"current user is not admin"
+//	@Failure		409		{object}	sent				"role already exist"
+//	@Failure		500		{object}	sent				"internal server error"
+//	@Param			role	body		model.RolePartial	true	"role params"
+//	@Router			/role [post]
+//	@Description	Create a role with the permissions on the queues: send, history and dlx.
+func (controller *Permission) createRole(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	body := &model.RolePartial{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() error { return controller.core.CreateRole(*body, userID) }
+
+	expectErrors := []expectError{{core.ErrRoleAlreadyExist, fiber.StatusConflict}}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error creating role",
+		okay{"role created", fiber.StatusCreated},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Get all roles
+//
+//	@Summary		Get roles
+//	@Tags			permission
+//	@Produce		json
+//	@Success		200	{array}		model.Role	"all roles"
+//	@Failure		401	{object}	sent		"user session has expired"
+//	@Failure		403	{object}	sent		"current user is not admin"
+//	@Failure		500	{object}	sent		"internal server error"
+//	@Router			/role [get]
+//	@Description	Get all roles.
+func (controller *Permission) getRoles(handler *fiber.Ctx) error {
+	return callingCoreWithReturn(
+		controller.core.GetRoles,
+		[]expectError{},
+		"error getting roles",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Delete a role
+//
+//	@Summary		Delete role
+//	@Tags			permission
+//	@Produce		json
+//	@Success		200		{object}	sent	"role deleted"
+//	@Failure		401		{object}	sent	"user session has expired"
+//	@Failure		403		{object}	sent	"current user is not admin"
+//	@Failure		404		{object}	sent	"role does not exist"
+//	@Failure		500		{object}	sent	"internal server error"
+//	@Param			name	path		string	true	"role name"
+//	@Router			/role/{name} [delete]
+//	@Description	Delete a role, the grants with the role do not have permissions anymore.
+func (controller *Permission) deleteRole(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	funcCore := func() error { return controller.core.DeleteRole(handler.Params("name"), userID) }
+
+	expectErrors := []expectError{{core.ErrRoleDoesNotExist, fiber.StatusNotFound}}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error deleting role",
+		okay{"role deleted", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Get the grants of a queue
+//
+//	@Summary		Get queue grants
+//	@Tags			permission
+//	@Produce		json
+//	@Success		200		{array}		model.QueueGrant	"queue grants"
+//	@Failure		401		{object}	sent				"user session has expired"
+//	@Failure		403		{object}	sent				"current user is not admin"
+//	@Failure		500		{object}	sent				"internal server error"
+//	@Param			name	path		string				true	"queue name"
+//	@Router			/email/queue/{name}/grant [get]
+//	@Description	Get the users with a role on the queue.
+func (controller *Permission) getGrants(handler *fiber.Ctx) error {
+	funcCore := func() ([]model.QueueGrant, error) {
+		return controller.core.GetGrants(handler.Params("name"))
+	}
+
+	return callingCoreWithReturn(
+		funcCore,
+		[]expectError{},
+		"error getting grants",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Grant a role on a queue to a user
+//
+//	@Summary		Grant role
+//	@Tags			permission
+//	@Accept			json
+//	@Produce		json
+//	@Success		201		{object}	sent					"role granted"
+//	@Failure		400		{object}	sent					"an invalid grant param was sent"
+//	@Failure		401		{object}	sent					"user session has expired"
+//	@Failure		403		{object}	sent					"current user is not admin"
+//	@Failure		404		{object}	sent					"queue, user or role does not exist"
+//	@Failure		500		{object}	sent					"internal server error"
+//	@Param			name	path		string					true	"queue name"
+//	@Param			userID	path		string					true	"user ID"
+//	@Param			grant	body		model.QueueGrantPartial	true	"grant params"
+//	@Router			/email/queue/{name}/grant/{userID} [post]
+//	@Description	Grant a role on the queue to the user, it replaces the current role of the user on the queue.
+func (controller *Permission) grant(handler *fiber.Ctx) error {
+	adminID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	userID, err := model.ParseID(handler.Params("userID"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid user ID"})
+	}
+
+	body := &model.QueueGrantPartial{}
+
+	err = handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() error {
+		return controller.core.Grant(handler.Params("name"), userID, *body, adminID)
+	}
+
+	expectErrors := []expectError{
+		{core.ErrQueueDoesNotExist, fiber.StatusNotFound},
+		{core.ErrUserDoesNotExist, fiber.StatusNotFound},
+		{core.ErrRoleDoesNotExist, fiber.StatusNotFound},
+	}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error granting role",
+		okay{"role granted", fiber.StatusCreated},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Revoke the role on a queue from a user
+//
+//	@Summary		Revoke role
+//	@Tags			permission
+//	@Produce		json
+//	@Success		200		{object}	sent	"role revoked"
+//	@Failure		400		{object}	sent	"was sent a invalid user ID"
+//	@Failure		401		{object}	sent	"user session has expired"
+//	@Failure		403		{object}	sent	"current user is not admin"
+//	@Failure		404		{object}	sent	"grant does not exist"
+//	@Failure		500		{object}	sent	"internal server error"
+//	@Param			name	path		string	true	"queue name"
+//	@Param			userID	path		string	true	"user ID"
+//	@Router			/email/queue/{name}/grant/{userID} [delete]
+//	@Description	Revoke the role on the queue from the user.
+func (controller *Permission) revoke(handler *fiber.Ctx) error {
+	adminID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	userID, err := model.ParseID(handler.Params("userID"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid user ID"})
+	}
+
+	funcCore := func() error { return controller.core.Revoke(handler.Params("name"), userID, adminID) }
+
+	expectErrors := []expectError{{core.ErrGrantDoesNotExist, fiber.StatusNotFound}}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error revoking role",
+		okay{"role revoked", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
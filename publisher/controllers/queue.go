@@ -36,11 +36,13 @@ func (controller *Queue) getTranslator(handler *fiber.Ctx) ut.Translator { //nol
 //	@Failure		400		{object}	sent				"an invalid queue param was sent"
 //	@Failure		401		{object}	sent				"user session has expired"
 //	@Failure		403		{object}	sent				"current user is not admin"
+//	@Failure		404		{object}	sent				"organization does not exist"
 //	@Failure		409		{object}	sent				"queue already exist"
 //	@Failure		500		{object}	sent				"internal server error"
 //	@Param			queue	body		model.QueuePartial	true	"queue params"
 //	@Router			/email/queue [post]
-//	@Description	Create a RabbitMQ queue with DLX.
+//	@Description	Create a RabbitMQ queue with DLX, the queue of a organization is only used by its members.
+//	@Description	The queue name can not end with -high or -dlx, they are used by the high priority and DLX queues.
 func (controller *Queue) create(handler *fiber.Ctx) error {
 	userID, ok := handler.Locals("userID").(model.ID)
 	if !ok {
@@ -61,7 +63,11 @@ func (controller *Queue) create(handler *fiber.Ctx) error {
 
 	funcCore := func() error { return controller.core.Create(*body, userID) }
 
-	expectErrors := []expectError{{core.ErrQueueAlreadyExist, fiber.StatusConflict}}
+	expectErrors := []expectError{
+		{core.ErrQueueReservedName, fiber.StatusBadRequest},
+		{core.ErrQueueAlreadyExist, fiber.StatusConflict},
+		{core.ErrOrganizationDoesNotExist, fiber.StatusNotFound},
+	}
 
 	unexpectMessageError := "error creating queue"
 
@@ -111,7 +117,7 @@ func (controller *Queue) getAll(handler *fiber.Ctx) error {
 //	@Failure		500		{object}	sent	"internal server error"
 //	@Param			name	path		string	true	"queue name"
 //	@Router			/email/queue/{name} [delete]
-//	@Description	Delete a queue with DLX.
+//	@Description	Delete a queue with DLX, the permissions granted on the queue are revoked.
 func (controller *Queue) delete(handler *fiber.Ctx) error {
 	userID, ok := handler.Locals("userID").(model.ID)
 	if !ok {
@@ -145,16 +151,19 @@ func (controller *Queue) delete(handler *fiber.Ctx) error {
 //	@Tags			queue
 //	@Accept			json
 //	@Produce		json
-//	@Success		200		{object}	sent		"email sent successfully"
-//	@Failure		400		{object}	sent		"an invalid email param was sent"
-//	@Failure		401		{object}	sent		"user session has expired"
-//	@Failure		403		{object}	sent		"current user is not admin"
-//	@Failure		404		{object}	sent		"queue does not exist"
-//	@Failure		500		{object}	sent		"internal server error"
-//	@Param			name	path		string		true	"queue name"
-//	@Param			queue	body		model.Email	true	"email"
+//	@Success		200					{object}	model.EmailSent	"email saved, it is pending until published to the queue"
+//	@Failure		400					{object}	sent			"an invalid email param was sent"
+//	@Failure		401					{object}	sent			"user session has expired"
+//	@Failure		403					{object}	sent			"user does not have permission on the queue"
+//	@Failure		404					{object}	sent			"queue does not exist"
+//	@Failure		500					{object}	sent			"internal server error"
+//	@Param			name				path		string			true	"queue name"
+//	@Param			Idempotency-Key		header		string			false	"emails with the same key are sent only once"
+//	@Param			queue				body		model.Email		true	"email"
+//	@Param			X-Organization-ID	header		string			false	"organization of the resources"
 //	@Router			/email/queue/{name}/send [post]
-//	@Description	Sends an email to the RabbitMQ queue.
+//	@Description	Saves the email and sends it to the RabbitMQ queue, a pending or relaying email is published later by the outbox. The suppressed receivers are removed and reported.
+//	@Description	The contacts of the email lists are blind receivers of one email, so it has no unsubscribe link or List-Unsubscribe header, the bulk send adds them to each contact.
 func (controller *Queue) sendEmail(handler *fiber.Ctx) error {
 	userID, ok := handler.Locals("userID").(model.ID)
 	if !ok {
@@ -164,6 +173,14 @@ func (controller *Queue) sendEmail(handler *fiber.Ctx) error {
 			JSON(sent{"error refreshing session"})
 	}
 
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
 	body := &model.EmailPartial{}
 
 	err := handler.BodyParser(body)
@@ -171,25 +188,327 @@ func (controller *Queue) sendEmail(handler *fiber.Ctx) error {
 		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
 	}
 
-	funcCore := func() error { return controller.core.SendEmail(handler.Params("name"), *body, userID) }
+	idempotencyKey := handler.Get("Idempotency-Key")
+
+	funcCore := func() (*model.EmailSent, error) {
+		return controller.core.SendEmail(handler.Params("name"), *body, userID, ownerID, idempotencyKey)
+	}
 
 	expectErrors := []expectError{
 		{core.ErrQueueDoesNotExist, fiber.StatusNotFound},
 		{core.ErrMissingFieldTemplates, fiber.StatusBadRequest},
 		{core.ErrTemplateDoesNotExist, fiber.StatusBadRequest},
 		{core.ErrAttachmentDoesNotExist, fiber.StatusBadRequest},
+		{core.ErrQueueWithoutPriority, fiber.StatusBadRequest},
+		{core.ErrAllReceiversSuppressed, fiber.StatusBadRequest},
+		{core.ErrPermissionDenied, fiber.StatusForbidden},
 	}
 
 	unexpectMessageError := "error sending email"
 
-	okay := okay{"email sent", fiber.StatusOK}
+	return callingCoreWithReturn(
+		funcCore,
+		expectErrors,
+		unexpectMessageError,
+		controller.getTranslator(handler),
+		handler,
+	)
+}
 
-	return callingCore(
+// Sends a template email to each recipient
+//
+//	@Summary		Sends bulk email
+//	@Tags			queue
+//	@Accept			json
+//	@Produce		json
+//	@Success		200					{object}	model.BulkJob			"job with the result of each recipient"
+//	@Failure		400					{object}	sent					"an invalid bulk param was sent"
+//	@Failure		401					{object}	sent					"user session has expired"
+//	@Failure		403					{object}	sent					"user does not have permission on the queue"
+//	@Failure		404					{object}	sent					"queue does not exist"
+//	@Failure		500					{object}	sent					"internal server error"
+//	@Param			name				path		string					true	"queue name"
+//	@Param			bulk				body		model.BulkEmailPartial	true	"bulk params"
+//	@Param			X-Organization-ID	header		string					false	"organization of the resources"
+//	@Router			/email/queue/{name}/bulk [post]
+//	@Description	Sends a template email to each recipient with its own template data, the emails of the email lists are recipients with the contact attributes as template data. The recipients with the emails of the lists have a max of 10000.
+func (controller *Queue) sendBulk(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	body := &model.BulkEmailPartial{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	return controller.callingSendBulk(handler, *body, userID, ownerID)
+}
+
+// Sends a template email to each recipient of a CSV
+//
+//	@Summary		Sends bulk email from CSV
+//	@Tags			queue
+//	@Accept			mpfd
+//	@Produce		json
+//	@Success		200					{object}	model.BulkJob	"job with the result of each row"
+//	@Failure		400					{object}	sent			"an invalid bulk param was sent"
+//	@Failure		401					{object}	sent			"user session has expired"
+//	@Failure		404					{object}	sent			"queue does not exist"
+//	@Failure		500					{object}	sent			"internal server error"
+//	@Param			name				path		string			true	"queue name"
+//	@Param			subject				formData	string			true	"email subject"
+//	@Param			template			formData	string			true	"template name"
+//	@Param			priority			formData	string			false	"high or low"
+//	@Param			attachments			formData	[]string		false	"attachments IDs"
+//	@Param			emailLists			formData	[]string		false	"email lists names"
+//	@Param			recipients			formData	file			true	"CSV with name, email and the template fields columns"
+//	@Param			X-Organization-ID	header		string			false	"organization of the resources"
+//	@Router			/email/queue/{name}/bulk/csv [post]
+//	@Description	Sends a template email to each row of a CSV, the name and email columns are the receiver and the other columns are the template data. The rows with the emails of the lists have a max of 10000.
+func (controller *Queue) sendBulkCSV(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	body := &model.BulkEmailPartial{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	fileHeader, err := handler.FormFile("recipients")
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	defer file.Close()
+
+	body.Recipients, err = core.ParseBulkCSV(file)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	return controller.callingSendBulk(handler, *body, userID, ownerID)
+}
+
+func (controller *Queue) callingSendBulk(
+	handler *fiber.Ctx,
+	body model.BulkEmailPartial,
+	userID model.ID,
+	ownerID model.ID,
+) error {
+	funcCore := func() (*model.BulkJob, error) {
+		return controller.core.SendBulk(handler.Params("name"), body, userID, ownerID)
+	}
+
+	expectErrors := []expectError{
+		{core.ErrQueueDoesNotExist, fiber.StatusNotFound},
+		{core.ErrTemplateDoesNotExist, fiber.StatusBadRequest},
+		{core.ErrAttachmentDoesNotExist, fiber.StatusBadRequest},
+		{core.ErrQueueWithoutPriority, fiber.StatusBadRequest},
+		{core.ErrEmailListDoesNotExist, fiber.StatusBadRequest},
+		{core.ErrBulkTooManyRecipients, fiber.StatusBadRequest},
+		{core.ErrPermissionDenied, fiber.StatusForbidden},
+	}
+
+	return callingCoreWithReturn(
+		funcCore,
+		expectErrors,
+		"error sending bulk email",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Get a bulk job
+//
+//	@Summary		Get bulk job
+//	@Tags			queue
+//	@Produce		json
+//	@Success		200	{object}	model.BulkJob	"bulk job"
+//	@Failure		400	{object}	sent			"was sent a invalid job ID"
+//	@Failure		401	{object}	sent			"user session has expired"
+//	@Failure		404	{object}	sent			"bulk job does not exist"
+//	@Failure		500	{object}	sent			"internal server error"
+//	@Param			id	path		string			true	"job id"
+//	@Router			/email/bulk/{id} [get]
+//	@Description	Get a bulk job with the result of each recipient.
+func (controller *Queue) getBulkJob(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	jobID, err := model.ParseID(handler.Params("id"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{"was sent a invalid job ID"})
+	}
+
+	funcCore := func() (*model.BulkJob, error) { return controller.core.GetBulkJob(jobID, userID) }
+
+	expectErrors := []expectError{{core.ErrBulkJobDoesNotExist, fiber.StatusNotFound}}
+
+	return callingCoreWithReturn(
+		funcCore,
+		expectErrors,
+		"error getting bulk job",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Get the emails sent to a queue
+//
+//	@Summary		Get queue emails
+//	@Tags			queue
+//	@Produce		json
+//	@Success		200		{array}		model.Email	"emails sent to the queue, the newest first"
+//	@Failure		401		{object}	sent		"user session has expired"
+//	@Failure		403		{object}	sent		"user does not have permission on the queue"
+//	@Failure		404		{object}	sent		"queue does not exist"
+//	@Failure		500		{object}	sent		"internal server error"
+//	@Param			name	path		string		true	"queue name"
+//	@Router			/email/queue/{name}/emails [get]
+//	@Description	Get the emails sent to a queue, the user needs the history permission on the queue.
+func (controller *Queue) getEmails(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	funcCore := func() ([]model.Email, error) {
+		return controller.core.GetEmails(handler.Params("name"), userID)
+	}
+
+	expectErrors := []expectError{
+		{core.ErrQueueDoesNotExist, fiber.StatusNotFound},
+		{core.ErrPermissionDenied, fiber.StatusForbidden},
+	}
+
+	return callingCoreWithReturn(
+		funcCore,
+		expectErrors,
+		"error getting queue emails",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+func (controller *Queue) dlxOf(
+	handler *fiber.Ctx,
+	dlxCore func(name string, userID model.ID) (*model.DLX, error),
+	unexpectMessageError string,
+) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	funcCore := func() (*model.DLX, error) { return dlxCore(handler.Params("name"), userID) }
+
+	expectErrors := []expectError{
+		{core.ErrQueueDoesNotExist, fiber.StatusNotFound},
+		{core.ErrPermissionDenied, fiber.StatusForbidden},
+	}
+
+	return callingCoreWithReturn(
 		funcCore,
 		expectErrors,
 		unexpectMessageError,
-		okay,
 		controller.getTranslator(handler),
 		handler,
 	)
 }
+
+// Get the queue DLX
+//
+//	@Summary		Get DLX
+//	@Tags			queue
+//	@Produce		json
+//	@Success		200		{object}	model.DLX	"number of emails on the DLX"
+//	@Failure		401		{object}	sent		"user session has expired"
+//	@Failure		403		{object}	sent		"user does not have permission on the queue"
+//	@Failure		404		{object}	sent		"queue does not exist"
+//	@Failure		500		{object}	sent		"internal server error"
+//	@Param			name	path		string		true	"queue name"
+//	@Router			/email/queue/{name}/dlx [get]
+//	@Description	Get the number of emails that were not sent after the max retries, the user needs the dlx permission on the queue.
+func (controller *Queue) getDLX(handler *fiber.Ctx) error {
+	return controller.dlxOf(handler, controller.core.GetDLX, "error getting dlx")
+}
+
+// Requeue the queue DLX
+//
+//	@Summary		Requeue DLX
+//	@Tags			queue
+//	@Produce		json
+//	@Success		200		{object}	model.DLX	"number of emails sent to the queue again"
+//	@Failure		401		{object}	sent		"user session has expired"
+//	@Failure		403		{object}	sent		"user does not have permission on the queue"
+//	@Failure		404		{object}	sent		"queue does not exist"
+//	@Failure		500		{object}	sent		"internal server error"
+//	@Param			name	path		string		true	"queue name"
+//	@Router			/email/queue/{name}/dlx/requeue [post]
+//	@Description	Send the emails of the DLX to the queue again, the user needs the dlx permission on the queue.
+func (controller *Queue) requeueDLX(handler *fiber.Ctx) error {
+	return controller.dlxOf(handler, controller.core.RequeueDLX, "error requeuing dlx")
+}
+
+// Purge the queue DLX
+//
+//	@Summary		Purge DLX
+//	@Tags			queue
+//	@Produce		json
+//	@Success		200		{object}	model.DLX	"number of emails removed"
+//	@Failure		401		{object}	sent		"user session has expired"
+//	@Failure		403		{object}	sent		"user does not have permission on the queue"
+//	@Failure		404		{object}	sent		"queue does not exist"
+//	@Failure		500		{object}	sent		"internal server error"
+//	@Param			name	path		string		true	"queue name"
+//	@Router			/email/queue/{name}/dlx [delete]
+//	@Description	Remove the emails of the DLX, the user needs the dlx permission on the queue.
+func (controller *Queue) purgeDLX(handler *fiber.Ctx) error {
+	return controller.dlxOf(handler, controller.core.PurgeDLX, "error purging dlx")
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"fmt"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/gofiber/fiber/v2"
+	"github.com/thiago-felipe-99/mail/publisher/core"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+type Subscription struct {
+	core       *core.Subscription
+	translator *ut.UniversalTranslator
+	languages  []string
+}
+
+func (controller *Subscription) getTranslator(handler *fiber.Ctx) ut.Translator { //nolint:ireturn
+	accept := handler.AcceptsLanguages(controller.languages...)
+	if accept == "" {
+		accept = controller.languages[0]
+	}
+
+	language, _ := controller.translator.GetTranslator(accept)
+
+	return language
+}
+
+// Subscribe to an email list.
+//
+//	@Summary		Subscribe to email list
+//	@Tags			subscription
+//	@Accept			json
+//	@Produce		json
+//	@Success		202		{object}	sent					"confirmation email sent"
+//	@Failure		400		{object}	sent					"an invalid contact param was sent"
+//	@Failure		404		{object}	sent					"email list does not exist"
+//	@Failure		429		{object}	sent					"too many subscriptions"
+//	@Failure		500		{object}	sent					"internal server error"
+//	@Param			user_id	path		string					true	"user or organization id from email list"
+//	@Param			name	path		string					true	"email list name"
+//	@Param			contact	body		model.ContactPartial	true	"contact"
+//	@Router			/subscribe/{user_id}/{name} [post]
+//	@Description	Saves the contact as pending and sends the confirmation email, the contact is added to the email list when the link is confirmed. The subscriptions are limited by IP and by email in an hour and by the pending subscriptions of the list.
+func (controller *Subscription) subscribe(handler *fiber.Ctx) error {
+	userID, err := model.ParseID(handler.Params("user_id"))
+	if err != nil {
+		return handler.Status(fiber.StatusNotFound).
+			JSON(sent{core.ErrEmailListDoesNotExist.Error()})
+	}
+
+	body := &model.ContactPartial{}
+
+	err = handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() error {
+		return controller.core.Subscribe(handler.Params("name"), userID, *body, handler.IP())
+	}
+
+	expectErrors := []expectError{
+		{core.ErrEmailListDoesNotExist, fiber.StatusNotFound},
+		{core.ErrSubscriptionDisabled, fiber.StatusNotFound},
+		{core.ErrAllReceiversSuppressed, fiber.StatusBadRequest},
+		{core.ErrSubscriptionLimit, fiber.StatusTooManyRequests},
+	}
+
+	unexpectMessageError := "error subscribing email"
+
+	okay := okay{"confirmation email sent", fiber.StatusAccepted}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		unexpectMessageError,
+		okay,
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Subscription confirmation page.
+//
+//	@Summary		Subscription confirmation page
+//	@Tags			subscription
+//	@Produce		html
+//	@Success		200		{string}	string	"confirmation page"
+//	@Failure		400		{string}	string	"invalid or expired token"
+//	@Failure		404		{string}	string	"subscription does not exist"
+//	@Failure		500		{string}	string	"internal server error"
+//	@Param			token	path		string	true	"confirmation token"
+//	@Router			/subscribe/confirm/{token} [get]
+//	@Description	Page asking to confirm the subscription to the email list.
+func (controller *Subscription) confirmPage(handler *fiber.Ctx) error {
+	subscription, err := controller.core.Get(handler.Params("token"))
+	if err != nil {
+		return linkPageError(handler, err, "error getting subscription")
+	}
+
+	if subscription.Confirmed {
+		return renderLinkPage(handler, fiber.StatusOK, linkPageData{
+			Title:   "Subscription",
+			Message: fmt.Sprintf("The subscription to '%s' is already confirmed.", subscription.List),
+		})
+	}
+
+	return renderLinkPage(handler, fiber.StatusOK, linkPageData{
+		Title:   "Subscription",
+		Message: fmt.Sprintf("Subscribe %s to '%s'?", subscription.Email, subscription.List),
+		Button:  "Confirm",
+	})
+}
+
+// Confirm subscription.
+//
+//	@Summary		Confirm subscription
+//	@Tags			subscription
+//	@Produce		html
+//	@Success		200		{string}	string	"subscription confirmed"
+//	@Failure		400		{string}	string	"invalid or expired token"
+//	@Failure		404		{string}	string	"subscription does not exist"
+//	@Failure		500		{string}	string	"internal server error"
+//	@Param			token	path		string	true	"confirmation token"
+//	@Router			/subscribe/confirm/{token} [post]
+//	@Description	Adds the contact to the email list with the IP and time of the consent.
+func (controller *Subscription) confirm(handler *fiber.Ctx) error {
+	err := controller.core.Confirm(handler.Params("token"), handler.IP())
+	if err != nil {
+		return linkPageError(handler, err, "error confirming subscription")
+	}
+
+	return renderLinkPage(handler, fiber.StatusOK, linkPageData{
+		Title:   "Subscription",
+		Message: "Subscription confirmed.",
+	})
+}
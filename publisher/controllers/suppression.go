@@ -0,0 +1,366 @@
+package controllers
+
+import (
+	"log"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/gofiber/fiber/v2"
+	"github.com/thiago-felipe-99/mail/publisher/core"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+type Suppression struct {
+	core       *core.Suppression
+	translator *ut.UniversalTranslator
+	languages  []string
+}
+
+func (controller *Suppression) getTranslator(handler *fiber.Ctx) ut.Translator { //nolint:ireturn
+	accept := handler.AcceptsLanguages(controller.languages...)
+	if accept == "" {
+		accept = controller.languages[0]
+	}
+
+	language, _ := controller.translator.GetTranslator(accept)
+
+	return language
+}
+
+func (controller *Suppression) getAllOf(handler *fiber.Ctx, ownerID model.ID) error {
+	funcCore := func() ([]model.Suppression, error) { return controller.core.GetAll(ownerID) }
+
+	return callingCoreWithReturn(
+		funcCore,
+		[]expectError{},
+		"error getting suppressions",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+func (controller *Suppression) createOf(handler *fiber.Ctx, ownerID, userID model.ID) error {
+	body := &model.SuppressionPartial{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() error { return controller.core.Create(*body, ownerID, userID) }
+
+	return callingCore(
+		funcCore,
+		[]expectError{},
+		"error creating suppression",
+		okay{"email suppressed", fiber.StatusCreated},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+func (controller *Suppression) bulkOf(
+	handler *fiber.Ctx,
+	body model.SuppressionBulk,
+	ownerID model.ID,
+	userID model.ID,
+) error {
+	funcCore := func() (*model.SuppressionImport, error) {
+		return controller.core.CreateMultiples(body, ownerID, userID)
+	}
+
+	return callingCoreWithReturn(
+		funcCore,
+		[]expectError{},
+		"error creating suppressions",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+func (controller *Suppression) bulkJSONOf(handler *fiber.Ctx, ownerID, userID model.ID) error {
+	body := &model.SuppressionBulk{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	return controller.bulkOf(handler, *body, ownerID, userID)
+}
+
+func (controller *Suppression) bulkCSVOf(handler *fiber.Ctx, ownerID, userID model.ID) error {
+	fileHeader, err := handler.FormFile("suppressions")
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	defer file.Close()
+
+	suppressions, err := core.ParseSuppressionCSV(file, handler.FormValue("reason"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	return controller.bulkOf(handler, model.SuppressionBulk{Suppressions: suppressions}, ownerID, userID)
+}
+
+func (controller *Suppression) deleteOf(handler *fiber.Ctx, ownerID, userID model.ID) error {
+	funcCore := func() error { return controller.core.Delete(handler.Params("email"), ownerID, userID) }
+
+	expectErrors := []expectError{{core.ErrSuppressionDoesNotExist, fiber.StatusNotFound}}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error deleting suppression",
+		okay{"suppression deleted", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Get all user suppressions
+//
+//	@Summary		Get user suppressions
+//	@Tags			suppression
+//	@Produce		json
+//	@Success		200	{array}		model.Suppression	"user suppressions"
+//	@Failure		401	{object}	sent				"user session has expired"
+//	@Failure		500	{object}	sent				"internal server error"
+//	@Router			/suppression [get]
+//	@Description	Get the emails that do not receive emails from the user.
+func (controller *Suppression) getAll(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	return controller.getAllOf(handler, userID)
+}
+
+// Suppress an email for the user
+//
+//	@Summary		Suppress email
+//	@Tags			suppression
+//	@Accept			json
+//	@Produce		json
+//	@Success		201			{object}	sent						"email suppressed"
+//	@Failure		400			{object}	sent						"an invalid suppression param was sent"
+//	@Failure		401			{object}	sent						"user session has expired"
+//	@Failure		500			{object}	sent						"internal server error"
+//	@Param			suppression	body		model.SuppressionPartial	true	"suppression params"
+//	@Router			/suppression [post]
+//	@Description	Suppress an email, it is removed from the receivers of the user emails.
+func (controller *Suppression) create(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	return controller.createOf(handler, userID, userID)
+}
+
+// Suppress emails for the user
+//
+//	@Summary		Suppress emails
+//	@Tags			suppression
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	model.SuppressionImport	"import report"
+//	@Failure		400				{object}	sent					"an invalid suppression param was sent"
+//	@Failure		401				{object}	sent					"user session has expired"
+//	@Failure		500				{object}	sent					"internal server error"
+//	@Param			suppressions	body		model.SuppressionBulk	true	"suppressions"
+//	@Router			/suppression/bulk [post]
+//	@Description	Suppress emails, the invalid ones are reported.
+func (controller *Suppression) bulk(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	return controller.bulkJSONOf(handler, userID, userID)
+}
+
+// Suppress emails of a CSV for the user
+//
+//	@Summary		Suppress emails from CSV
+//	@Tags			suppression
+//	@Accept			mpfd
+//	@Produce		json
+//	@Success		200				{object}	model.SuppressionImport	"import report"
+//	@Failure		400				{object}	sent					"an invalid CSV was sent"
+//	@Failure		401				{object}	sent					"user session has expired"
+//	@Failure		500				{object}	sent					"internal server error"
+//	@Param			suppressions	formData	file					true	"CSV with email and reason columns"
+//	@Param			reason			formData	string					false	"reason of the rows without it, default is manual"
+//	@Router			/suppression/bulk/csv [post]
+//	@Description	Suppress the emails of a CSV, the invalid ones are reported.
+func (controller *Suppression) bulkCSV(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	return controller.bulkCSVOf(handler, userID, userID)
+}
+
+// Delete a user suppression
+//
+//	@Summary		Delete suppression
+//	@Tags			suppression
+//	@Produce		json
+//	@Success		200		{object}	sent	"suppression deleted"
+//	@Failure		401		{object}	sent	"user session has expired"
+//	@Failure		404		{object}	sent	"suppression does not exist"
+//	@Failure		500		{object}	sent	"internal server error"
+//	@Param			email	path		string	true	"suppressed email"
+//	@Router			/suppression/{email} [delete]
+//	@Description	Delete a suppression, the email receives the user emails again.
+func (controller *Suppression) delete(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	return controller.deleteOf(handler, userID, userID)
+}
+
+// Get all global suppressions
+//
+//	@Summary		Get global suppressions
+//	@Tags			suppression
+//	@Produce		json
+//	@Success		200	{array}		model.Suppression	"global suppressions"
+//	@Failure		401	{object}	sent				"user session has expired"
+//	@Failure		403	{object}	sent				"current user is not admin"
+//	@Failure		500	{object}	sent				"internal server error"
+//	@Router			/suppression/global [get]
+//	@Description	Get the emails that do not receive emails from any user.
+func (controller *Suppression) getAllGlobal(handler *fiber.Ctx) error {
+	return controller.getAllOf(handler, model.ID{})
+}
+
+// Suppress an email for all users
+//
+//	@Summary		Suppress email globally
+//	@Tags			suppression
+//	@Accept			json
+//	@Produce		json
+//	@Success		201			{object}	sent						"email suppressed"
+//	@Failure		400			{object}	sent						"an invalid suppression param was sent"
+//	@Failure		401			{object}	sent						"user session has expired"
+//	@Failure		403			{object}	sent						"current user is not admin"
+//	@Failure		500			{object}	sent						"internal server error"
+//	@Param			suppression	body		model.SuppressionPartial	true	"suppression params"
+//	@Router			/suppression/global [post]
+//	@Description	Suppress an email, it is removed from the receivers of all emails.
+func (controller *Suppression) createGlobal(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	return controller.createOf(handler, model.ID{}, userID)
+}
+
+// Suppress emails for all users
+//
+//	@Summary		Suppress emails globally
+//	@Tags			suppression
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	model.SuppressionImport	"import report"
+//	@Failure		400				{object}	sent					"an invalid suppression param was sent"
+//	@Failure		401				{object}	sent					"user session has expired"
+//	@Failure		403				{object}	sent					"current user is not admin"
+//	@Failure		500				{object}	sent					"internal server error"
+//	@Param			suppressions	body		model.SuppressionBulk	true	"suppressions"
+//	@Router			/suppression/global/bulk [post]
+//	@Description	Suppress emails for all users, the invalid ones are reported.
+func (controller *Suppression) bulkGlobal(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	return controller.bulkJSONOf(handler, model.ID{}, userID)
+}
+
+// Suppress emails of a CSV for all users
+//
+//	@Summary		Suppress emails globally from CSV
+//	@Tags			suppression
+//	@Accept			mpfd
+//	@Produce		json
+//	@Success		200				{object}	model.SuppressionImport	"import report"
+//	@Failure		400				{object}	sent					"an invalid CSV was sent"
+//	@Failure		401				{object}	sent					"user session has expired"
+//	@Failure		403				{object}	sent					"current user is not admin"
+//	@Failure		500				{object}	sent					"internal server error"
+//	@Param			suppressions	formData	file					true	"CSV with email and reason columns"
+//	@Param			reason			formData	string					false	"reason of the rows without it, default is manual"
+//	@Router			/suppression/global/bulk/csv [post]
+//	@Description	Suppress the emails of a CSV for all users, the invalid ones are reported.
+func (controller *Suppression) bulkCSVGlobal(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	return controller.bulkCSVOf(handler, model.ID{}, userID)
+}
+
+// Delete a global suppression
+//
+//	@Summary		Delete global suppression
+//	@Tags			suppression
+//	@Produce		json
+//	@Success		200		{object}	sent	"suppression deleted"
+//	@Failure		401		{object}	sent	"user session has expired"
+//	@Failure		403		{object}	sent	"current user is not admin"
+//	@Failure		404		{object}	sent	"suppression does not exist"
+//	@Failure		500		{object}	sent	"internal server error"
+//	@Param			email	path		string	true	"suppressed email"
+//	@Router			/suppression/global/{email} [delete]
+//	@Description	Delete a global suppression.
+func (controller *Suppression) deleteGlobal(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	return controller.deleteOf(handler, model.ID{}, userID)
+}
@@ -32,12 +32,13 @@ func (controller *Template) getTranslator(handler *fiber.Ctx) ut.Translator { //
 //	@Tags			template
 //	@Accept			json
 //	@Produce		json
-//	@Success		201			{object}	sent					"create template successfully"
-//	@Failure		400			{object}	sent					"an invalid template param was sent"
-//	@Failure		401			{object}	sent					"user session has expired"
-//	@Failure		409			{object}	sent					"template name already exist"
-//	@Failure		500			{object}	sent					"internal server error"
-//	@Param			template	body		model.TemplatePartial	true	"template params"
+//	@Success		201					{object}	sent					"create template successfully"
+//	@Failure		400					{object}	sent					"an invalid template param was sent"
+//	@Failure		401					{object}	sent					"user session has expired"
+//	@Failure		409					{object}	sent					"template name already exist"
+//	@Failure		500					{object}	sent					"internal server error"
+//	@Param			template			body		model.TemplatePartial	true	"template params"
+//	@Param			X-Organization-ID	header		string					false	"organization of the resources"
 //	@Router			/email/template [post]
 //	@Description	Create a email template.
 func (controller *Template) create(handler *fiber.Ctx) error {
@@ -49,6 +50,14 @@ func (controller *Template) create(handler *fiber.Ctx) error {
 			JSON(sent{"error refreshing session"})
 	}
 
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
 	body := &model.TemplatePartial{}
 
 	err := handler.BodyParser(body)
@@ -56,7 +65,7 @@ func (controller *Template) create(handler *fiber.Ctx) error {
 		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
 	}
 
-	funcCore := func() error { return controller.core.Create(*body, userID) }
+	funcCore := func() error { return controller.core.Create(*body, ownerID, userID) }
 
 	expectErrors := []expectError{
 		{core.ErrTemplateNameAlreadyExist, fiber.StatusConflict},
@@ -104,15 +113,24 @@ func (controller *Template) getAll(handler *fiber.Ctx) error {
 //	@Tags			template
 //	@Accept			json
 //	@Produce		json
-//	@Success		200		{object}	model.Template	"all templates"
-//	@Failure		401		{object}	sent			"user session has expired"
-//	@Success		404		{array}		sent			"template does not exist"
-//	@Failure		500		{object}	sent			"internal server error"
-//	@Param			name	path		string			true	"template name"
+//	@Success		200					{object}	model.Template	"all templates"
+//	@Failure		401					{object}	sent			"user session has expired"
+//	@Success		404					{array}		sent			"template does not exist"
+//	@Failure		500					{object}	sent			"internal server error"
+//	@Param			name				path		string			true	"template name"
+//	@Param			X-Organization-ID	header		string			false	"organization of the resources"
 //	@Router			/email/template/{name} [get]
 //	@Description	Get a email template.
 func (controller *Template) get(handler *fiber.Ctx) error {
-	coreFunc := func() (*model.Template, error) { return controller.core.Get(handler.Params("name")) }
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	coreFunc := func() (*model.Template, error) { return controller.core.Get(handler.Params("name"), ownerID) }
 
 	expectErros := []expectError{{core.ErrTemplateDoesNotExist, fiber.StatusNotFound}}
 
@@ -131,21 +149,22 @@ func (controller *Template) get(handler *fiber.Ctx) error {
 //	@Tags			template
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{array}		model.Template	"all user templates"
-//	@Failure		401	{object}	sent			"user session has expired"
-//	@Failure		500	{object}	sent			"internal server error"
+//	@Success		200					{array}		model.Template	"all user templates"
+//	@Failure		401					{object}	sent			"user session has expired"
+//	@Failure		500					{object}	sent			"internal server error"
+//	@Param			X-Organization-ID	header		string			false	"organization of the resources"
 //	@Router			/email/template [get]
 //	@Description	Get all user templates.
 func (controller *Template) getByUser(handler *fiber.Ctx) error {
-	userID, ok := handler.Locals("userID").(model.ID)
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
 	if !ok {
-		log.Printf("[ERROR] - error getting user ID")
+		log.Printf("[ERROR] - error getting owner ID")
 
 		return handler.Status(fiber.StatusInternalServerError).
 			JSON(sent{"error refreshing session"})
 	}
 
-	funcCore := func() ([]model.Template, error) { return controller.core.GetByUser(userID) }
+	funcCore := func() ([]model.Template, error) { return controller.core.GetByOwner(ownerID) }
 
 	expectErrors := []expectError{}
 
@@ -166,16 +185,25 @@ func (controller *Template) getByUser(handler *fiber.Ctx) error {
 //	@Tags			template
 //	@Accept			json
 //	@Produce		json
-//	@Success		200			{object}	sent					"template updated"
-//	@Failure		400			{object}	sent					"an invalid template param was sent"
-//	@Failure		401			{object}	sent					"user session has expired"
-//	@Failure		404			{object}	sent					"template does not exist"
-//	@Failure		500			{object}	sent					"internal server error"
-//	@Param			name		path		string					true	"template name"
-//	@Param			template	body		model.TemplatePartial	true	"template params"
+//	@Success		200					{object}	sent					"template updated"
+//	@Failure		400					{object}	sent					"an invalid template param was sent"
+//	@Failure		401					{object}	sent					"user session has expired"
+//	@Failure		404					{object}	sent					"template does not exist"
+//	@Failure		500					{object}	sent					"internal server error"
+//	@Param			name				path		string					true	"template name"
+//	@Param			template			body		model.TemplatePartial	true	"template params"
+//	@Param			X-Organization-ID	header		string					false	"organization of the resources"
 //	@Router			/email/template/{name} [put]
 //	@Description	Update a email template.
 func (controller *Template) update(handler *fiber.Ctx) error {
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
 	body := &model.TemplatePartial{}
 
 	err := handler.BodyParser(body)
@@ -183,7 +211,7 @@ func (controller *Template) update(handler *fiber.Ctx) error {
 		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
 	}
 
-	funcCore := func() error { return controller.core.Update(handler.Params("name"), *body) }
+	funcCore := func() error { return controller.core.Update(handler.Params("name"), ownerID, *body) }
 
 	expectErrors := []expectError{
 		{core.ErrTemplateDoesNotExist, fiber.StatusNotFound},
@@ -210,11 +238,12 @@ func (controller *Template) update(handler *fiber.Ctx) error {
 //	@Tags			template
 //	@Accept			json
 //	@Produce		json
-//	@Success		200		{object}	sent	"template deleted"
-//	@Failure		401		{object}	sent	"user session has expired"
-//	@Failure		404		{object}	sent	"template does not exist"
-//	@Failure		500		{object}	sent	"internal server error"
-//	@Param			name	path		string	true	"template name"
+//	@Success		200					{object}	sent	"template deleted"
+//	@Failure		401					{object}	sent	"user session has expired"
+//	@Failure		404					{object}	sent	"template does not exist"
+//	@Failure		500					{object}	sent	"internal server error"
+//	@Param			name				path		string	true	"template name"
+//	@Param			X-Organization-ID	header		string	false	"organization of the resources"
 //	@Router			/email/template/{name} [delete]
 //	@Description	Delete a email template.
 func (controller *Template) delete(handler *fiber.Ctx) error {
@@ -226,7 +255,15 @@ func (controller *Template) delete(handler *fiber.Ctx) error {
 			JSON(sent{"error refreshing session"})
 	}
 
-	funcCore := func() error { return controller.core.Delete(handler.Params("name"), userID) }
+	ownerID, ok := handler.Locals("ownerID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting owner ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	funcCore := func() error { return controller.core.Delete(handler.Params("name"), ownerID, userID) }
 
 	expectErrors := []expectError{{core.ErrTemplateDoesNotExist, fiber.StatusNotFound}}
 
@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/thiago-felipe-99/mail/publisher/core"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+// Enroll the two-factor authentication
+//
+//	@Summary		Enroll TOTP
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{object}	model.TOTPEnrollment	"TOTP secret and provisioning URI"
+//	@Failure		401	{object}	sent					"user session has expired"
+//	@Failure		409	{object}	sent					"two-factor authentication is already enabled"
+//	@Failure		500	{object}	sent					"internal server error"
+//	@Router			/user/totp [post]
+//	@Description	Create a new TOTP secret for the current user, the URI can be shown as a QR code to the authenticator
+//	@Description	app. The two-factor authentication is enabled only after the first code is verified.
+func (controller *User) enrollTOTP(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	funcCore := func() (*model.TOTPEnrollment, error) { return controller.core.EnrollTOTP(userID) }
+
+	expectErrors := []expectError{{core.ErrUserTOTPAlreadyEnabled, fiber.StatusConflict}}
+
+	return callingCoreWithReturn(
+		funcCore,
+		expectErrors,
+		"error enrolling two-factor authentication",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Enable the two-factor authentication
+//
+//	@Summary		Enable TOTP
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	model.RecoveryCodes	"recovery codes"
+//	@Failure		400		{object}	sent				"was sent a wrong code"
+//	@Failure		401		{object}	sent				"user session has expired"
+//	@Failure		404		{object}	sent				"two-factor authentication was not enrolled"
+//	@Failure		409		{object}	sent				"two-factor authentication is already enabled"
+//	@Failure		500		{object}	sent				"internal server error"
+//	@Param			code	body		model.TOTPCode		true	"authenticator app code"
+//	@Router			/user/totp/verify [post]
+//	@Description	Verify the first code of the enrolled secret and enable the two-factor authentication. The recovery
+//	@Description	codes are returned only once.
+func (controller *User) enableTOTP(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	body := &model.TOTPCode{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() (*model.RecoveryCodes, error) { return controller.core.EnableTOTP(userID, *body) }
+
+	expectErrors := []expectError{
+		{core.ErrUserWrongTOTPCode, fiber.StatusBadRequest},
+		{core.ErrUserTOTPNotEnrolled, fiber.StatusNotFound},
+		{core.ErrUserTOTPAlreadyEnabled, fiber.StatusConflict},
+	}
+
+	return callingCoreWithReturn(
+		funcCore,
+		expectErrors,
+		"error enabling two-factor authentication",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Disable the two-factor authentication
+//
+//	@Summary		Disable TOTP
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	sent			"two-factor authentication disabled"
+//	@Failure		400		{object}	sent			"was sent a wrong code"
+//	@Failure		401		{object}	sent			"user session has expired"
+//	@Failure		404		{object}	sent			"two-factor authentication is not enabled"
+//	@Failure		429		{object}	sent			"too many wrong two-factor authentication codes"
+//	@Failure		500		{object}	sent			"internal server error"
+//	@Param			code	body		model.TOTPCode	true	"authenticator app or recovery code"
+//	@Router			/user/totp [delete]
+//	@Description	Disable the two-factor authentication of the current user.
+func (controller *User) disableTOTP(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	body := &model.TOTPCode{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() error { return controller.core.DisableTOTP(userID, *body) }
+
+	expectErrors := []expectError{
+		{core.ErrUserWrongTOTPCode, fiber.StatusBadRequest},
+		{core.ErrUserTOTPLocked, fiber.StatusTooManyRequests},
+		{core.ErrUserTOTPNotEnabled, fiber.StatusNotFound},
+	}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error disabling two-factor authentication",
+		okay{"two-factor authentication disabled", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Regenerate the recovery codes
+//
+//	@Summary		Regenerate recovery codes
+//	@Tags			user
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	model.RecoveryCodes	"recovery codes"
+//	@Failure		400		{object}	sent				"was sent a wrong code"
+//	@Failure		401		{object}	sent				"user session has expired"
+//	@Failure		404		{object}	sent				"two-factor authentication is not enabled"
+//	@Failure		429		{object}	sent				"too many wrong two-factor authentication codes"
+//	@Failure		500		{object}	sent				"internal server error"
+//	@Param			code	body		model.TOTPCode		true	"authenticator app or recovery code"
+//	@Router			/user/totp/recovery [post]
+//	@Description	Replace all recovery codes of the current user.
+func (controller *User) regenerateRecoveryCodes(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	body := &model.TOTPCode{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() (*model.RecoveryCodes, error) {
+		return controller.core.RegenerateRecoveryCodes(userID, *body)
+	}
+
+	expectErrors := []expectError{
+		{core.ErrUserWrongTOTPCode, fiber.StatusBadRequest},
+		{core.ErrUserTOTPLocked, fiber.StatusTooManyRequests},
+		{core.ErrUserTOTPNotEnabled, fiber.StatusNotFound},
+	}
+
+	return callingCoreWithReturn(
+		funcCore,
+		expectErrors,
+		"error regenerating recovery codes",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Get the settings
+//
+//	@Summary		Get settings
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	model.Settings	"settings"
+//	@Failure		401	{object}	sent			"user session has expired"
+//	@Failure		403	{object}	sent			"current user is not admin"
+//	@Failure		500	{object}	sent			"internal server error"
+//	@Router			/settings [get]
+//	@Description	Get the settings of the publisher.
+func (controller *User) getSettings(handler *fiber.Ctx) error {
+	return callingCoreWithReturn(
+		controller.core.GetSettings,
+		[]expectError{},
+		"error getting settings",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Update the settings
+//
+//	@Summary		Update settings
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200			{object}	sent			"settings updated"
+//	@Failure		400			{object}	sent			"an invalid settings param was sent"
+//	@Failure		401			{object}	sent			"user session has expired"
+//	@Failure		403			{object}	sent			"current user is not admin"
+//	@Failure		500			{object}	sent			"internal server error"
+//	@Param			settings	body		model.Settings	true	"settings params"
+//	@Router			/settings [put]
+//	@Description	Update the settings of the publisher, requiring the two-factor authentication blocks the admin
+//	@Description	routes for the admins without it.
+func (controller *User) updateSettings(handler *fiber.Ctx) error {
+	body := &model.Settings{}
+
+	err := handler.BodyParser(body)
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).JSON(sent{err.Error()})
+	}
+
+	funcCore := func() error { return controller.core.UpdateSettings(*body) }
+
+	return callingCore(
+		funcCore,
+		[]expectError{},
+		"error updating settings",
+		okay{"settings updated", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
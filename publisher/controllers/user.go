@@ -3,6 +3,7 @@ package controllers
 import (
 	"errors"
 	"log"
+	"strings"
 	"time"
 
 	ut "github.com/go-playground/universal-translator"
@@ -13,6 +14,7 @@ import (
 
 type User struct {
 	core       *core.User
+	apiKey     *APIKey
 	translator *ut.UniversalTranslator
 	languages  []string
 }
@@ -37,7 +39,16 @@ func (controller *User) isAdmin(handler *fiber.Ctx) error {
 			JSON(sent{"error refreshing session"})
 	}
 
+	if _, isAPIKey := handler.Locals("apiKeyID").(model.ID); isAPIKey {
+		return handler.Status(fiber.StatusForbidden).
+			JSON(sent{"API key does not have access to the route"})
+	}
+
 	isAdmin, err := controller.core.IsAdmin(userID)
+	if errors.Is(err, core.ErrAdminTOTPRequired) {
+		return handler.Status(fiber.StatusForbidden).JSON(sent{err.Error()})
+	}
+
 	if err != nil {
 		log.Printf("[ERROR] - error getting if user is admin: %s", err)
 
@@ -60,11 +71,15 @@ func (controller *User) isAdmin(handler *fiber.Ctx) error {
 //	@Produce		json
 //	@Success		201		{object}	sent						"session created successfully"
 //	@Failure		400		{object}	sent						"an invalid user param was sent"
+//	@Failure		401		{object}	sent						"two-factor authentication code is required"
 //	@Failure		404		{object}	sent						"user does not exist"
+//	@Failure		429		{object}	sent						"too many wrong two-factor authentication codes"
 //	@Failure		500		{object}	sent						"internal server error"
 //	@Param			user	body		model.UserSessionPartial	true	"user params"
 //	@Router			/user/session [post]
-//	@Description	Create a user session and set in the response cookie.
+//	@Description	Create a user session and set in the response cookie. With two-factor authentication enabled the code
+//	@Description	of the authenticator app or a recovery code is required, the second factor is locked for some minutes
+//	@Description	after many wrong codes.
 func (controller *User) newSession(handler *fiber.Ctx) error {
 	body := &model.UserSessionPartial{}
 
@@ -76,7 +91,7 @@ func (controller *User) newSession(handler *fiber.Ctx) error {
 	session := &model.UserSession{}
 
 	funcCore := func() error {
-		sessionTemp, err := controller.core.NewSession(*body)
+		sessionTemp, err := controller.core.NewSession(*body, handler.IP(), handler.Get(fiber.HeaderUserAgent))
 		session = sessionTemp
 
 		return err
@@ -85,6 +100,9 @@ func (controller *User) newSession(handler *fiber.Ctx) error {
 	expectErrors := []expectError{
 		{core.ErrUserDoesNotExist, fiber.StatusNotFound},
 		{core.ErrUserWrongPassword, fiber.StatusBadRequest},
+		{core.ErrUserTOTPCodeRequired, fiber.StatusUnauthorized},
+		{core.ErrUserWrongTOTPCode, fiber.StatusBadRequest},
+		{core.ErrUserTOTPLocked, fiber.StatusTooManyRequests},
 	}
 
 	unexpectMessageError := "error creating user session"
@@ -136,6 +154,10 @@ func (controller *User) newSession(handler *fiber.Ctx) error {
 //	@Router			/user/session [put]
 //	@Description	Refresh a user session and set in the response cookie.
 func (controller *User) refreshSession(handler *fiber.Ctx) error {
+	if key, found := strings.CutPrefix(handler.Get(fiber.HeaderAuthorization), "Bearer "); found {
+		return controller.apiKey.authenticate(handler, key)
+	}
+
 	sessionIDRaw := handler.Cookies("session", handler.Get("session", "invalid_session"))
 
 	cookie := &fiber.Cookie{
@@ -172,19 +194,26 @@ func (controller *User) refreshSession(handler *fiber.Ctx) error {
 	cookie.Expires = session.Expires
 
 	handler.Locals("userID", session.UserID)
+	handler.Locals("sessionID", session.ID)
 
 	errNext := handler.Next()
 
-	session, err = controller.core.ReplaceSession(sessionID)
-	if err != nil {
+	session, err = controller.core.ReplaceSession(sessionID, handler.IP(), handler.Get(fiber.HeaderUserAgent))
+
+	switch {
+	case errors.Is(err, core.ErrUserSessionDoesNotExist):
+		// The session was revoked by the request.
+		cookie.Value = ""
+		cookie.Expires = time.Now()
+	case err != nil:
 		log.Printf("[ERROR] - error replacing session: %s", err)
-	} else {
+	default:
 		cookie.Value = session.ID.String()
 		cookie.Expires = session.Expires
 	}
 
 	handler.Cookie(cookie)
-	handler.Set("session", session.ID.String())
+	handler.Set("session", cookie.Value)
 
 	return errNext
 }
@@ -466,7 +495,7 @@ func (controller *User) getAll(handler *fiber.Ctx) error {
 //	@Failure		500		{object}	sent				"internal server error"
 //	@Param			user	body		model.UserPartial	true	"user params"
 //	@Router			/user [put]
-//	@Description	Update user informatios.
+//	@Description	Update user informatios, all sessions of the user are revoked.
 func (controller *User) update(handler *fiber.Ctx) error {
 	userID, ok := handler.Locals("userID").(model.ID)
 	if !ok {
@@ -513,7 +542,7 @@ func (controller *User) update(handler *fiber.Ctx) error {
 //	@Failure		404	{object}	sent	"user does not exist"
 //	@Failure		500	{object}	sent	"internal server error"
 //	@Router			/user [delete]
-//	@Description	Delete current user.
+//	@Description	Delete current user and revoke its sessions.
 func (controller *User) delete(handler *fiber.Ctx) error {
 	userID, ok := handler.Locals("userID").(model.ID)
 	if !ok {
@@ -559,7 +588,7 @@ func (controller *User) delete(handler *fiber.Ctx) error {
 //	@Failure		500		{object}	sent	"internal server error"
 //	@Param			userID	path		string	true	"user id to be deleted"
 //	@Router			/user/admin/{userID}/user [delete]
-//	@Description	Delete user by admin.
+//	@Description	Delete user by admin and revoke its sessions.
 func (controller *User) deleteUserAdmin(handler *fiber.Ctx) error {
 	adminID, ok := handler.Locals("userID").(model.ID)
 	if !ok {
@@ -597,3 +626,178 @@ func (controller *User) deleteUserAdmin(handler *fiber.Ctx) error {
 		handler,
 	)
 }
+
+// Get the active sessions of the current user
+//
+//	@Summary		Get sessions
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{array}		model.ActiveSession	"active sessions"
+//	@Failure		401	{object}	sent				"user session has expired"
+//	@Failure		500	{object}	sent				"internal server error"
+//	@Router			/user/session [get]
+//	@Description	Get the active sessions of the current user with the IP and user agent of the last request.
+func (controller *User) getSessions(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	sessionID, _ := handler.Locals("sessionID").(model.ID)
+
+	funcCore := func() ([]model.ActiveSession, error) {
+		return controller.core.GetSessions(userID, sessionID)
+	}
+
+	return callingCoreWithReturn(
+		funcCore,
+		[]expectError{},
+		"error getting sessions",
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Log out the current session
+//
+//	@Summary		Logout
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{object}	sent	"session revoked"
+//	@Failure		401	{object}	sent	"user session has expired"
+//	@Failure		500	{object}	sent	"internal server error"
+//	@Router			/user/session [delete]
+//	@Description	Revoke the current session and remove it from the cookie.
+func (controller *User) logout(handler *fiber.Ctx) error {
+	sessionID, ok := handler.Locals("sessionID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting session ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	funcCore := func() error { return controller.core.Logout(sessionID) }
+
+	expectErrors := []expectError{{core.ErrUserSessionDoesNotExist, fiber.StatusUnauthorized}}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error revoking session",
+		okay{"session revoked", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Revoke a session of the current user
+//
+//	@Summary		Revoke session
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{object}	sent	"session revoked"
+//	@Failure		400	{object}	sent	"was sent a invalid session ID"
+//	@Failure		401	{object}	sent	"user session has expired"
+//	@Failure		404	{object}	sent	"session does not exist"
+//	@Failure		500	{object}	sent	"internal server error"
+//	@Param			id	path		string	true	"session id"
+//	@Router			/user/session/{id} [delete]
+//	@Description	Revoke a session of the current user, the ID is of the active sessions list.
+func (controller *User) revokeSession(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	loginID, err := model.ParseID(handler.Params("id"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid session ID"})
+	}
+
+	funcCore := func() error { return controller.core.RevokeSession(userID, loginID) }
+
+	expectErrors := []expectError{{core.ErrUserSessionDoesNotExist, fiber.StatusNotFound}}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error revoking session",
+		okay{"session revoked", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Revoke all sessions of the current user
+//
+//	@Summary		Revoke sessions
+//	@Tags			user
+//	@Produce		json
+//	@Success		200	{object}	sent	"sessions revoked"
+//	@Failure		401	{object}	sent	"user session has expired"
+//	@Failure		500	{object}	sent	"internal server error"
+//	@Router			/user/session/all [delete]
+//	@Description	Revoke all sessions of the current user, including the current one.
+func (controller *User) revokeSessions(handler *fiber.Ctx) error {
+	userID, ok := handler.Locals("userID").(model.ID)
+	if !ok {
+		log.Printf("[ERROR] - error getting user ID")
+
+		return handler.Status(fiber.StatusInternalServerError).
+			JSON(sent{"error refreshing session"})
+	}
+
+	funcCore := func() error { return controller.core.RevokeSessions(userID) }
+
+	return callingCore(
+		funcCore,
+		[]expectError{},
+		"error revoking sessions",
+		okay{"sessions revoked", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
+
+// Revoke all sessions of a user by admin
+//
+//	@Summary		Revoke user sessions
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200		{object}	sent	"sessions revoked"
+//	@Failure		400		{object}	sent	"was sent a invalid user ID"
+//	@Failure		401		{object}	sent	"user session has expired"
+//	@Failure		403		{object}	sent	"current user is not admin"
+//	@Failure		404		{object}	sent	"user does not exist"
+//	@Failure		500		{object}	sent	"internal server error"
+//	@Param			userID	path		string	true	"user id"
+//	@Router			/user/admin/{userID}/session [delete]
+//	@Description	Revoke all sessions of a user by admin.
+func (controller *User) revokeUserSessions(handler *fiber.Ctx) error {
+	userID, err := model.ParseID(handler.Params("userID"))
+	if err != nil {
+		return handler.Status(fiber.StatusBadRequest).
+			JSON(sent{"was sent a invalid user ID"})
+	}
+
+	funcCore := func() error { return controller.core.RevokeUserSessions(userID) }
+
+	expectErrors := []expectError{{core.ErrUserDoesNotExist, fiber.StatusNotFound}}
+
+	return callingCore(
+		funcCore,
+		expectErrors,
+		"error revoking sessions",
+		okay{"sessions revoked", fiber.StatusOK},
+		controller.getTranslator(handler),
+		handler,
+	)
+}
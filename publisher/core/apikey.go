@@ -0,0 +1,159 @@
+package core
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/thiago-felipe-99/mail/publisher/data"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	apiKeyPrefix       = "mk_"
+	apiKeySize         = 32
+	apiKeyVisibleChars = 8
+)
+
+// APIKey authenticates the services of an user, only the hash of the key is
+// saved.
+type APIKey struct {
+	database  *data.APIKey
+	user      *User
+	validator *validator.Validate
+}
+
+func hashToken(key string) string {
+	hash := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func (core *APIKey) Create(partial model.APIKeyPartial, userID model.ID) (*model.APIKeyCreated, error) {
+	err := validate(core.validator, partial)
+	if err != nil {
+		return nil, err
+	}
+
+	random := make([]byte, apiKeySize)
+
+	_, err = rand.Read(random)
+	if err != nil {
+		return nil, fmt.Errorf("error generating API key: %w", err)
+	}
+
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	apiKey := model.APIKey{
+		ID:        model.NewID(),
+		UserID:    userID,
+		Name:      partial.Name,
+		Prefix:    key[:len(apiKeyPrefix)+apiKeyVisibleChars],
+		Hash:      hashToken(key),
+		Scopes:    partial.Scopes,
+		ExpiresAt: time.Time{},
+		CreatedAt: time.Now(),
+		DeletedAt: time.Time{},
+		DeletedBy: model.ID{},
+	}
+
+	if partial.ExpiresInDays > 0 {
+		apiKey.ExpiresAt = apiKey.CreatedAt.AddDate(0, 0, partial.ExpiresInDays)
+	}
+
+	err = core.database.Create(apiKey)
+	if err != nil {
+		return nil, fmt.Errorf("error saving API key in database: %w", err)
+	}
+
+	return &model.APIKeyCreated{APIKey: apiKey, Key: key}, nil
+}
+
+func (core *APIKey) GetAll(userID model.ID) ([]model.APIKey, error) {
+	keys, err := core.database.GetAll(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting API keys from database: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (core *APIKey) Delete(keyID model.ID, userID model.ID) error {
+	exist, err := core.database.Exist(keyID, userID)
+	if err != nil {
+		return fmt.Errorf("error checking if API key exist in database: %w", err)
+	}
+
+	if !exist {
+		return ErrAPIKeyDoesNotExist
+	}
+
+	key, err := core.database.Get(keyID, userID)
+	if err != nil {
+		return fmt.Errorf("error getting API key from database: %w", err)
+	}
+
+	key.DeletedAt = time.Now()
+	key.DeletedBy = userID
+
+	err = core.database.Update(*key)
+	if err != nil {
+		return fmt.Errorf("error deleting API key: %w", err)
+	}
+
+	return nil
+}
+
+// Authenticate returns the API key of the key sent, the key of a deleted user
+// is not valid.
+func (core *APIKey) Authenticate(key string) (*model.APIKey, error) {
+	hash := hashToken(key)
+
+	exist, err := core.database.ExistByHash(hash)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if API key exist in database: %w", err)
+	}
+
+	if !exist {
+		return nil, ErrAPIKeyDoesNotExist
+	}
+
+	apiKey, err := core.database.GetByHash(hash)
+	if err != nil {
+		return nil, fmt.Errorf("error getting API key from database: %w", err)
+	}
+
+	if !apiKey.ExpiresAt.IsZero() && apiKey.ExpiresAt.Before(time.Now()) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	exist, err = core.user.existByID(apiKey.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, ErrAPIKeyDoesNotExist
+	}
+
+	return apiKey, nil
+}
+
+// HasScope is true when the key has the scope, a key without scopes has all of
+// them.
+func (core *APIKey) HasScope(key *model.APIKey, scope string) bool {
+	return len(key.Scopes) == 0 || slices.Contains(key.Scopes, scope)
+}
+
+func newAPIKey(database *data.APIKey, user *User, validate *validator.Validate) *APIKey {
+	return &APIKey{
+		database:  database,
+		user:      user,
+		validator: validate,
+	}
+}
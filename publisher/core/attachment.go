@@ -106,6 +106,7 @@ func (core *Attachment) createUploadURL(
 
 func (core *Attachment) Create(
 	partial model.AttachmentPartial,
+	ownerID model.ID,
 	userID model.ID,
 ) (*model.AttachmentURL, error) {
 	err := validate(core.validator, partial)
@@ -123,9 +124,10 @@ func (core *Attachment) Create(
 	attachment := model.Attachment{
 		ID:              model.NewID(),
 		UserID:          userID,
+		OrganizationID:  organizationOf(ownerID, userID),
 		CreatedAt:       now,
 		Name:            partial.Name,
-		MinioName:       userID.String() + "/" + nowString + "-" + partial.Name,
+		MinioName:       ownerID.String() + "/" + nowString + "-" + partial.Name,
 		ContentType:     partial.ContentType,
 		Size:            partial.Size,
 		ConfirmedUpload: false,
@@ -139,8 +141,8 @@ func (core *Attachment) Create(
 	return core.createUploadURL(attachment.ID, attachment.MinioName, attachment.Size)
 }
 
-func (core *Attachment) get(attachmentID model.ID, userID model.ID) (*model.Attachment, error) {
-	exist, err := core.database.Exist(attachmentID, userID)
+func (core *Attachment) get(attachmentID model.ID, ownerID model.ID) (*model.Attachment, error) {
+	exist, err := core.database.Exist(attachmentID, ownerID)
 	if err != nil {
 		return nil, fmt.Errorf("error checking if attachment exist in database: %w", err)
 	}
@@ -149,7 +151,7 @@ func (core *Attachment) get(attachmentID model.ID, userID model.ID) (*model.Atta
 		return nil, ErrAttachmentDoesNotExist
 	}
 
-	attachment, err := core.database.Get(attachmentID, userID)
+	attachment, err := core.database.Get(attachmentID, ownerID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting attachment from database: %w", err)
 	}
@@ -159,9 +161,9 @@ func (core *Attachment) get(attachmentID model.ID, userID model.ID) (*model.Atta
 
 func (core *Attachment) RefreshUploadURL(
 	attachmentID model.ID,
-	userID model.ID,
+	ownerID model.ID,
 ) (*model.AttachmentURL, error) {
-	attachment, err := core.get(attachmentID, userID)
+	attachment, err := core.get(attachmentID, ownerID)
 	if err != nil {
 		return nil, err
 	}
@@ -175,9 +177,9 @@ func (core *Attachment) RefreshUploadURL(
 
 func (core *Attachment) Get(
 	attachmentID model.ID,
-	userID model.ID,
+	ownerID model.ID,
 ) (*model.AttachmentURL, error) {
-	attachment, err := core.get(attachmentID, userID)
+	attachment, err := core.get(attachmentID, ownerID)
 	if err != nil {
 		return nil, err
 	}
@@ -206,8 +208,8 @@ func (core *Attachment) Get(
 	return &attachmentURL, nil
 }
 
-func (core *Attachment) GetAttachments(userID model.ID) ([]model.Attachment, error) {
-	attachments, err := core.database.GetAttachments(userID)
+func (core *Attachment) GetAttachments(ownerID model.ID) ([]model.Attachment, error) {
+	attachments, err := core.database.GetAttachments(ownerID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting attachments from database: %w", err)
 	}
@@ -215,8 +217,8 @@ func (core *Attachment) GetAttachments(userID model.ID) ([]model.Attachment, err
 	return attachments, nil
 }
 
-func (core *Attachment) ConfirmUpload(attachmentID model.ID, userID model.ID) error {
-	attachment, err := core.get(attachmentID, userID)
+func (core *Attachment) ConfirmUpload(attachmentID model.ID, ownerID model.ID) error {
+	attachment, err := core.get(attachmentID, ownerID)
 	if err != nil {
 		return err
 	}
@@ -250,8 +252,8 @@ func (core *Attachment) ConfirmUpload(attachmentID model.ID, userID model.ID) er
 	return nil
 }
 
-func (core *Attachment) Uploaded(userID model.ID, minioName string) (bool, error) {
-	exist, err := core.database.ExistByName(userID, minioName)
+func (core *Attachment) Uploaded(ownerID model.ID, minioName string) (bool, error) {
+	exist, err := core.database.ExistByName(ownerID, minioName)
 	if err != nil {
 		return false, err
 	}
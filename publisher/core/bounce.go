@@ -0,0 +1,396 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/thiago-felipe-99/mail/publisher/data"
+	"github.com/thiago-felipe-99/mail/publisher/mailbox"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+const headerEmailID = "X-Email-ID"
+
+var errNotReport = errors.New("message is not a report")
+
+// report is a DSN (RFC 3464) or a feedback report (RFC 5965) with the ID of
+// the original email, when it was returned.
+type report struct {
+	emailID model.ID
+	bounces []model.Bounce
+}
+
+// reportAddress returns the address of fields like "rfc822; user@host".
+func reportAddress(field string) string {
+	_, address, found := strings.Cut(field, ";")
+	if !found {
+		address = field
+	}
+
+	return normalizeEmail(strings.Trim(strings.TrimSpace(address), "<>"))
+}
+
+// reportFields reads the blocks of fields separated by blank lines.
+func reportFields(reader io.Reader) ([]textproto.MIMEHeader, error) {
+	fields := []textproto.MIMEHeader{}
+	textReader := textproto.NewReader(bufio.NewReader(reader))
+
+	for {
+		block, err := textReader.ReadMIMEHeader()
+		if len(block) > 0 {
+			fields = append(fields, block)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return fields, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error reading report fields: %w", err)
+		}
+	}
+}
+
+// deliveryBounces returns the bounces of the per-recipient fields, the
+// delivered recipients are ignored.
+func deliveryBounces(fields []textproto.MIMEHeader, receivedAt time.Time) []model.Bounce {
+	bounces := []model.Bounce{}
+
+	for _, field := range fields {
+		recipient := field.Get("Final-Recipient")
+		if recipient == "" {
+			recipient = field.Get("Original-Recipient")
+		}
+
+		action := strings.ToLower(strings.TrimSpace(field.Get("Action")))
+		if recipient == "" || (action != "failed" && action != "delayed") {
+			continue
+		}
+
+		status := strings.TrimSpace(field.Get("Status"))
+		bounceType := model.BounceSoft
+
+		if action == "failed" && strings.HasPrefix(status, "5") {
+			bounceType = model.BounceHard
+		}
+
+		bounces = append(bounces, model.Bounce{
+			Email:      reportAddress(recipient),
+			Type:       bounceType,
+			Action:     action,
+			Status:     status,
+			Diagnostic: strings.TrimSpace(field.Get("Diagnostic-Code")),
+			ReceivedAt: receivedAt,
+		})
+	}
+
+	return bounces
+}
+
+func feedbackBounces(fields []textproto.MIMEHeader, receivedAt time.Time) []model.Bounce {
+	bounces := []model.Bounce{}
+
+	for _, field := range fields {
+		for _, recipient := range field.Values("Original-Rcpt-To") {
+			bounces = append(bounces, model.Bounce{
+				Email:      reportAddress(recipient),
+				Type:       model.BounceComplaint,
+				Action:     strings.ToLower(strings.TrimSpace(field.Get("Feedback-Type"))),
+				Status:     "",
+				Diagnostic: "",
+				ReceivedAt: receivedAt,
+			})
+		}
+	}
+
+	return bounces
+}
+
+// originalEmailID returns the ID of the headers of the original email, set by
+// the consumer on X-Email-ID and Message-ID.
+func originalEmailID(reader io.Reader) model.ID {
+	header, err := textproto.NewReader(bufio.NewReader(reader)).ReadMIMEHeader()
+	if err != nil && len(header) == 0 {
+		return model.ID{}
+	}
+
+	if id, err := model.ParseID(strings.TrimSpace(header.Get(headerEmailID))); err == nil {
+		return id
+	}
+
+	messageID := strings.Trim(strings.TrimSpace(header.Get("Message-Id")), "<>")
+	localPart, _, _ := strings.Cut(messageID, "@")
+
+	if id, err := model.ParseID(localPart); err == nil {
+		return id
+	}
+
+	return model.ID{}
+}
+
+func parseReport(message []byte) (*report, error) {
+	parsed, err := mail.ReadMessage(bytes.NewReader(message))
+	if err != nil {
+		return nil, fmt.Errorf("error reading message: %w", err)
+	}
+
+	receivedAt, err := parsed.Header.Date()
+	if err != nil {
+		receivedAt = time.Now()
+	}
+
+	receivedAt = receivedAt.UTC().Truncate(time.Second)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" {
+		return nil, errNotReport
+	}
+
+	result := &report{emailID: model.ID{}, bounces: []model.Bounce{}}
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error reading report part: %w", err)
+		}
+
+		var body io.Reader = part
+		if strings.EqualFold(part.Header.Get("Content-Transfer-Encoding"), "base64") {
+			body = base64.NewDecoder(base64.StdEncoding, part)
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+
+		switch partType {
+		case "message/delivery-status", "message/global-delivery-status":
+			fields, err := reportFields(body)
+			if err != nil {
+				return nil, err
+			}
+
+			result.bounces = append(result.bounces, deliveryBounces(fields, receivedAt)...)
+		case "message/feedback-report":
+			fields, err := reportFields(body)
+			if err != nil {
+				return nil, err
+			}
+
+			result.bounces = append(result.bounces, feedbackBounces(fields, receivedAt)...)
+		case "message/rfc822", "message/global", "text/rfc822-headers", "message/global-headers":
+			if id := originalEmailID(body); id != (model.ID{}) {
+				result.emailID = id
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Bounce reads the reports from the mailbox, saves the bounces on the
+// emails and suppresses the hard bounces and complaints for the user.
+type Bounce struct {
+	database    *data.Queue
+	suppression *Suppression
+}
+
+// receiversBounces returns the bounces of the email receivers, the reports
+// can be forged with the email ID and the other addresses are ignored.
+func receiversBounces(email *model.Email, bounces []model.Bounce) []model.Bounce {
+	receivers := map[string]struct{}{}
+	for _, receiver := range receiversEmails(email.Receivers, email.BlindReceivers) {
+		receivers[normalizeEmail(receiver)] = struct{}{}
+	}
+
+	filtered := make([]model.Bounce, 0, len(bounces))
+
+	for _, bounce := range bounces {
+		if _, exist := receivers[bounce.Email]; exist {
+			filtered = append(filtered, bounce)
+		}
+	}
+
+	return filtered
+}
+
+// emailBounced is true when every receiver of the email had a hard bounce.
+func emailBounced(email *model.Email, bounces []model.Bounce) bool {
+	hard := map[string]struct{}{}
+
+	for _, bounce := range append(email.Bounces, bounces...) {
+		if bounce.Type == model.BounceHard {
+			hard[bounce.Email] = struct{}{}
+		}
+	}
+
+	for _, receiver := range receiversEmails(email.Receivers, email.BlindReceivers) {
+		if _, bounced := hard[normalizeEmail(receiver)]; !bounced {
+			return false
+		}
+	}
+
+	return len(hard) > 0
+}
+
+// Process saves the bounces of the message, messages that are not reports or
+// that are not from a sent email are ignored.
+func (core *Bounce) Process(message []byte) error {
+	report, err := parseReport(message)
+	if errors.Is(err, errNotReport) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(report.bounces) == 0 {
+		return nil
+	}
+
+	if report.emailID == (model.ID{}) {
+		log.Printf("[INFO] - Ignoring bounce without email ID for %s", report.bounces[0].Email)
+
+		return nil
+	}
+
+	exist, err := core.database.ExistEmail(report.emailID)
+	if err != nil {
+		return fmt.Errorf("error checking if email exist in database: %w", err)
+	}
+
+	if !exist {
+		log.Printf("[INFO] - Ignoring bounce of unknown email '%s'", report.emailID)
+
+		return nil
+	}
+
+	email, err := core.database.GetEmail(report.emailID)
+	if err != nil {
+		return fmt.Errorf("error getting email from database: %w", err)
+	}
+
+	bounces := receiversBounces(email, report.bounces)
+	if len(bounces) == 0 {
+		log.Printf("[INFO] - Ignoring bounce of '%s' without its receivers", email.ID)
+
+		return nil
+	}
+
+	status := ""
+	if emailBounced(email, bounces) {
+		status = model.EmailBounced
+	}
+
+	for _, bounce := range bounces {
+		err := core.database.AddBounce(email.ID, bounce, status)
+		if err != nil {
+			return fmt.Errorf("error saving bounce in database: %w", err)
+		}
+
+		reason := ""
+
+		switch bounce.Type {
+		case model.BounceHard:
+			reason = model.SuppressionBounced
+		case model.BounceComplaint:
+			reason = model.SuppressionComplained
+		}
+
+		if reason == "" {
+			continue
+		}
+
+		err = core.suppression.insert(
+			model.SuppressionPartial{Email: bounce.Email, Reason: reason},
+			email.UserID,
+		)
+		if errors.As(err, &ModelInvalidError{}) {
+			log.Printf("[INFO] - Ignoring bounce with invalid email '%s'", bounce.Email)
+
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("error suppressing bounced email: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ProcessMailbox processes the messages of the mailbox until the context is
+// done, a message with error is processed again on the next interval.
+func (core *Bounce) ProcessMailbox(ctx context.Context, source mailbox.Mailbox, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	defer func() {
+		err := source.Close()
+		if err != nil {
+			log.Printf("[ERROR] - Error closing mailbox: %s", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		messages, err := source.Fetch(ctx)
+		if err != nil {
+			log.Printf("[ERROR] - Error fetching bounces: %s", err)
+
+			continue
+		}
+
+		processed := 0
+
+		for _, message := range messages {
+			err := core.Process(message.Data)
+			if err != nil {
+				log.Printf("[ERROR] - Error processing bounce: %s", err)
+
+				continue
+			}
+
+			err = message.Done()
+			if err != nil {
+				log.Printf("[ERROR] - Error marking bounce as processed: %s", err)
+
+				continue
+			}
+
+			processed++
+		}
+
+		if processed > 0 {
+			log.Printf("[INFO] - %d mailbox messages were processed", processed)
+		}
+	}
+}
+
+func newBounce(database *data.Queue, suppression *Suppression) *Bounce {
+	return &Bounce{
+		database:    database,
+		suppression: suppression,
+	}
+}
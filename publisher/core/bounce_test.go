@@ -0,0 +1,153 @@
+package core
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+// reportMessage returns a message of the content type with the parts, the
+// lines end with CRLF like the messages read from the mailboxes.
+func reportMessage(contentType string, parts ...string) []byte {
+	message := "Date: Mon, 02 Jan 2006 15:04:05 +0000\n" +
+		"Content-Type: " + contentType + "; boundary=\"boundary\"\n\n"
+
+	for _, part := range parts {
+		message += "--boundary\n" + part + "\n"
+	}
+
+	message += "--boundary--\n"
+
+	return []byte(strings.ReplaceAll(message, "\n", "\r\n"))
+}
+
+// deliveryStatus returns a message/delivery-status part with a recipient.
+func deliveryStatus(recipient string, fields ...string) string {
+	return "Content-Type: message/delivery-status\n\n" +
+		"Reporting-MTA: dns; mail.example.com\n\n" +
+		recipient + "\n" +
+		strings.Join(fields, "\n") + "\n"
+}
+
+func TestParseReport(t *testing.T) {
+	t.Parallel()
+
+	emailID := model.NewID()
+	receivedAt := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	headers := "Content-Type: text/rfc822-headers\n\n" +
+		"X-Email-ID: " + emailID.String() + "\n" +
+		"Subject: test\n"
+	original := "Content-Type: message/rfc822\n\n" +
+		"Message-ID: <" + emailID.String() + "@example.com>\n" +
+		"Subject: test\n\n" +
+		"body\n"
+	failed := deliveryStatus(
+		"Final-Recipient: rfc822; <User@Example.com>",
+		"Action: failed",
+		"Status: 5.1.1",
+		"Diagnostic-Code: smtp; 550 user unknown",
+	)
+	encoded := "Content-Type: message/delivery-status\n" +
+		"Content-Transfer-Encoding: base64\n\n" +
+		base64.StdEncoding.EncodeToString([]byte(
+			"Reporting-MTA: dns; mail.example.com\r\n\r\n"+
+				"Final-Recipient: rfc822; user@example.com\r\n"+
+				"Action: failed\r\n"+
+				"Status: 5.2.1\r\n",
+		)) + "\n"
+	complaint := "Content-Type: message/feedback-report\n\n" +
+		"Feedback-Type: Abuse\n" +
+		"Original-Rcpt-To: <user@example.com>\n"
+
+	bounce := func(kind, action, status, diagnostic string) model.Bounce {
+		return model.Bounce{
+			Email:      "user@example.com",
+			Type:       kind,
+			Action:     action,
+			Status:     status,
+			Diagnostic: diagnostic,
+			ReceivedAt: receivedAt,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		message     []byte
+		wantEmailID model.ID
+		wantBounces []model.Bounce
+		wantErr     error
+	}{
+		{
+			name:        "hard bounce",
+			message:     reportMessage("multipart/report", failed, headers),
+			wantEmailID: emailID,
+			wantBounces: []model.Bounce{bounce(model.BounceHard, "failed", "5.1.1", "smtp; 550 user unknown")},
+		},
+		{
+			name: "soft bounce",
+			message: reportMessage("multipart/report", deliveryStatus(
+				"Original-Recipient: rfc822; user@example.com",
+				"Action: delayed",
+				"Status: 4.2.2",
+			)),
+			wantBounces: []model.Bounce{bounce(model.BounceSoft, "delayed", "4.2.2", "")},
+		},
+		{
+			name: "delivered",
+			message: reportMessage("multipart/report", deliveryStatus(
+				"Final-Recipient: rfc822; user@example.com",
+				"Action: delivered",
+				"Status: 2.0.0",
+			), headers),
+			wantEmailID: emailID,
+			wantBounces: []model.Bounce{},
+		},
+		{
+			name:        "base64 delivery status",
+			message:     reportMessage("multipart/report", encoded),
+			wantBounces: []model.Bounce{bounce(model.BounceHard, "failed", "5.2.1", "")},
+		},
+		{
+			name:        "complaint",
+			message:     reportMessage("multipart/report", complaint, original),
+			wantEmailID: emailID,
+			wantBounces: []model.Bounce{bounce(model.BounceComplaint, "abuse", "", "")},
+		},
+		{
+			name:    "not report",
+			message: reportMessage("multipart/mixed", failed),
+			wantErr: errNotReport,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseReport(test.message)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("parseReport() error = %v, want %v", err, test.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got.emailID != test.wantEmailID {
+				t.Errorf("parseReport() email ID = %s, want %s", got.emailID, test.wantEmailID)
+			}
+
+			if !reflect.DeepEqual(got.bounces, test.wantBounces) {
+				t.Errorf("parseReport() bounces = %+v, want %+v", got.bounces, test.wantBounces)
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package core
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+const (
+	csvNameColumn     = "name"
+	csvEmailColumn    = "email"
+	maxBulkRecipients = 10000
+)
+
+// ParseBulkCSV reads the recipients from a CSV with a header, the name and
+// email columns are the receiver and the other columns are the template data.
+func ParseBulkCSV(reader io.Reader) ([]model.BulkRecipient, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errors.Join(ErrInvalidCSV, err)
+	}
+
+	nameColumn, emailColumn := -1, -1
+
+	for index, column := range header {
+		header[index] = strings.TrimSpace(column)
+
+		switch header[index] {
+		case csvNameColumn:
+			nameColumn = index
+		case csvEmailColumn:
+			emailColumn = index
+		}
+	}
+
+	if nameColumn < 0 || emailColumn < 0 {
+		return nil, fmt.Errorf("%w: missing name or email column", ErrInvalidCSV)
+	}
+
+	recipients := []model.BulkRecipient{}
+
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Join(ErrInvalidCSV, err)
+		}
+
+		recipient := model.BulkRecipient{
+			Name:  record[nameColumn],
+			Email: record[emailColumn],
+			Data:  map[string]string{},
+		}
+
+		for index, value := range record {
+			if index != nameColumn && index != emailColumn {
+				recipient.Data[header[index]] = value
+			}
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	return recipients, nil
+}
+
+// mergeData returns the shared data overwritten by the recipient data.
+func mergeData(shared map[string]string, data map[string]string) map[string]string {
+	merged := make(map[string]string, len(shared)+len(data))
+
+	for key, value := range shared {
+		merged[key] = value
+	}
+
+	for key, value := range data {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// listRecipients returns one recipient for each email of the email lists, the
+// contact attributes are the template data of the recipient. The unsubscribe
+// links are returned in the same order of the recipients. The lists with more
+// than limit emails are ErrBulkTooManyRecipients.
+func (core *Queue) listRecipients(
+	lists []string,
+	ownerID model.ID,
+	limit int,
+) ([]model.BulkRecipient, []string, error) {
+	recipients := []model.BulkRecipient{}
+	unsubscribes := []string{}
+
+	for _, list := range lists {
+		emailList, err := core.emailList.Get(list, ownerID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = core.emailList.database.EachContact(emailList.ID, func(contact model.ListContact) error {
+			if len(recipients) >= limit {
+				return ErrBulkTooManyRecipients
+			}
+
+			recipient := model.BulkRecipient{
+				Name:  contact.Name,
+				Email: contact.Email,
+				Data:  mergeData(contact.Attributes, nil),
+			}
+
+			if recipient.Name == "" {
+				recipient.Name = emailList.Name
+			}
+
+			if _, exist := recipient.Data[csvNameColumn]; !exist {
+				recipient.Data[csvNameColumn] = recipient.Name
+			}
+
+			if _, exist := recipient.Data[csvEmailColumn]; !exist {
+				recipient.Data[csvEmailColumn] = recipient.Email
+			}
+
+			unsubscribe := core.emailList.UnsubscribeURL(emailList.ID, contact.ID)
+
+			if _, exist := recipient.Data[templateUnsubscribeField]; !exist {
+				recipient.Data[templateUnsubscribeField] = unsubscribe
+			}
+
+			recipients = append(recipients, recipient)
+			unsubscribes = append(unsubscribes, unsubscribe)
+
+			return nil
+		})
+		if errors.Is(err, ErrBulkTooManyRecipients) {
+			return nil, nil, err
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting email list contacts: %w", err)
+		}
+	}
+
+	return recipients, unsubscribes, nil
+}
+
+// SendBulk validates every recipient, the accepted ones are saved on the
+// outbox and the job has the result of each row. The template, email lists and
+// attachments are of the owner. The recipients with the emails of the lists
+// have a max of maxBulkRecipients.
+func (core *Queue) SendBulk(
+	name string,
+	partial model.BulkEmailPartial,
+	userID model.ID,
+	ownerID model.ID,
+) (*model.BulkJob, error) {
+	if len(name) == 0 {
+		return nil, ErrInvalidName
+	}
+
+	err := validate(core.validator, partial)
+	if err != nil {
+		return nil, err
+	}
+
+	queue, err := core.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = core.permission.allowed(queue, userID, model.PermissionSend)
+	if err != nil {
+		return nil, err
+	}
+
+	routing, err := routing(queue, partial.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := core.templateFields(partial.Template, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = core.checkAttachments(ownerID, partial.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	fromLists, fromListsUnsubscribes, err := core.listRecipients(
+		partial.EmailLists,
+		ownerID,
+		maxBulkRecipients-len(partial.Recipients),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]model.BulkRecipient, 0, len(partial.Recipients)+len(fromLists))
+	recipients = append(recipients, partial.Recipients...)
+	recipients = append(recipients, fromLists...)
+
+	unsubscribes := make([]string, len(partial.Recipients), len(recipients))
+	unsubscribes = append(unsubscribes, fromListsUnsubscribes...)
+
+	emailsRecipients := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		emailsRecipients = append(emailsRecipients, recipient.Email)
+	}
+
+	suppressed, err := core.suppression.suppressed(emailsRecipients, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	job := model.BulkJob{
+		ID:        model.NewID(),
+		UserID:    userID,
+		Queue:     queue.Name,
+		Template:  partial.Template,
+		Rows:      make([]model.BulkRow, 0, len(recipients)),
+		CreatedAt: now,
+	}
+
+	emails := make([]model.Email, 0, len(recipients))
+
+	for index, recipient := range recipients {
+		recipient.Data = mergeData(partial.Data, recipient.Data)
+
+		row := model.BulkRow{
+			Row:   index + 1,
+			Email: recipient.Email,
+		}
+
+		err := core.validator.Struct(recipient)
+		if err != nil {
+			row.Error = err.Error()
+			job.Rows = append(job.Rows, row)
+			job.Rejected++
+
+			continue
+		}
+
+		if _, exist := suppressed[normalizeEmail(recipient.Email)]; exist {
+			row.Error = ErrEmailSuppressed.Error()
+			job.Rows = append(job.Rows, row)
+			job.Rejected++
+
+			continue
+		}
+
+		missing := missingFields(fields, recipient.Data)
+		if len(missing) > 0 {
+			row.Error = fmt.Sprintf("%s: %s", ErrMissingFieldTemplates, strings.Join(missing, ", "))
+			job.Rows = append(job.Rows, row)
+			job.Rejected++
+
+			continue
+		}
+
+		email := model.Email{
+			ID:          model.NewID(),
+			UserID:      userID,
+			Receivers:   []model.Receiver{{Name: recipient.Name, Email: recipient.Email}},
+			Subject:     partial.Subject,
+			Template:    &model.TemplateData{Name: partial.Template, Data: recipient.Data},
+			Attachments: partial.Attachments,
+			Priority:    partial.Priority,
+			JobID:       job.ID,
+			Queue:       routing,
+			Status:      model.EmailPending,
+			SentAt:      now,
+			Unsubscribe: unsubscribes[index],
+		}
+
+		row.Accepted = true
+		row.EmailID = email.ID
+		job.Rows = append(job.Rows, row)
+		job.Accepted++
+
+		emails = append(emails, email)
+	}
+
+	// The job is saved first, so every email on the outbox has its job.
+	err = core.database.SaveBulkJob(job)
+	if err != nil {
+		return nil, fmt.Errorf("error saving bulk job in database: %w", err)
+	}
+
+	if len(emails) > 0 {
+		err = core.database.SaveEmails(emails)
+		if err != nil {
+			return nil, fmt.Errorf("error saving emails in database: %w", err)
+		}
+	}
+
+	return &job, nil
+}
+
+func (core *Queue) GetBulkJob(jobID model.ID, userID model.ID) (*model.BulkJob, error) {
+	exist, err := core.database.ExistBulkJob(jobID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if bulk job exist: %w", err)
+	}
+
+	if !exist {
+		return nil, ErrBulkJobDoesNotExist
+	}
+
+	job, err := core.database.GetBulkJob(jobID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bulk job from database: %w", err)
+	}
+
+	return job, nil
+}
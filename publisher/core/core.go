@@ -2,6 +2,7 @@ package core
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	ut "github.com/go-playground/universal-translator"
@@ -22,6 +23,11 @@ var (
 	ErrUserIsProtected               = errors.New("user is protected")
 	ErrQueueAlreadyExist             = errors.New("queue already exist")
 	ErrQueueDoesNotExist             = errors.New("queue does not exist")
+	ErrQueueWithoutPriority          = errors.New("queue was not created with priority")
+	ErrQueueReservedName             = errors.New("queue name can not end with -high or -dlx")
+	ErrInvalidCSV                    = errors.New("was sent a invalid CSV")
+	ErrBulkJobDoesNotExist           = errors.New("bulk job does not exist")
+	ErrBulkTooManyRecipients         = errors.New("bulk has a max of 10000 recipients")
 	ErrBodyValidate                  = errors.New("unable to parse body")
 	ErrTemplateNameAlreadyExist      = errors.New("template name already exist")
 	ErrMaxSizeTemplate               = errors.New("template has a max size of 1MB")
@@ -33,6 +39,34 @@ var (
 	ErrUploadAlreadyConfirmed        = errors.New("upload already confirmed")
 	ErrEmailListAlreadyExist         = errors.New("email list already exist")
 	ErrEmailListDoesNotExist         = errors.New("email list does not exist")
+	ErrInvalidLinkToken              = errors.New("was sent a invalid link token")
+	ErrLinkTokenExpired              = errors.New("link token has expired")
+	ErrSuppressionDoesNotExist       = errors.New("suppression does not exist")
+	ErrAllReceiversSuppressed        = errors.New("all receivers are suppressed")
+	ErrEmailSuppressed               = errors.New("email is suppressed")
+	ErrSubscriptionDisabled          = errors.New("subscription is disabled")
+	ErrSubscriptionDoesNotExist      = errors.New("subscription does not exist")
+	ErrSubscriptionLimit             = errors.New("too many subscriptions, try again later")
+	ErrPasswordResetDisabled         = errors.New("password reset is disabled")
+	ErrPasswordResetInvalid          = errors.New("password reset token is invalid or has expired")
+	ErrPasswordResetLimit            = errors.New("too many password reset requests, try again later")
+	ErrUserTOTPCodeRequired          = errors.New("two-factor authentication code is required")
+	ErrUserWrongTOTPCode             = errors.New("was sent a wrong two-factor authentication code")
+	ErrUserTOTPLocked                = errors.New("too many wrong two-factor authentication codes, try again later")
+	ErrUserTOTPAlreadyEnabled        = errors.New("two-factor authentication is already enabled")
+	ErrUserTOTPNotEnabled            = errors.New("two-factor authentication is not enabled")
+	ErrUserTOTPNotEnrolled           = errors.New("two-factor authentication was not enrolled")
+	ErrAdminTOTPRequired             = errors.New("admins must enable two-factor authentication")
+	ErrAPIKeyDoesNotExist            = errors.New("API key does not exist")
+	ErrAPIKeyExpired                 = errors.New("API key has expired")
+	ErrRoleAlreadyExist              = errors.New("role already exist")
+	ErrRoleDoesNotExist              = errors.New("role does not exist")
+	ErrGrantDoesNotExist             = errors.New("grant does not exist")
+	ErrPermissionDenied              = errors.New("user does not have permission on the queue")
+	ErrOrganizationDoesNotExist      = errors.New("organization does not exist")
+	ErrOrganizationNotOwner          = errors.New("user is not owner of the organization")
+	ErrOrganizationNotMember         = errors.New("user is not member of the organization")
+	ErrOrganizationLastOwner         = errors.New("organization must have an owner")
 )
 
 const (
@@ -86,33 +120,111 @@ type Cores struct {
 	*EmailList
 	*Template
 	*Attachment
+	*Health
+	*Suppression
+	*Subscription
+	*Bounce
+	*APIKey
+	*Permission
+	*Organization
+	*PasswordReset
+}
+
+// StorageConfig configures the buckets of the templates and attachments on
+// Minio.
+type StorageConfig struct {
+	TemplateBucket   string
+	AttachmentBucket string
+	MaxEntrySize     int
+}
+
+// Configs configures the cores, each core that needs more than its
+// dependencies receives its own config.
+type Configs struct {
+	SessionDuration time.Duration
+	Storage         StorageConfig
+	Unsubscribe     UnsubscribeConfig
+	Subscription    SubscriptionConfig
+	PasswordReset   PasswordResetConfig
 }
 
 func NewCores(
 	databases *data.Databases,
 	validate *validator.Validate,
-	sessionDuration time.Duration,
 	rabbit *rabbit.Rabbit,
 	minio *minio.Client,
-	bukcetTemplate string,
-	bukcetAttachment string,
-	maxEntrySize int,
+	configs Configs,
 ) *Cores {
-	template := newTemplate(databases.Template, minio, bukcetTemplate, validate)
+	template := newTemplate(databases.Template, minio, configs.Storage.TemplateBucket, validate)
 	attachment := newAttachment(
 		minio,
-		bukcetAttachment,
+		configs.Storage.AttachmentBucket,
 		databases.Attachment,
 		validate,
-		maxEntrySize,
+		configs.Storage.MaxEntrySize,
+	)
+	emailList := newEmailList(
+		databases.EmailList,
+		validate,
+		signedLink{
+			secret: []byte(configs.Unsubscribe.Secret),
+			url:    strings.TrimSuffix(configs.Unsubscribe.URL, "/"),
+		},
+		configs.Unsubscribe.Expiration,
+	)
+
+	user := newUser(databases.User, validate, configs.SessionDuration)
+	organization := newOrganization(databases.Organization, user, validate)
+	permission := newPermission(databases.Permission, databases.Queue, user, organization, validate)
+
+	suppression := newSuppression(databases.Suppression, validate)
+	queue := newQueue(
+		template,
+		attachment,
+		emailList,
+		suppression,
+		permission,
+		organization,
+		rabbit,
+		databases.Queue,
+		validate,
+	)
+
+	subscription := newSubscription(
+		emailList,
+		queue,
+		databases.EmailList,
+		validate,
+		configs.Subscription,
+	)
+
+	health := newHealth(
+		databases,
+		rabbit,
+		minio,
+		configs.Storage.TemplateBucket,
+		configs.Storage.AttachmentBucket,
 	)
-	emailList := newEmailList(databases.EmailList, validate)
 
 	return &Cores{
-		User:       newUser(databases.User, validate, sessionDuration),
-		Queue:      newQueue(template, attachment, emailList, rabbit, databases.Queue, validate),
-		EmailList:  emailList,
-		Template:   template,
-		Attachment: attachment,
+		User:         user,
+		Queue:        queue,
+		EmailList:    emailList,
+		Template:     template,
+		Attachment:   attachment,
+		Health:       health,
+		Suppression:  suppression,
+		Subscription: subscription,
+		Bounce:       newBounce(databases.Queue, suppression),
+		APIKey:       newAPIKey(databases.APIKey, user, validate),
+		Permission:   permission,
+		Organization: organization,
+		PasswordReset: newPasswordReset(
+			user,
+			queue,
+			databases.User,
+			validate,
+			configs.PasswordReset,
+		),
 	}
 }
@@ -2,18 +2,28 @@ package core
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/thiago-felipe-99/mail/publisher/data"
 	"github.com/thiago-felipe-99/mail/publisher/model"
-	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
+// UnsubscribeConfig configures the signed links of the email lists, the
+// unsubscribe links expire after the expiration.
+type UnsubscribeConfig struct {
+	Secret     string
+	URL        string
+	Expiration time.Duration
+}
+
 type EmailList struct {
-	database  *data.EmailList
-	validator *validator.Validate
+	database              *data.EmailList
+	validator             *validator.Validate
+	links                 signedLink
+	unsubscribeExpiration time.Duration
 }
 
 func uniq[T comparable](data []T) []T {
@@ -28,13 +38,60 @@ func uniq[T comparable](data []T) []T {
 	return uniqs
 }
 
-func (core *EmailList) Create(userID model.ID, partial model.EmailListPartial) error {
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func newListContact(listID model.ID, email string) model.ListContact {
+	return model.ListContact{
+		ID:         model.NewID(),
+		ListID:     listID,
+		Email:      strings.TrimSpace(email),
+		Key:        normalizeEmail(email),
+		Name:       "",
+		Attributes: nil,
+		Consent:    nil,
+	}
+}
+
+// addContacts adds the emails that are not on the list and saves the contacts,
+// a contact replaces the name and attributes of its email.
+func (core *EmailList) addContacts(listID model.ID, emails []string, contacts []model.ContactPartial) error {
+	added := make([]model.ListContact, 0, len(emails))
+	for _, email := range emails {
+		added = append(added, newListContact(listID, email))
+	}
+
+	err := core.database.AddContacts(added)
+	if err != nil {
+		return fmt.Errorf("error adding emails in database: %w", err)
+	}
+
+	saved := make([]model.ListContact, 0, len(contacts))
+
+	for _, partial := range contacts {
+		contact := newListContact(listID, partial.Email)
+		contact.Name = partial.Name
+		contact.Attributes = partial.Attributes
+
+		saved = append(saved, contact)
+	}
+
+	err = core.database.SaveContacts(saved)
+	if err != nil {
+		return fmt.Errorf("error saving contacts in database: %w", err)
+	}
+
+	return nil
+}
+
+func (core *EmailList) Create(ownerID model.ID, userID model.ID, partial model.EmailListPartial) error {
 	err := validate(core.validator, partial)
 	if err != nil {
 		return err
 	}
 
-	exist, err := core.database.ExistByName(partial.Name, userID)
+	exist, err := core.database.ExistByName(partial.Name, ownerID)
 	if err != nil {
 		return fmt.Errorf("error checking if email list exist in database: %w", err)
 	}
@@ -43,23 +100,16 @@ func (core *EmailList) Create(userID model.ID, partial model.EmailListPartial) e
 		return ErrEmailListAlreadyExist
 	}
 
-	uniqEmails := uniq(partial.Emails)
-
-	emails := make(map[model.ID]string, len(uniqEmails))
-	for _, email := range uniqEmails {
-		emails[model.NewID()] = email
-	}
-
 	list := model.EmailList{
-		ID:          model.NewID(),
-		Emails:      emails,
-		Name:        partial.Name,
-		EmailAlias:  partial.EmailAlias,
-		Description: partial.Description,
-		CreatedAt:   time.Now(),
-		CreatedBy:   userID,
-		DeletedAt:   time.Time{},
-		DeletedBy:   model.ID{},
+		ID:             model.NewID(),
+		Name:           partial.Name,
+		EmailAlias:     partial.EmailAlias,
+		Description:    partial.Description,
+		OrganizationID: organizationOf(ownerID, userID),
+		CreatedAt:      time.Now(),
+		CreatedBy:      userID,
+		DeletedAt:      time.Time{},
+		DeletedBy:      model.ID{},
 	}
 
 	err = core.database.Create(list)
@@ -67,11 +117,11 @@ func (core *EmailList) Create(userID model.ID, partial model.EmailListPartial) e
 		return fmt.Errorf("error creating email list in database: %w", err)
 	}
 
-	return nil
+	return core.addContacts(list.ID, partial.Emails, partial.Contacts)
 }
 
-func (core *EmailList) GetAll(userID model.ID) ([]model.EmailList, error) {
-	emailList, err := core.database.GetAllUser(userID)
+func (core *EmailList) GetAll(ownerID model.ID) ([]model.EmailList, error) {
+	emailList, err := core.database.GetAllUser(ownerID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting email list: %w", err)
 	}
@@ -79,8 +129,8 @@ func (core *EmailList) GetAll(userID model.ID) ([]model.EmailList, error) {
 	return emailList, nil
 }
 
-func (core *EmailList) Get(name string, userID model.ID) (*model.EmailList, error) {
-	exist, err := core.database.ExistByName(name, userID)
+func (core *EmailList) Get(name string, ownerID model.ID) (*model.EmailList, error) {
+	exist, err := core.database.ExistByName(name, ownerID)
 	if err != nil {
 		return nil, fmt.Errorf("error checking if email list exist in database: %w", err)
 	}
@@ -89,7 +139,7 @@ func (core *EmailList) Get(name string, userID model.ID) (*model.EmailList, erro
 		return nil, ErrEmailListDoesNotExist
 	}
 
-	emailList, err := core.database.GetByName(name, userID)
+	emailList, err := core.database.GetByName(name, ownerID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting email list: %w", err)
 	}
@@ -97,19 +147,52 @@ func (core *EmailList) Get(name string, userID model.ID) (*model.EmailList, erro
 	return emailList, nil
 }
 
-func (core *EmailList) UpdateInfo(name string, userID model.ID, info model.EmailListInfo) error {
+// GetWithContacts returns the list with its emails and contacts.
+func (core *EmailList) GetWithContacts(name string, ownerID model.ID) (*model.EmailListContacts, error) {
+	emailList, err := core.Get(name, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	withContacts := &model.EmailListContacts{
+		EmailList: *emailList,
+		Emails:    map[model.ID]string{},
+		Contacts:  map[model.ID]model.Contact{},
+	}
+
+	err = core.database.EachContact(emailList.ID, func(contact model.ListContact) error {
+		withContacts.Emails[contact.ID] = contact.Email
+
+		if contact.Name != "" || len(contact.Attributes) > 0 || contact.Consent != nil {
+			withContacts.Contacts[contact.ID] = model.Contact{
+				Name:       contact.Name,
+				Attributes: contact.Attributes,
+				Consent:    contact.Consent,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting email list contacts: %w", err)
+	}
+
+	return withContacts, nil
+}
+
+func (core *EmailList) UpdateInfo(name string, ownerID model.ID, info model.EmailListInfo) error {
 	err := validate(core.validator, info)
 	if err != nil {
 		return err
 	}
 
-	emailList, err := core.Get(name, userID)
+	emailList, err := core.Get(name, ownerID)
 	if err != nil {
 		return err
 	}
 
 	if emailList.Name != info.Name {
-		exist, err := core.database.ExistByName(info.Name, userID)
+		exist, err := core.database.ExistByName(info.Name, ownerID)
 		if err != nil {
 			return fmt.Errorf("error checking if email list exist in database: %w", err)
 		}
@@ -127,8 +210,8 @@ func (core *EmailList) UpdateInfo(name string, userID model.ID, info model.Email
 	return nil
 }
 
-func (core *EmailList) Delete(name string, userID model.ID, deletedBy model.ID) error {
-	emailList, err := core.Get(name, userID)
+func (core *EmailList) Delete(name string, ownerID model.ID, deletedBy model.ID) error {
+	emailList, err := core.Get(name, ownerID)
 	if err != nil {
 		return err
 	}
@@ -144,36 +227,23 @@ func (core *EmailList) Delete(name string, userID model.ID, deletedBy model.ID)
 	return nil
 }
 
-func (core *EmailList) AddEmails(name string, userID model.ID, emails model.EmailListEmails) error {
+func (core *EmailList) AddEmails(name string, ownerID model.ID, emails model.EmailListEmails) error {
 	err := validate(core.validator, emails)
 	if err != nil {
 		return err
 	}
 
-	emailList, err := core.Get(name, userID)
+	emailList, err := core.Get(name, ownerID)
 	if err != nil {
 		return err
 	}
 
-	emailsAlreadyExist := maps.Values(emailList.Emails)
-
-	for _, email := range emails.Emails {
-		if !slices.Contains(emailsAlreadyExist, email) {
-			emailList.Emails[model.NewID()] = email
-		}
-	}
-
-	err = core.database.Update(*emailList)
-	if err != nil {
-		return fmt.Errorf("error adding emails: %w", err)
-	}
-
-	return nil
+	return core.addContacts(emailList.ID, emails.Emails, emails.Contacts)
 }
 
 func (core *EmailList) RemoveEmails(
 	name string,
-	userID model.ID,
+	ownerID model.ID,
 	emails model.EmailListEmails,
 ) error {
 	err := validate(core.validator, emails)
@@ -181,20 +251,21 @@ func (core *EmailList) RemoveEmails(
 		return err
 	}
 
-	emailList, err := core.Get(name, userID)
+	emailList, err := core.Get(name, ownerID)
 	if err != nil {
 		return err
 	}
 
-	emailsAlreadyExist := maps.Values(emailList.Emails)
-
-	del := func(k model.ID, v string) bool {
-		return slices.Contains(emailsAlreadyExist, v)
+	keys := make([]string, 0, len(emails.Emails)+len(emails.Contacts))
+	for _, email := range emails.Emails {
+		keys = append(keys, normalizeEmail(email))
 	}
 
-	maps.DeleteFunc(emailList.Emails, del)
+	for _, contact := range emails.Contacts {
+		keys = append(keys, normalizeEmail(contact.Email))
+	}
 
-	err = core.database.Update(*emailList)
+	err = core.database.RemoveContacts(emailList.ID, keys)
 	if err != nil {
 		return fmt.Errorf("error removing emails: %w", err)
 	}
@@ -202,19 +273,13 @@ func (core *EmailList) RemoveEmails(
 	return nil
 }
 
-func (core *EmailList) RemoveEmail(name string, userID model.ID, email model.ID) error {
-	emailList, err := core.Get(name, userID)
+func (core *EmailList) RemoveEmail(name string, ownerID model.ID, email model.ID) error {
+	emailList, err := core.Get(name, ownerID)
 	if err != nil {
 		return err
 	}
 
-	del := func(k model.ID, v string) bool {
-		return k == email
-	}
-
-	maps.DeleteFunc(emailList.Emails, del)
-
-	err = core.database.Update(*emailList)
+	_, err = core.database.RemoveContact(emailList.ID, email)
 	if err != nil {
 		return fmt.Errorf("error removing email: %w", err)
 	}
@@ -225,9 +290,13 @@ func (core *EmailList) RemoveEmail(name string, userID model.ID, email model.ID)
 func newEmailList(
 	database *data.EmailList,
 	validate *validator.Validate,
+	links signedLink,
+	unsubscribeExpiration time.Duration,
 ) *EmailList {
 	return &EmailList{
-		database:  database,
-		validator: validate,
+		database:              database,
+		validator:             validate,
+		links:                 links,
+		unsubscribeExpiration: unsubscribeExpiration,
 	}
 }
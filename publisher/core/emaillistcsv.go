@@ -0,0 +1,324 @@
+package core
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/thiago-felipe-99/mail/publisher/model"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// maxImportErrors bounds the rows reported on the import, the rest are
+	// only counted.
+	maxImportErrors = 1000
+	// importBatchSize is the number of rows saved at once on the import.
+	importBatchSize = 1000
+)
+
+// importColumns are the positions of the mapped columns on the CSV.
+type importColumns struct {
+	email      int
+	name       int
+	attributes map[int]string
+}
+
+func findImportColumns(header []string, options model.EmailListImportOptions) (*importColumns, error) {
+	if options.EmailColumn == "" {
+		options.EmailColumn = csvEmailColumn
+	}
+
+	if options.NameColumn == "" {
+		options.NameColumn = csvNameColumn
+	}
+
+	columns := &importColumns{
+		email:      -1,
+		name:       -1,
+		attributes: map[int]string{},
+	}
+
+	for index, column := range header {
+		column = strings.TrimSpace(column)
+
+		switch {
+		case column == options.EmailColumn:
+			columns.email = index
+		case column == options.NameColumn:
+			columns.name = index
+		case len(options.Attributes) == 0 || slices.Contains(options.Attributes, column):
+			columns.attributes[index] = column
+		}
+	}
+
+	if columns.email < 0 {
+		return nil, fmt.Errorf("%w: missing '%s' column", ErrInvalidCSV, options.EmailColumn)
+	}
+
+	found := make([]string, 0, len(columns.attributes))
+	for _, attribute := range columns.attributes {
+		found = append(found, attribute)
+	}
+
+	for _, attribute := range options.Attributes {
+		if !slices.Contains(found, attribute) {
+			return nil, fmt.Errorf("%w: missing '%s' column", ErrInvalidCSV, attribute)
+		}
+	}
+
+	return columns, nil
+}
+
+// importRow is a valid row of the import, the contact without name and
+// attributes only adds the email.
+type importRow struct {
+	contact model.ListContact
+	info    bool
+}
+
+// changed is true when the row replaces the name or attributes of the saved
+// contact.
+func (row importRow) changed(saved model.ListContact) bool {
+	return row.info && (row.contact.Name != saved.Name || !maps.Equal(row.contact.Attributes, saved.Attributes))
+}
+
+// importRows counts the rows of the batch as added, updated or unchanged
+// comparing with the saved contacts, the rows are saved when it is not a dry
+// run.
+func (core *EmailList) importRows(
+	listID model.ID,
+	rows []importRow,
+	dryRun bool,
+	result *model.EmailListImport,
+) error {
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, row.contact.Key)
+	}
+
+	savedContacts, err := core.database.GetContactsByKeys(listID, keys)
+	if err != nil {
+		return fmt.Errorf("error getting saved contacts: %w", err)
+	}
+
+	saved := make(map[string]model.ListContact, len(savedContacts))
+	for _, contact := range savedContacts {
+		saved[contact.Key] = contact
+	}
+
+	added := []model.ListContact{}
+	updated := []model.ListContact{}
+
+	for _, row := range rows {
+		savedContact, exist := saved[row.contact.Key]
+
+		switch {
+		case !exist:
+			result.Added++
+
+			if row.info {
+				updated = append(updated, row.contact)
+			} else {
+				added = append(added, row.contact)
+			}
+		case row.changed(savedContact):
+			result.Updated++
+
+			updated = append(updated, row.contact)
+		default:
+			result.Unchanged++
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	err = core.database.AddContacts(added)
+	if err != nil {
+		return fmt.Errorf("error importing emails: %w", err)
+	}
+
+	err = core.database.SaveContacts(updated)
+	if err != nil {
+		return fmt.Errorf("error importing contacts: %w", err)
+	}
+
+	return nil
+}
+
+// ImportCSV reads the contacts from a CSV row by row and saves them in
+// batches, the invalid and duplicated rows are reported and ignored. The
+// emails are compared without case. On dry run the list is not changed.
+func (core *EmailList) ImportCSV(
+	name string,
+	ownerID model.ID,
+	reader io.Reader,
+	options model.EmailListImportOptions,
+) (*model.EmailListImport, error) {
+	emailList, err := core.Get(name, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.FieldsPerRecord = -1
+	csvReader.ReuseRecord = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errors.Join(ErrInvalidCSV, err)
+	}
+
+	columns, err := findImportColumns(header, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.EmailListImport{
+		DryRun: options.DryRun,
+		Errors: []model.ImportError{},
+	}
+
+	report := func(row int, email string, err string) {
+		if len(result.Errors) < maxImportErrors {
+			result.Errors = append(result.Errors, model.ImportError{
+				Row:   row,
+				Email: email,
+				Error: err,
+			})
+		}
+	}
+
+	imported := map[string]struct{}{}
+	rows := make([]importRow, 0, importBatchSize)
+
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Join(ErrInvalidCSV, err)
+		}
+
+		result.Rows++
+
+		if columns.email >= len(record) {
+			result.Invalid++
+			report(result.Rows, "", "missing email column")
+
+			continue
+		}
+
+		email := strings.TrimSpace(record[columns.email])
+
+		err = core.validator.Var(email, "required,email")
+		if err != nil {
+			result.Invalid++
+			report(result.Rows, email, "invalid email")
+
+			continue
+		}
+
+		row := importRow{contact: newListContact(emailList.ID, email), info: false}
+
+		if _, exist := imported[row.contact.Key]; exist {
+			result.Duplicated++
+			report(result.Rows, email, "duplicated email")
+
+			continue
+		}
+
+		imported[row.contact.Key] = struct{}{}
+
+		if columns.name >= 0 && columns.name < len(record) {
+			row.contact.Name = strings.TrimSpace(record[columns.name])
+		}
+
+		row.contact.Attributes = map[string]string{}
+
+		for index, attribute := range columns.attributes {
+			if index < len(record) && record[index] != "" {
+				row.contact.Attributes[attribute] = record[index]
+			}
+		}
+
+		row.info = row.contact.Name != "" || len(row.contact.Attributes) > 0
+
+		rows = append(rows, row)
+
+		if len(rows) == importBatchSize {
+			err := core.importRows(emailList.ID, rows, options.DryRun, result)
+			if err != nil {
+				return nil, err
+			}
+
+			rows = rows[:0]
+		}
+	}
+
+	if len(rows) > 0 {
+		err := core.importRows(emailList.ID, rows, options.DryRun, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// WriteCSV writes the list emails sorted with the name and the contact
+// attributes as columns, the contacts are read one by one.
+func (core *EmailList) WriteCSV(emailList *model.EmailList, writer io.Writer) error {
+	columns, err := core.database.ContactAttributes(emailList.ID)
+	if err != nil {
+		return fmt.Errorf("error getting contacts attributes: %w", err)
+	}
+
+	sort.Strings(columns)
+
+	csvWriter := csv.NewWriter(writer)
+
+	record := make([]string, 0, len(columns)+2)
+	record = append(record, csvEmailColumn, csvNameColumn)
+	record = append(record, columns...)
+
+	err = csvWriter.Write(record)
+	if err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+
+	err = core.database.EachContact(emailList.ID, func(contact model.ListContact) error {
+		record = append(record[:0], contact.Email, contact.Name)
+		for _, column := range columns {
+			record = append(record, contact.Attributes[column])
+		}
+
+		err := csvWriter.Write(record)
+		if err != nil {
+			return fmt.Errorf("error writing CSV row: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	csvWriter.Flush()
+
+	err = csvWriter.Error()
+	if err != nil {
+		return fmt.Errorf("error writing CSV: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/thiago-felipe-99/mail/publisher/data"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+	"github.com/thiago-felipe-99/mail/rabbit"
+)
+
+var (
+	errRabbitDisconnected = errors.New("RabbitMQ connection is closed")
+	errBucketDoesNotExist = errors.New("bucket does not exist")
+)
+
+const healthTimeout = 5 * time.Second
+
+type Health struct {
+	databases *data.Databases
+	rabbit    *rabbit.Rabbit
+	minio     *minio.Client
+	buckets   []string
+}
+
+func (core *Health) checkMongo(ctx context.Context) error {
+	return core.databases.Ping(ctx) //nolint:wrapcheck
+}
+
+func (core *Health) checkRabbit(_ context.Context) error {
+	if !core.rabbit.IsConnected() {
+		return errRabbitDisconnected
+	}
+
+	return nil
+}
+
+func (core *Health) checkMinio(ctx context.Context) error {
+	for _, bucket := range core.buckets {
+		exist, err := core.minio.BucketExists(ctx, bucket)
+		if err != nil {
+			return fmt.Errorf("error checking bucket '%s': %w", bucket, err)
+		}
+
+		if !exist {
+			return fmt.Errorf("%w: %s", errBucketDoesNotExist, bucket)
+		}
+	}
+
+	return nil
+}
+
+// Ready checks all dependencies, the publisher is healthy only if all of them
+// are.
+func (core *Health) Ready() model.Health {
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	checks := []struct {
+		name  string
+		check func(context.Context) error
+	}{
+		{"mongo", core.checkMongo},
+		{"rabbit", core.checkRabbit},
+		{"minio", core.checkMinio},
+	}
+
+	health := model.Health{
+		Healthy:      true,
+		Dependencies: make([]model.DependencyHealth, 0, len(checks)),
+	}
+
+	for _, check := range checks {
+		dependency := model.DependencyHealth{Name: check.name, Healthy: true}
+
+		err := check.check(ctx)
+		if err != nil {
+			dependency.Healthy = false
+			dependency.Error = err.Error()
+			health.Healthy = false
+		}
+
+		health.Dependencies = append(health.Dependencies, dependency)
+	}
+
+	return health
+}
+
+func newHealth(
+	databases *data.Databases,
+	rabbit *rabbit.Rabbit,
+	minio *minio.Client,
+	buckets ...string,
+) *Health {
+	return &Health{
+		databases: databases,
+		rabbit:    rabbit,
+		minio:     minio,
+		buckets:   buckets,
+	}
+}
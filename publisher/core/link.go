@@ -0,0 +1,108 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+const (
+	idSize   = 16
+	linkSize = 2*idSize + 8
+)
+
+// The purposes of the links, a token of a purpose is invalid to others.
+const (
+	linkUnsubscribe = "unsubscribe"
+	linkSubscribe   = "subscribe"
+)
+
+// signedLink creates the public links sent on the emails.
+type signedLink struct {
+	secret []byte
+	url    string
+}
+
+func (link signedLink) sign(purpose string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, link.secret)
+	mac.Write([]byte(purpose))
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
+
+// token is the list ID, the email ID and the expiration signed with HMAC, it
+// does not reveal the email.
+func (link signedLink) token(purpose string, listID, emailID model.ID, expires time.Time) string {
+	payload := make([]byte, 0, linkSize)
+	payload = append(payload, listID[:]...)
+	payload = append(payload, emailID[:]...)
+	payload = binary.BigEndian.AppendUint64(payload, uint64(expires.Unix()))
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(link.sign(purpose, payload))
+}
+
+func (link signedLink) parse(purpose string, token string) (model.ID, model.ID, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return model.ID{}, model.ID{}, ErrInvalidLinkToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != linkSize {
+		return model.ID{}, model.ID{}, ErrInvalidLinkToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, link.sign(purpose, payload)) {
+		return model.ID{}, model.ID{}, ErrInvalidLinkToken
+	}
+
+	expires := time.Unix(int64(binary.BigEndian.Uint64(payload[2*idSize:])), 0)
+	if time.Now().After(expires) {
+		return model.ID{}, model.ID{}, ErrLinkTokenExpired
+	}
+
+	var listID, emailID model.ID
+
+	copy(listID[:], payload[:idSize])
+	copy(emailID[:], payload[idSize:2*idSize])
+
+	return listID, emailID, nil
+}
+
+// link is the public URL of the route with the token.
+func (link signedLink) link(route string, token string) string {
+	return link.url + route + token
+}
+
+// linkList returns the list and the email ID of the link token.
+func (core *EmailList) linkList(purpose string, token string) (*model.EmailList, model.ID, error) {
+	listID, emailID, err := core.links.parse(purpose, token)
+	if err != nil {
+		return nil, model.ID{}, err
+	}
+
+	exist, err := core.database.Exist(listID)
+	if err != nil {
+		return nil, model.ID{}, fmt.Errorf("error checking if email list exist in database: %w", err)
+	}
+
+	if !exist {
+		return nil, model.ID{}, ErrEmailListDoesNotExist
+	}
+
+	emailList, err := core.database.GetByID(listID)
+	if err != nil {
+		return nil, model.ID{}, fmt.Errorf("error getting email list: %w", err)
+	}
+
+	return emailList, emailID, nil
+}
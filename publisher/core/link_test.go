@@ -0,0 +1,64 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+func TestSignedLinkParse(t *testing.T) {
+	t.Parallel()
+
+	link := signedLink{secret: []byte("secret"), url: "http://localhost"}
+	listID := model.NewID()
+	emailID := model.NewID()
+	valid := link.token(linkUnsubscribe, listID, emailID, time.Now().Add(time.Hour))
+	payload, signature, _ := strings.Cut(valid, ".")
+
+	tests := []struct {
+		name    string
+		link    signedLink
+		purpose string
+		token   string
+		err     error
+	}{
+		{"valid", link, linkUnsubscribe, valid, nil},
+		{"other purpose", link, linkSubscribe, valid, ErrInvalidLinkToken},
+		{"other secret", signedLink{secret: []byte("other")}, linkUnsubscribe, valid, ErrInvalidLinkToken},
+		{
+			"expired",
+			link,
+			linkUnsubscribe,
+			link.token(linkUnsubscribe, listID, emailID, time.Now().Add(-time.Hour)),
+			ErrLinkTokenExpired,
+		},
+		{"without signature", link, linkUnsubscribe, payload, ErrInvalidLinkToken},
+		{"short payload", link, linkUnsubscribe, payload[1:] + "." + signature, ErrInvalidLinkToken},
+		{"invalid base64", link, linkUnsubscribe, "!" + valid, ErrInvalidLinkToken},
+		{"empty", link, linkUnsubscribe, "", ErrInvalidLinkToken},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotListID, gotEmailID, err := test.link.parse(test.purpose, test.token)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+
+			if test.err != nil {
+				return
+			}
+
+			if gotListID != listID || gotEmailID != emailID {
+				t.Fatalf("expected IDs %s and %s, got %s and %s", listID, emailID, gotListID, gotEmailID)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/thiago-felipe-99/mail/publisher/data"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+// Organization shares the resources between its members, the owners manage
+// the members. A request on an organization uses the organization ID as the
+// owner of the resources instead of the user ID.
+type Organization struct {
+	database  *data.Organization
+	user      *User
+	validator *validator.Validate
+}
+
+// organizationOf returns the organization of the owner, the user is the owner
+// of the personal resources.
+func organizationOf(ownerID model.ID, userID model.ID) model.ID {
+	if ownerID == userID {
+		return model.ID{}
+	}
+
+	return ownerID
+}
+
+// owned is true when the resource is of the organization or a personal one of
+// the user.
+func owned(organizationID model.ID, createdBy model.ID, ownerID model.ID) bool {
+	if organizationID != (model.ID{}) {
+		return organizationID == ownerID
+	}
+
+	return createdBy == ownerID
+}
+
+func (core *Organization) Create(partial model.OrganizationPartial, userID model.ID) error {
+	err := validate(core.validator, partial)
+	if err != nil {
+		return err
+	}
+
+	organization := model.Organization{
+		ID:        model.NewID(),
+		Name:      partial.Name,
+		Members:   map[model.ID]string{userID: model.OrganizationOwner},
+		CreatedAt: time.Now(),
+		CreatedBy: userID,
+		DeletedAt: time.Time{},
+		DeletedBy: model.ID{},
+	}
+
+	err = core.database.Create(organization)
+	if err != nil {
+		return fmt.Errorf("error creating organization in database: %w", err)
+	}
+
+	return nil
+}
+
+func (core *Organization) exist(organizationID model.ID) (bool, error) {
+	exist, err := core.database.Exist(organizationID)
+	if err != nil {
+		return false, fmt.Errorf("error checking if organization exist in database: %w", err)
+	}
+
+	return exist, nil
+}
+
+func (core *Organization) get(organizationID model.ID) (*model.Organization, error) {
+	exist, err := core.exist(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return nil, ErrOrganizationDoesNotExist
+	}
+
+	organization, err := core.database.Get(organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting organization from database: %w", err)
+	}
+
+	return organization, nil
+}
+
+// Get returns the organization when the user is a member.
+func (core *Organization) Get(organizationID model.ID, userID model.ID) (*model.Organization, error) {
+	organization, err := core.get(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, member := organization.Members[userID]; !member {
+		return nil, ErrOrganizationDoesNotExist
+	}
+
+	return organization, nil
+}
+
+func (core *Organization) GetAll(userID model.ID) ([]model.Organization, error) {
+	organizations, err := core.database.GetByMember(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting organizations from database: %w", err)
+	}
+
+	return organizations, nil
+}
+
+// IsMember is false when the organization does not exist.
+func (core *Organization) IsMember(organizationID model.ID, userID model.ID) (bool, error) {
+	exist, err := core.exist(organizationID)
+	if err != nil || !exist {
+		return false, err
+	}
+
+	organization, err := core.database.Get(organizationID)
+	if err != nil {
+		return false, fmt.Errorf("error getting organization from database: %w", err)
+	}
+
+	_, member := organization.Members[userID]
+
+	return member, nil
+}
+
+func (core *Organization) getAsOwner(organizationID model.ID, userID model.ID) (*model.Organization, error) {
+	organization, err := core.Get(organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if organization.Members[userID] != model.OrganizationOwner {
+		return nil, ErrOrganizationNotOwner
+	}
+
+	return organization, nil
+}
+
+// owners returns the number of owners of the organization.
+func owners(organization *model.Organization) int {
+	count := 0
+
+	for _, role := range organization.Members {
+		if role == model.OrganizationOwner {
+			count++
+		}
+	}
+
+	return count
+}
+
+// AddMember adds the user to the organization or changes its role, the
+// organization always has an owner.
+func (core *Organization) AddMember(
+	organizationID model.ID,
+	memberID model.ID,
+	partial model.OrganizationMemberPartial,
+	userID model.ID,
+) error {
+	err := validate(core.validator, partial)
+	if err != nil {
+		return err
+	}
+
+	organization, err := core.getAsOwner(organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	exist, err := core.user.existByID(memberID)
+	if err != nil {
+		return err
+	}
+
+	if !exist {
+		return ErrUserDoesNotExist
+	}
+
+	if organization.Members[memberID] == model.OrganizationOwner &&
+		partial.Role != model.OrganizationOwner &&
+		owners(organization) == 1 {
+		return ErrOrganizationLastOwner
+	}
+
+	organization.Members[memberID] = partial.Role
+
+	err = core.database.Update(*organization)
+	if err != nil {
+		return fmt.Errorf("error adding organization member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember removes the user from the organization, a member can remove
+// itself.
+func (core *Organization) RemoveMember(organizationID model.ID, memberID model.ID, userID model.ID) error {
+	organization, err := core.Get(organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	if memberID != userID && organization.Members[userID] != model.OrganizationOwner {
+		return ErrOrganizationNotOwner
+	}
+
+	role, member := organization.Members[memberID]
+	if !member {
+		return ErrOrganizationNotMember
+	}
+
+	if role == model.OrganizationOwner && owners(organization) == 1 {
+		return ErrOrganizationLastOwner
+	}
+
+	delete(organization.Members, memberID)
+
+	err = core.database.Update(*organization)
+	if err != nil {
+		return fmt.Errorf("error removing organization member: %w", err)
+	}
+
+	return nil
+}
+
+func (core *Organization) Delete(organizationID model.ID, userID model.ID) error {
+	organization, err := core.getAsOwner(organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	organization.DeletedAt = time.Now()
+	organization.DeletedBy = userID
+
+	err = core.database.Update(*organization)
+	if err != nil {
+		return fmt.Errorf("error deleting organization: %w", err)
+	}
+
+	return nil
+}
+
+func newOrganization(database *data.Organization, user *User, validate *validator.Validate) *Organization {
+	return &Organization{
+		database:  database,
+		user:      user,
+		validator: validate,
+	}
+}
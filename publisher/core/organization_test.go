@@ -0,0 +1,57 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+func TestOrganizationOf(t *testing.T) {
+	t.Parallel()
+
+	userID := model.NewID()
+	organizationID := model.NewID()
+
+	if got := organizationOf(userID, userID); got != (model.ID{}) {
+		t.Errorf("organizationOf(user, user) = %s, want an empty ID", got)
+	}
+
+	if got := organizationOf(organizationID, userID); got != organizationID {
+		t.Errorf("organizationOf(organization, user) = %s, want %s", got, organizationID)
+	}
+}
+
+func TestOwned(t *testing.T) {
+	t.Parallel()
+
+	userID := model.NewID()
+	otherUserID := model.NewID()
+	organizationID := model.NewID()
+	otherOrganizationID := model.NewID()
+
+	type resource struct {
+		organizationID model.ID
+		createdBy      model.ID
+	}
+
+	tests := []struct {
+		name     string
+		resource resource
+		ownerID  model.ID
+		want     bool
+	}{
+		{"personal of the user", resource{model.ID{}, userID}, userID, true},
+		{"personal of other user", resource{model.ID{}, otherUserID}, userID, false},
+		{"of the organization", resource{organizationID, otherUserID}, organizationID, true},
+		{"of other organization", resource{otherOrganizationID, userID}, organizationID, false},
+		{"of an organization created by the user", resource{organizationID, userID}, userID, false},
+		{"personal with organization owner", resource{model.ID{}, userID}, organizationID, false},
+	}
+
+	for _, test := range tests {
+		got := owned(test.resource.organizationID, test.resource.createdBy, test.ownerID)
+		if got != test.want {
+			t.Errorf("%s: owned() = %t, want %t", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/thiago-felipe-99/mail/publisher/data"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+const (
+	passwordResetTokenSize   = 32
+	passwordResetLimitWindow = time.Hour
+)
+
+// PasswordReset sends a single use token to the user email, the token sets a
+// new password without a session.
+type PasswordReset struct {
+	user      *User
+	queue     *Queue
+	database  *data.User
+	validator *validator.Validate
+	config    PasswordResetConfig
+}
+
+// PasswordResetConfig configures the password reset emails, the URL receives
+// the token on the query, and the limits of the requests.
+type PasswordResetConfig struct {
+	Queue       string
+	Template    string
+	Subject     string
+	URL         string
+	Expiration  time.Duration
+	MaxPerIP    int
+	MaxPerEmail int
+}
+
+func (core *PasswordReset) link(token string) (string, error) {
+	link, err := url.Parse(core.config.URL)
+	if err != nil {
+		return "", fmt.Errorf("error parsing password reset URL: %w", err)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
+
+// limited checks the requests of the IP and to the email in the last
+// passwordResetLimitWindow.
+func (core *PasswordReset) limited(key string, requestIP string) (bool, error) {
+	since := time.Now().Add(-passwordResetLimitWindow)
+
+	requests, err := core.database.CountForgotByIP(requestIP, since)
+	if err != nil || requests >= core.config.MaxPerIP {
+		return true, err
+	}
+
+	requests, err = core.database.CountForgotByKey(key, since)
+	if err != nil || requests >= core.config.MaxPerEmail {
+		return true, err
+	}
+
+	return false, nil
+}
+
+// Forgot limits the requests and sends the reset email in background, the
+// response is the same when the email does not have a user to not reveal
+// which emails are registered.
+func (core *PasswordReset) Forgot(partial model.PasswordForgot, requestIP string) error {
+	if core.config.Queue == "" || core.config.Template == "" {
+		return ErrPasswordResetDisabled
+	}
+
+	err := validate(core.validator, partial)
+	if err != nil {
+		return err
+	}
+
+	key := normalizeEmail(partial.Email)
+
+	limited, err := core.limited(key, requestIP)
+	if err != nil {
+		return fmt.Errorf("error checking password reset limit: %w", err)
+	}
+
+	if limited {
+		return ErrPasswordResetLimit
+	}
+
+	now := time.Now()
+
+	request := model.PasswordForgotRequest{
+		ID:          model.NewID(),
+		Key:         key,
+		RequestIP:   requestIP,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(passwordResetLimitWindow),
+	}
+
+	err = core.database.SaveForgot(request)
+	if err != nil {
+		return fmt.Errorf("error saving password reset request in database: %w", err)
+	}
+
+	go func() {
+		err := core.send(partial.Email)
+		if err != nil {
+			log.Printf("[ERROR] - Error sending password reset: %s", err)
+		}
+	}()
+
+	return nil
+}
+
+// send creates the token and sends the reset email, an email without user is
+// ignored.
+func (core *PasswordReset) send(email string) error {
+	user, err := core.user.GetByNameOrEmail("", email)
+	if errors.Is(err, ErrUserDoesNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	random := make([]byte, passwordResetTokenSize)
+
+	_, err = rand.Read(random)
+	if err != nil {
+		return fmt.Errorf("error generating password reset token: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(random)
+
+	link, err := core.link(token)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	reset := model.PasswordReset{
+		ID:        model.NewID(),
+		UserID:    user.ID,
+		Hash:      hashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(core.config.Expiration),
+		UsedAt:    time.Time{},
+	}
+
+	err = core.database.SaveReset(reset)
+	if err != nil {
+		return fmt.Errorf("error saving password reset in database: %w", err)
+	}
+
+	resetEmail := model.EmailPartial{
+		Receivers: []model.Receiver{{Name: user.Name, Email: user.Email}},
+		Subject:   core.config.Subject,
+		Template: &model.TemplateData{
+			Name: core.config.Template,
+			Data: map[string]string{
+				csvNameColumn:  user.Name,
+				csvEmailColumn: user.Email,
+				"token":        token,
+				"reset":        link,
+			},
+		},
+	}
+
+	// The reset is sent by the publisher, the user does not need the send
+	// permission on the queue and the template is not of the user. Only the
+	// hash of the token is saved, the email is not saved on the queue history.
+	err = core.queue.sendUnsaved(core.config.Queue, resetEmail, user.ID)
+	if err != nil {
+		return fmt.Errorf("error sending password reset email: %w", err)
+	}
+
+	return nil
+}
+
+// Reset sets the new password, all tokens of the user are used.
+func (core *PasswordReset) Reset(partial model.PasswordResetPartial) error {
+	err := validate(core.validator, partial)
+	if err != nil {
+		return err
+	}
+
+	// The token is claimed before the password is set, a token sent twice at
+	// the same time only sets one password.
+	reset, err := core.database.ClaimReset(hashToken(partial.Token))
+	if err != nil {
+		return err
+	}
+
+	if reset == nil {
+		return ErrPasswordResetInvalid
+	}
+
+	user, err := core.user.GetByID(reset.UserID)
+	if errors.Is(err, ErrUserDoesNotExist) {
+		return ErrPasswordResetInvalid
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = core.database.UseResets(user.ID)
+	if err != nil {
+		return fmt.Errorf("error using password reset: %w", err)
+	}
+
+	return core.user.setPassword(user, partial.Password)
+}
+
+func newPasswordReset(
+	user *User,
+	queue *Queue,
+	database *data.User,
+	validate *validator.Validate,
+	config PasswordResetConfig,
+) *PasswordReset {
+	return &PasswordReset{
+		user:      user,
+		queue:     queue,
+		database:  database,
+		validator: validate,
+		config:    config,
+	}
+}
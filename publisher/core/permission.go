@@ -0,0 +1,280 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/thiago-felipe-99/mail/publisher/data"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+	"golang.org/x/exp/slices"
+)
+
+// Permission has the roles and the grants of the users on the queues, the
+// admins have all permissions.
+type Permission struct {
+	database     *data.Permission
+	queues       *data.Queue
+	user         *User
+	organization *Organization
+	validator    *validator.Validate
+}
+
+// grantID is the same for the queue of an user, granting again replaces the
+// role.
+func grantID(queue string, userID model.ID) model.ID {
+	return model.ID(uuid.NewSHA1(uuid.UUID(userID), []byte(queue)))
+}
+
+func (core *Permission) CreateRole(partial model.RolePartial, userID model.ID) error {
+	err := validate(core.validator, partial)
+	if err != nil {
+		return err
+	}
+
+	exist, err := core.database.ExistRole(partial.Name)
+	if err != nil {
+		return fmt.Errorf("error checking if role exist in database: %w", err)
+	}
+
+	if exist {
+		return ErrRoleAlreadyExist
+	}
+
+	role := model.Role{
+		ID:          model.NewID(),
+		Name:        partial.Name,
+		Permissions: partial.Permissions,
+		CreatedAt:   time.Now(),
+		CreatedBy:   userID,
+		DeletedAt:   time.Time{},
+		DeletedBy:   model.ID{},
+	}
+
+	err = core.database.CreateRole(role)
+	if err != nil {
+		return fmt.Errorf("error creating role in database: %w", err)
+	}
+
+	return nil
+}
+
+func (core *Permission) GetRole(name string) (*model.Role, error) {
+	exist, err := core.database.ExistRole(name)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if role exist in database: %w", err)
+	}
+
+	if !exist {
+		return nil, ErrRoleDoesNotExist
+	}
+
+	role, err := core.database.GetRole(name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting role from database: %w", err)
+	}
+
+	return role, nil
+}
+
+func (core *Permission) GetRoles() ([]model.Role, error) {
+	roles, err := core.database.GetRoles()
+	if err != nil {
+		return nil, fmt.Errorf("error getting roles from database: %w", err)
+	}
+
+	return roles, nil
+}
+
+// DeleteRole deletes the role, the grants with the role do not have
+// permissions anymore.
+func (core *Permission) DeleteRole(name string, userID model.ID) error {
+	role, err := core.GetRole(name)
+	if err != nil {
+		return err
+	}
+
+	role.DeletedAt = time.Now()
+	role.DeletedBy = userID
+
+	err = core.database.UpdateRole(*role)
+	if err != nil {
+		return fmt.Errorf("error deleting role: %w", err)
+	}
+
+	return nil
+}
+
+func (core *Permission) Grant(
+	queue string,
+	userID model.ID,
+	partial model.QueueGrantPartial,
+	adminID model.ID,
+) error {
+	err := validate(core.validator, partial)
+	if err != nil {
+		return err
+	}
+
+	exist, err := core.queues.Exist(queue)
+	if err != nil {
+		return fmt.Errorf("error checking if queue exist in database: %w", err)
+	}
+
+	if !exist {
+		return ErrQueueDoesNotExist
+	}
+
+	exist, err = core.user.existByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if !exist {
+		return ErrUserDoesNotExist
+	}
+
+	_, err = core.GetRole(partial.Role)
+	if err != nil {
+		return err
+	}
+
+	grant := model.QueueGrant{
+		ID:        grantID(queue, userID),
+		UserID:    userID,
+		Queue:     queue,
+		Role:      partial.Role,
+		CreatedAt: time.Now(),
+		CreatedBy: adminID,
+		DeletedAt: time.Time{},
+		DeletedBy: model.ID{},
+	}
+
+	err = core.database.SaveGrant(grant)
+	if err != nil {
+		return fmt.Errorf("error saving grant in database: %w", err)
+	}
+
+	return nil
+}
+
+func (core *Permission) GetGrants(queue string) ([]model.QueueGrant, error) {
+	grants, err := core.database.GetGrants(queue)
+	if err != nil {
+		return nil, fmt.Errorf("error getting grants from database: %w", err)
+	}
+
+	return grants, nil
+}
+
+func (core *Permission) Revoke(queue string, userID model.ID, adminID model.ID) error {
+	exist, err := core.database.ExistGrant(queue, userID)
+	if err != nil {
+		return fmt.Errorf("error checking if grant exist in database: %w", err)
+	}
+
+	if !exist {
+		return ErrGrantDoesNotExist
+	}
+
+	grant, err := core.database.GetGrant(queue, userID)
+	if err != nil {
+		return fmt.Errorf("error getting grant from database: %w", err)
+	}
+
+	grant.DeletedAt = time.Now()
+	grant.DeletedBy = adminID
+
+	err = core.database.UpdateGrant(*grant)
+	if err != nil {
+		return fmt.Errorf("error revoking grant: %w", err)
+	}
+
+	return nil
+}
+
+// revokeAll revokes the grants of all users on the queue.
+func (core *Permission) revokeAll(queue string, adminID model.ID) error {
+	err := core.database.DeleteGrants(queue, time.Now(), adminID)
+	if err != nil {
+		return fmt.Errorf("error revoking grants: %w", err)
+	}
+
+	return nil
+}
+
+// allowed returns ErrPermissionDenied when the user does not have the
+// permission on the queue.
+func (core *Permission) allowed(queue *model.Queue, userID model.ID, permission string) error {
+	isAdmin, err := core.user.IsAdmin(userID)
+	if err != nil && !errors.Is(err, ErrAdminTOTPRequired) {
+		return err
+	}
+
+	if isAdmin {
+		return nil
+	}
+
+	if queue.OrganizationID != (model.ID{}) {
+		member, err := core.organization.IsMember(queue.OrganizationID, userID)
+		if err != nil {
+			return err
+		}
+
+		if !member {
+			return ErrPermissionDenied
+		}
+	}
+
+	exist, err := core.database.ExistGrant(queue.Name, userID)
+	if err != nil {
+		return fmt.Errorf("error checking if grant exist in database: %w", err)
+	}
+
+	if !exist {
+		return ErrPermissionDenied
+	}
+
+	grant, err := core.database.GetGrant(queue.Name, userID)
+	if err != nil {
+		return fmt.Errorf("error getting grant from database: %w", err)
+	}
+
+	exist, err = core.database.ExistRole(grant.Role)
+	if err != nil {
+		return fmt.Errorf("error checking if role exist in database: %w", err)
+	}
+
+	if !exist {
+		return ErrPermissionDenied
+	}
+
+	role, err := core.database.GetRole(grant.Role)
+	if err != nil {
+		return fmt.Errorf("error getting role from database: %w", err)
+	}
+
+	if !slices.Contains(role.Permissions, permission) {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+func newPermission(
+	database *data.Permission,
+	queues *data.Queue,
+	user *User,
+	organization *Organization,
+	validate *validator.Validate,
+) *Permission {
+	return &Permission{
+		database:     database,
+		queues:       queues,
+		user:         user,
+		organization: organization,
+		validator:    validate,
+	}
+}
@@ -2,22 +2,40 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/thiago-felipe-99/mail/publisher/data"
 	"github.com/thiago-felipe-99/mail/publisher/model"
 	"github.com/thiago-felipe-99/mail/rabbit"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// outboxLease is the time that a publisher has to publish the claimed email
+	// before it is relayed by another one.
+	outboxLease = time.Minute
+	// outboxMaxDelay is the longest delay between the passes of the outbox
+	// while the emails can not be published.
+	outboxMaxDelay = 5 * time.Minute
+	dlxSuffix      = "-dlx"
 )
 
 type Queue struct {
-	template   *Template
-	attachment *Attachment
-	emailList  *EmailList
-	rabbit     *rabbit.Rabbit
-	database   *data.Queue
-	validator  *validator.Validate
+	template     *Template
+	attachment   *Attachment
+	emailList    *EmailList
+	suppression  *Suppression
+	permission   *Permission
+	organization *Organization
+	rabbit       *rabbit.Rabbit
+	database     *data.Queue
+	validator    *validator.Validate
 }
 
 func (core *Queue) Exist(name string) (bool, error) {
@@ -35,15 +53,39 @@ func (core *Queue) Create(partial model.QueuePartial, userID model.ID) error {
 		return err
 	}
 
+	if strings.HasSuffix(partial.Name, rabbit.HighPriorityQueue("")) || strings.HasSuffix(partial.Name, dlxSuffix) {
+		return ErrQueueReservedName
+	}
+
+	organizationID := model.ID{}
+
+	if partial.Organization != "" {
+		organizationID, err = model.ParseID(partial.Organization)
+		if err != nil {
+			return fmt.Errorf("error parsing organization ID: %w", err)
+		}
+
+		exist, err := core.organization.exist(organizationID)
+		if err != nil {
+			return err
+		}
+
+		if !exist {
+			return ErrOrganizationDoesNotExist
+		}
+	}
+
 	queue := model.Queue{
-		ID:         model.NewID(),
-		Name:       partial.Name,
-		DLX:        partial.Name + "-dlx",
-		MaxRetries: partial.MaxRetries,
-		CreatedAt:  time.Now(),
-		CreatedBy:  userID,
-		DeletedAt:  time.Time{},
-		DeletedBy:  model.ID{},
+		ID:             model.NewID(),
+		Name:           partial.Name,
+		DLX:            partial.Name + dlxSuffix,
+		MaxRetries:     partial.MaxRetries,
+		Priority:       partial.Priority,
+		OrganizationID: organizationID,
+		CreatedAt:      time.Now(),
+		CreatedBy:      userID,
+		DeletedAt:      time.Time{},
+		DeletedBy:      model.ID{},
 	}
 
 	queueExist, err := core.Exist(queue.Name)
@@ -51,7 +93,7 @@ func (core *Queue) Create(partial model.QueuePartial, userID model.ID) error {
 		return fmt.Errorf("error checking if queue exist in database: %w", err)
 	}
 
-	dlxExist, err := core.Exist(queue.Name)
+	dlxExist, err := core.Exist(queue.DLX)
 	if err != nil {
 		return fmt.Errorf("error checking if dlx queue exist in database: %w", err)
 	}
@@ -60,7 +102,7 @@ func (core *Queue) Create(partial model.QueuePartial, userID model.ID) error {
 		return ErrQueueAlreadyExist
 	}
 
-	err = core.rabbit.CreateQueueWithDLX(queue.Name, queue.DLX, queue.MaxRetries)
+	err = core.rabbit.CreateQueueWithDLX(queue.Name, queue.DLX, queue.MaxRetries, queue.Priority)
 	if err != nil {
 		return fmt.Errorf("error creating queue: %w", err)
 	}
@@ -110,7 +152,14 @@ func (core *Queue) Delete(name string, userID model.ID) error {
 		return err
 	}
 
-	err = core.rabbit.DeleteQueueWithDLX(queue.Name, queue.DLX)
+	// The grants are keyed by the queue name, they are revoked first so a queue
+	// created later with the same name does not inherit them.
+	err = core.permission.revokeAll(queue.Name, userID)
+	if err != nil {
+		return err
+	}
+
+	err = core.rabbit.DeleteQueueWithDLX(queue.Name, queue.DLX, queue.Priority)
 	if err != nil {
 		return fmt.Errorf("error deleting queue from RabbitMQ: %w", err)
 	}
@@ -126,51 +175,267 @@ func (core *Queue) Delete(name string, userID model.ID) error {
 	return nil
 }
 
-func (core *Queue) SendEmail(queue string, partial model.EmailPartial, userID model.ID) error {
-	if len(queue) == 0 {
-		return ErrInvalidName
+// GetEmails returns the emails sent to the queue, the newest first.
+func (core *Queue) GetEmails(name string, userID model.ID) ([]model.Email, error) {
+	queue, err := core.Get(name)
+	if err != nil {
+		return nil, err
 	}
 
-	err := validate(core.validator, partial)
+	err = core.permission.allowed(queue, userID, model.PermissionHistory)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	queues := []string{queue.Name}
+	if queue.Priority {
+		queues = append(queues, rabbit.HighPriorityQueue(queue.Name))
+	}
+
+	emails, err := core.database.GetEmailsByQueue(queues)
+	if err != nil {
+		return nil, fmt.Errorf("error getting emails from database: %w", err)
 	}
 
-	queueExist, err := core.Exist(queue)
+	slices.SortFunc(emails, func(a, b model.Email) bool { return a.SentAt.After(b.SentAt) })
+
+	return emails, nil
+}
+
+func (core *Queue) dlx(name string, userID model.ID) (*model.Queue, error) {
+	queue, err := core.Get(name)
 	if err != nil {
-		return fmt.Errorf("error checking if queue exist: %w", err)
+		return nil, err
 	}
 
-	if !queueExist {
-		return ErrQueueDoesNotExist
+	err = core.permission.allowed(queue, userID, model.PermissionDLX)
+	if err != nil {
+		return nil, err
 	}
 
-	if partial.Template != nil {
-		exist, err := core.template.Exist(partial.Template.Name)
+	return queue, nil
+}
+
+// GetDLX returns the number of emails that were not sent after the max
+// retries.
+func (core *Queue) GetDLX(name string, userID model.ID) (*model.DLX, error) {
+	queue, err := core.dlx(name, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := core.rabbit.CountMessages(queue.DLX)
+	if err != nil {
+		return nil, fmt.Errorf("error counting dlx messages: %w", err)
+	}
+
+	return &model.DLX{Queue: queue.Name, DLX: queue.DLX, Messages: messages}, nil
+}
+
+// RequeueDLX sends the emails of the DLX to the queue again, it returns the
+// number of emails sent.
+func (core *Queue) RequeueDLX(name string, userID model.ID) (*model.DLX, error) {
+	queue, err := core.dlx(name, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := core.rabbit.MoveMessages(context.Background(), queue.DLX, queue.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error requeuing dlx messages: %w", err)
+	}
+
+	return &model.DLX{Queue: queue.Name, DLX: queue.DLX, Messages: moved}, nil
+}
+
+// PurgeDLX removes the emails of the DLX, it returns the number of emails
+// removed.
+func (core *Queue) PurgeDLX(name string, userID model.ID) (*model.DLX, error) {
+	queue, err := core.dlx(name, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	purged, err := core.rabbit.PurgeMessages(queue.DLX)
+	if err != nil {
+		return nil, fmt.Errorf("error purging dlx messages: %w", err)
+	}
+
+	return &model.DLX{Queue: queue.Name, DLX: queue.DLX, Messages: purged}, nil
+}
+
+// emailID is random, unless the email has an idempotency key, then the same
+// user and key always have the same ID.
+func emailID(userID model.ID, idempotencyKey string) model.ID {
+	if idempotencyKey == "" {
+		return model.NewID()
+	}
+
+	return model.ID(uuid.NewSHA1(uuid.UUID(userID), []byte(idempotencyKey)))
+}
+
+// routing is the queue that receives the email, high priority emails go to
+// the high priority queue.
+func routing(queue *model.Queue, priority string) (string, error) {
+	if priority != "high" {
+		return queue.Name, nil
+	}
+
+	if !queue.Priority {
+		return "", ErrQueueWithoutPriority
+	}
+
+	return rabbit.HighPriorityQueue(queue.Name), nil
+}
+
+func (core *Queue) templateFields(name string, ownerID model.ID) ([]string, error) {
+	exist, err := core.template.Exist(name)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if template exist: %w", err)
+	}
+
+	if !exist {
+		return nil, ErrTemplateDoesNotExist
+	}
+
+	fields, err := core.template.GetFields(name, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting templates fields: %w", err)
+	}
+
+	return fields, nil
+}
+
+func missingFields(fields []string, data map[string]string) []string {
+	missing := []string{}
+
+	for _, field := range fields {
+		if _, found := data[field]; !found {
+			missing = append(missing, field)
+		}
+	}
+
+	return missing
+}
+
+func (core *Queue) checkAttachments(ownerID model.ID, attachments []string) error {
+	for _, attachment := range attachments {
+		uploaded, err := core.attachment.Uploaded(ownerID, attachment)
 		if err != nil {
-			return fmt.Errorf("error checking if template exist: %w", err)
+			return fmt.Errorf("error checking if attachment exist: %w", err)
 		}
 
-		if !exist {
-			return ErrTemplateDoesNotExist
+		if !uploaded {
+			return ErrAttachmentDoesNotExist
+		}
+	}
+
+	return nil
+}
+
+func receiversEmails(receivers ...[]model.Receiver) []string {
+	emails := []string{}
+
+	for _, receiver := range receivers {
+		for _, receiver := range receiver {
+			emails = append(emails, receiver.Email)
+		}
+	}
+
+	return emails
+}
+
+// withoutSuppressed returns the receivers that are not suppressed and the
+// suppressed emails.
+func withoutSuppressed(
+	receivers []model.Receiver,
+	suppressed map[string]struct{},
+) ([]model.Receiver, []string) {
+	allowed := make([]model.Receiver, 0, len(receivers))
+	removed := []string{}
+
+	for _, receiver := range receivers {
+		if _, exist := suppressed[normalizeEmail(receiver.Email)]; exist {
+			removed = append(removed, receiver.Email)
+
+			continue
 		}
 
-		fields, err := core.template.GetFields(partial.Template.Name)
+		allowed = append(allowed, receiver)
+	}
+
+	return allowed, removed
+}
+
+// SendEmail sends the email when the user has the send permission on the
+// queue, the template, email lists and attachments are of the owner.
+func (core *Queue) SendEmail(
+	name string,
+	partial model.EmailPartial,
+	userID model.ID,
+	ownerID model.ID,
+	idempotencyKey string,
+) (*model.EmailSent, error) {
+	queue, err := core.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = core.permission.allowed(queue, userID, model.PermissionSend)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.sendEmail(name, partial, userID, ownerID, idempotencyKey)
+}
+
+// sendEmail saves the email and publishes it, the suppressed receivers are
+// removed and reported.
+func (core *Queue) sendEmail(
+	name string,
+	partial model.EmailPartial,
+	userID model.ID,
+	ownerID model.ID,
+	idempotencyKey string,
+) (*model.EmailSent, error) {
+	if len(name) == 0 {
+		return nil, ErrInvalidName
+	}
+
+	id := emailID(userID, idempotencyKey)
+
+	err := validate(core.validator, partial)
+	if err != nil {
+		return nil, err
+	}
+
+	queue, err := core.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	routing, err := routing(queue, partial.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	if partial.Template != nil {
+		fields, err := core.templateFields(partial.Template.Name, ownerID)
 		if err != nil {
-			return fmt.Errorf("error getting templates fields: %w", err)
+			return nil, err
 		}
 
-		for _, field := range fields {
-			if _, found := partial.Template.Data[field]; !found {
-				return ErrMissingFieldTemplates
-			}
+		if len(missingFields(fields, partial.Template.Data)) > 0 {
+			return nil, ErrMissingFieldTemplates
 		}
 	}
 
+	// The contacts of the lists are blind receivers of the same message, so it
+	// has no unsubscribe link, the bulk send has one for each contact.
 	for _, list := range partial.EmailLists {
-		emailList, err := core.emailList.Get(list, userID)
+		emailList, err := core.emailList.Get(list, ownerID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		partial.Receivers = append(partial.Receivers, model.Receiver{
@@ -178,68 +443,283 @@ func (core *Queue) SendEmail(queue string, partial model.EmailPartial, userID mo
 			Email: emailList.EmailAlias,
 		})
 
-		blindReceiver := make([]model.Receiver, 0, len(emailList.Emails))
-		for _, email := range emailList.Emails {
-			blindReceiver = append(blindReceiver, model.Receiver{
+		err = core.emailList.database.EachContact(emailList.ID, func(contact model.ListContact) error {
+			partial.BlindReceivers = append(partial.BlindReceivers, model.Receiver{
 				Name:  emailList.Name,
-				Email: email,
+				Email: contact.Email,
 			})
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error getting email list contacts: %w", err)
 		}
+	}
 
-		partial.BlindReceivers = append(partial.BlindReceivers, blindReceiver...)
+	suppressed, err := core.suppression.suppressed(
+		receiversEmails(partial.Receivers, partial.BlindReceivers),
+		userID,
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, attachment := range partial.Attachments {
-		uploaded, err := core.attachment.Uploaded(userID, attachment)
-		if err != nil {
-			return fmt.Errorf("error checking if attachment exist: %w", err)
-		}
+	receivers, suppressedReceivers := withoutSuppressed(partial.Receivers, suppressed)
+	blindReceivers, suppressedBlindReceivers := withoutSuppressed(partial.BlindReceivers, suppressed)
 
-		if !uploaded {
-			return ErrAttachmentDoesNotExist
-		}
+	if len(receivers)+len(blindReceivers) == 0 {
+		return nil, ErrAllReceiversSuppressed
 	}
 
-	err = core.rabbit.SendMessage(context.Background(), queue, partial)
+	err = core.checkAttachments(ownerID, partial.Attachments)
 	if err != nil {
-		return fmt.Errorf("error sending email: %w", err)
+		return nil, err
 	}
 
+	// The email is saved claimed by this publisher, the outbox relays it only
+	// when the publish fails.
+	now := time.Now()
+
 	email := model.Email{
-		ID:             model.NewID(),
+		ID:             id,
 		UserID:         userID,
 		EmailLists:     partial.EmailLists,
-		Receivers:      partial.Receivers,
-		BlindReceivers: partial.BlindReceivers,
+		Receivers:      receivers,
+		BlindReceivers: blindReceivers,
 		Subject:        partial.Subject,
 		Message:        partial.Message,
 		Template:       partial.Template,
 		Attachments:    partial.Attachments,
-		SentAt:         time.Now(),
+		Priority:       partial.Priority,
+		IdempotencyKey: idempotencyKey,
+		Queue:          routing,
+		Status:         model.EmailRelaying,
+		SentAt:         now,
+		PublishedAt:    time.Time{},
+		LeaseExpiresAt: now.Add(outboxLease),
+		Suppressed:     append(suppressedReceivers, suppressedBlindReceivers...),
 	}
 
+	// The ID of the idempotency key is unique, a retry sent at the same time
+	// returns the saved email.
 	err = core.database.SaveEmail(email)
+	if errors.Is(err, data.ErrDuplicatedKey) {
+		return core.emailSent(id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error saving email in database: %w", err)
+	}
+
+	err = core.publish(email)
+	if err != nil {
+		log.Printf("[ERROR] - Error publishing email, it will be published by the outbox: %s", err)
+
+		return &model.EmailSent{
+			ID:         email.ID,
+			Status:     core.release(email.ID),
+			Suppressed: email.Suppressed,
+		}, nil
+	}
+
+	return &model.EmailSent{
+		ID:         email.ID,
+		Status:     model.EmailPublished,
+		Suppressed: email.Suppressed,
+	}, nil
+}
+
+// sendUnsaved publishes the email without saving it, the emails with secrets
+// can not be read on the queue history. It is not relayed by the outbox, an
+// error publishing the email is returned. The template is not of an owner.
+func (core *Queue) sendUnsaved(name string, partial model.EmailPartial, userID model.ID) error {
+	if len(name) == 0 {
+		return ErrInvalidName
+	}
+
+	err := validate(core.validator, partial)
+	if err != nil {
+		return err
+	}
+
+	queue, err := core.Get(name)
+	if err != nil {
+		return err
+	}
+
+	routing, err := routing(queue, partial.Priority)
+	if err != nil {
+		return err
+	}
+
+	if partial.Template != nil {
+		fields, err := core.templateFields(partial.Template.Name, model.ID{})
+		if err != nil {
+			return err
+		}
+
+		if len(missingFields(fields, partial.Template.Data)) > 0 {
+			return ErrMissingFieldTemplates
+		}
+	}
+
+	suppressed, err := core.suppression.suppressed(receiversEmails(partial.Receivers), userID)
+	if err != nil {
+		return err
+	}
+
+	partial.Receivers, _ = withoutSuppressed(partial.Receivers, suppressed)
+	if len(partial.Receivers) == 0 {
+		return ErrAllReceiversSuppressed
+	}
+
+	err = core.rabbit.SendMessage(context.Background(), routing, model.NewID().String(), partial)
 	if err != nil {
-		return fmt.Errorf("error saving email in database: %w", err)
+		return fmt.Errorf("error sending email: %w", err)
 	}
 
 	return nil
 }
 
+func (core *Queue) emailSent(emailID model.ID) (*model.EmailSent, error) {
+	email, err := core.database.GetEmail(emailID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting email from database: %w", err)
+	}
+
+	return &model.EmailSent{ID: email.ID, Status: email.Status, Suppressed: email.Suppressed}, nil
+}
+
+// publish sends the email saved on the outbox to RabbitMQ and marks it as
+// published.
+func (core *Queue) publish(email model.Email) error {
+	partial := model.EmailPartial{
+		EmailLists:     email.EmailLists,
+		Receivers:      email.Receivers,
+		BlindReceivers: email.BlindReceivers,
+		Subject:        email.Subject,
+		Message:        email.Message,
+		Template:       email.Template,
+		Attachments:    email.Attachments,
+		Priority:       email.Priority,
+		Unsubscribe:    email.Unsubscribe,
+	}
+
+	err := core.rabbit.SendMessage(context.Background(), email.Queue, email.ID.String(), partial)
+	if err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+
+	err = core.database.PublishedEmail(email.ID, time.Now())
+	if err != nil {
+		return fmt.Errorf("error marking email as published: %w", err)
+	}
+
+	return nil
+}
+
+// release moves the email that could not be published back to pending and
+// returns its status, it stays relaying until the lease expires when it can
+// not be released.
+func (core *Queue) release(emailID model.ID) string {
+	err := core.database.ReleaseEmail(emailID)
+	if err != nil {
+		log.Printf("[ERROR] - Error releasing email '%s': %s", emailID, err)
+
+		return model.EmailRelaying
+	}
+
+	return model.EmailPending
+}
+
+// RelayOutbox publishes the pending emails until the context is done, each
+// email is claimed with a lease so only one publisher relays it. The emails of
+// a publisher that stopped are relayed after the lease expires. A pass stops at
+// the first publish error and the delay until the next one is doubled.
+func (core *Queue) RelayOutbox(ctx context.Context, interval time.Duration) {
+	delay := interval
+
+	maxDelay := outboxMaxDelay
+	if interval > maxDelay {
+		maxDelay = interval
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		published := 0
+		failed := false
+
+		for ctx.Err() == nil {
+			email, err := core.database.ClaimEmail(time.Now().Add(outboxLease))
+			if err != nil {
+				log.Printf("[ERROR] - Error getting pending emails: %s", err)
+
+				break
+			}
+
+			if email == nil {
+				break
+			}
+
+			err = core.publish(*email)
+			if err != nil {
+				log.Printf("[ERROR] - Error relaying email '%s': %s", email.ID, err)
+
+				core.release(email.ID)
+
+				failed = true
+
+				break
+			}
+
+			published++
+		}
+
+		if published > 0 {
+			log.Printf("[INFO] - %d pending emails were published", published)
+		}
+
+		if failed {
+			delay *= 2
+			if delay > maxDelay {
+				delay = maxDelay
+			}
+		} else {
+			delay = interval
+		}
+
+		timer.Reset(delay)
+	}
+}
+
 func newQueue(
 	template *Template,
 	attachment *Attachment,
 	emailList *EmailList,
+	suppression *Suppression,
+	permission *Permission,
+	organization *Organization,
 	rabbit *rabbit.Rabbit,
 	database *data.Queue,
 	validate *validator.Validate,
 ) *Queue {
 	return &Queue{
-		template:   template,
-		attachment: attachment,
-		emailList:  emailList,
-		rabbit:     rabbit,
-		database:   database,
-		validator:  validate,
+		template:     template,
+		attachment:   attachment,
+		emailList:    emailList,
+		suppression:  suppression,
+		permission:   permission,
+		organization: organization,
+		rabbit:       rabbit,
+		database:     database,
+		validator:    validate,
 	}
 }
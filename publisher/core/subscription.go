@@ -0,0 +1,270 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/thiago-felipe-99/mail/publisher/data"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+const (
+	subscribeConfirmRoute = "/subscribe/confirm/"
+	subscribeLimitWindow  = time.Hour
+)
+
+// Subscription is the double opt-in of the email lists, a subscription is
+// pending until the link sent to the email is confirmed.
+type Subscription struct {
+	emailList *EmailList
+	queue     *Queue
+	database  *data.EmailList
+	validator *validator.Validate
+	config    SubscriptionConfig
+}
+
+// SubscriptionConfig configures the confirmation emails of the subscriptions
+// and the limits of the subscriptions waiting the confirmation.
+type SubscriptionConfig struct {
+	Queue       string
+	Template    string
+	Subject     string
+	Expiration  time.Duration
+	MaxPerIP    int
+	MaxPerEmail int
+	MaxPending  int
+}
+
+// limited checks the subscriptions requested by the IP and to the email in
+// the last subscribeLimitWindow and the subscriptions of the list waiting the
+// confirmation.
+func (core *Subscription) limited(listID model.ID, key string, requestIP string) (bool, error) {
+	now := time.Now()
+
+	requests, err := core.database.CountPendingByIP(requestIP, now.Add(-subscribeLimitWindow))
+	if err != nil || requests >= core.config.MaxPerIP {
+		return true, err
+	}
+
+	requests, err = core.database.CountPendingByKey(key, now.Add(-subscribeLimitWindow))
+	if err != nil || requests >= core.config.MaxPerEmail {
+		return true, err
+	}
+
+	pending, err := core.database.CountPendingByList(listID, now.Add(-core.config.Expiration))
+	if err != nil || pending >= core.config.MaxPending {
+		return true, err
+	}
+
+	return false, nil
+}
+
+// Subscribe saves the contact as pending and sends the confirmation email, an
+// email already on the list is ignored.
+func (core *Subscription) Subscribe(
+	name string,
+	userID model.ID,
+	partial model.ContactPartial,
+	requestIP string,
+) error {
+	if core.config.Queue == "" || core.config.Template == "" {
+		return ErrSubscriptionDisabled
+	}
+
+	err := validate(core.validator, partial)
+	if err != nil {
+		return err
+	}
+
+	emailList, err := core.emailList.Get(name, userID)
+	if err != nil {
+		return err
+	}
+
+	key := normalizeEmail(partial.Email)
+
+	subscribed, err := core.database.ExistContactByKey(emailList.ID, key)
+	if err != nil {
+		return fmt.Errorf("error checking if email is on the list: %w", err)
+	}
+
+	if subscribed {
+		return nil
+	}
+
+	limited, err := core.limited(emailList.ID, key, requestIP)
+	if err != nil {
+		return fmt.Errorf("error checking subscriptions limit: %w", err)
+	}
+
+	if limited {
+		return ErrSubscriptionLimit
+	}
+
+	now := time.Now()
+
+	// The pending is kept while it counts on the limits even if the link has
+	// expired.
+	keep := core.config.Expiration
+	if keep < subscribeLimitWindow {
+		keep = subscribeLimitWindow
+	}
+
+	pending := model.PendingContact{
+		ID:          model.NewID(),
+		ListID:      emailList.ID,
+		Email:       partial.Email,
+		Key:         key,
+		Name:        partial.Name,
+		Attributes:  partial.Attributes,
+		RequestIP:   requestIP,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(keep),
+	}
+
+	err = core.database.CreatePending(pending)
+	if err != nil {
+		return fmt.Errorf("error saving pending subscription: %w", err)
+	}
+
+	receiverName := partial.Name
+	if receiverName == "" {
+		receiverName = partial.Email
+	}
+
+	token := core.emailList.links.token(linkSubscribe, emailList.ID, pending.ID, now.Add(core.config.Expiration))
+
+	confirmation := model.EmailPartial{
+		Receivers: []model.Receiver{{Name: receiverName, Email: partial.Email}},
+		Subject:   core.config.Subject,
+		Template: &model.TemplateData{
+			Name: core.config.Template,
+			Data: map[string]string{
+				csvNameColumn:  receiverName,
+				csvEmailColumn: partial.Email,
+				"list":         emailList.Name,
+				"confirm":      core.emailList.links.link(subscribeConfirmRoute, token),
+			},
+		},
+	}
+
+	// The confirmation is sent by the publisher, the owner of the list does not
+	// need the send permission on the queue and the template is not of the
+	// owner.
+	_, err = core.queue.sendEmail(core.config.Queue, confirmation, emailList.CreatedBy, model.ID{}, "")
+	if err != nil {
+		return fmt.Errorf("error sending confirmation email: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the subscription of the confirmation token.
+func (core *Subscription) Get(token string) (*model.Subscription, error) {
+	emailList, pendingID, err := core.emailList.linkList(linkSubscribe, token)
+	if err != nil {
+		return nil, err
+	}
+
+	exist, err := core.database.ExistPending(emailList.ID, pendingID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if subscription exist: %w", err)
+	}
+
+	if exist {
+		pending, err := core.database.GetPending(emailList.ID, pendingID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting subscription: %w", err)
+		}
+
+		return &model.Subscription{List: emailList.Name, Email: pending.Email, Confirmed: false}, nil
+	}
+
+	confirmed, err := core.database.ExistContact(emailList.ID, pendingID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if email is on the list: %w", err)
+	}
+
+	if confirmed {
+		contact, err := core.database.GetContact(emailList.ID, pendingID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting email from the list: %w", err)
+		}
+
+		return &model.Subscription{List: emailList.Name, Email: contact.Email, Confirmed: true}, nil
+	}
+
+	return nil, ErrSubscriptionDoesNotExist
+}
+
+// Confirm adds the pending contact to the list with the consent, a confirmed
+// subscription is not an error.
+func (core *Subscription) Confirm(token string, confirmIP string) error {
+	emailList, pendingID, err := core.emailList.linkList(linkSubscribe, token)
+	if err != nil {
+		return err
+	}
+
+	exist, err := core.database.ExistPending(emailList.ID, pendingID)
+	if err != nil {
+		return fmt.Errorf("error checking if subscription exist: %w", err)
+	}
+
+	if !exist {
+		confirmed, err := core.database.ExistContact(emailList.ID, pendingID)
+		if err != nil {
+			return fmt.Errorf("error checking if email is on the list: %w", err)
+		}
+
+		if confirmed {
+			return nil
+		}
+
+		return ErrSubscriptionDoesNotExist
+	}
+
+	pending, err := core.database.GetPending(emailList.ID, pendingID)
+	if err != nil {
+		return fmt.Errorf("error getting subscription: %w", err)
+	}
+
+	contact := newListContact(emailList.ID, pending.Email)
+	contact.ID = pendingID
+	contact.Name = pending.Name
+	contact.Attributes = pending.Attributes
+	contact.Consent = &model.Consent{
+		RequestIP:   pending.RequestIP,
+		RequestedAt: pending.RequestedAt,
+		ConfirmIP:   confirmIP,
+		ConfirmedAt: time.Now(),
+	}
+
+	err = core.database.SaveContacts([]model.ListContact{contact})
+	if err != nil {
+		return fmt.Errorf("error confirming subscription: %w", err)
+	}
+
+	err = core.database.RemovePending(emailList.ID, pendingID)
+	if err != nil {
+		return fmt.Errorf("error confirming subscription: %w", err)
+	}
+
+	return nil
+}
+
+func newSubscription(
+	emailList *EmailList,
+	queue *Queue,
+	database *data.EmailList,
+	validate *validator.Validate,
+	config SubscriptionConfig,
+) *Subscription {
+	return &Subscription{
+		emailList: emailList,
+		queue:     queue,
+		database:  database,
+		validator: validate,
+		config:    config,
+	}
+}
@@ -0,0 +1,271 @@
+package core
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/thiago-felipe-99/mail/publisher/data"
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+const csvReasonColumn = "reason"
+
+type Suppression struct {
+	database  *data.Suppression
+	validator *validator.Validate
+}
+
+// suppressionID is the same for the email of an user, saving it again replaces
+// the suppression.
+func suppressionID(email string, userID model.ID) model.ID {
+	return model.ID(uuid.NewSHA1(uuid.UUID(userID), []byte(email)))
+}
+
+func (core *Suppression) newSuppression(
+	partial model.SuppressionPartial,
+	userID model.ID,
+	createdBy model.ID,
+) model.Suppression {
+	email := normalizeEmail(partial.Email)
+
+	return model.Suppression{
+		ID:        suppressionID(email, userID),
+		Email:     email,
+		UserID:    userID,
+		Global:    userID == model.ID{},
+		Reason:    partial.Reason,
+		CreatedAt: time.Now(),
+		CreatedBy: createdBy,
+		DeletedAt: time.Time{},
+		DeletedBy: model.ID{},
+	}
+}
+
+// Create suppresses the email for the user, an empty user ID is a global
+// suppression.
+func (core *Suppression) Create(
+	partial model.SuppressionPartial,
+	userID model.ID,
+	createdBy model.ID,
+) error {
+	err := validate(core.validator, partial)
+	if err != nil {
+		return err
+	}
+
+	err = core.database.Save([]model.Suppression{core.newSuppression(partial, userID, createdBy)})
+	if err != nil {
+		return fmt.Errorf("error saving suppression in database: %w", err)
+	}
+
+	return nil
+}
+
+// insert suppresses the email only when it was never suppressed for the user,
+// a suppression deleted by the user is kept deleted.
+func (core *Suppression) insert(partial model.SuppressionPartial, userID model.ID) error {
+	err := validate(core.validator, partial)
+	if err != nil {
+		return err
+	}
+
+	err = core.database.Insert(core.newSuppression(partial, userID, model.ID{}))
+	if err != nil && !errors.Is(err, data.ErrDuplicatedKey) {
+		return fmt.Errorf("error inserting suppression in database: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMultiples suppresses the valid emails, the invalid ones are reported.
+func (core *Suppression) CreateMultiples(
+	bulk model.SuppressionBulk,
+	userID model.ID,
+	createdBy model.ID,
+) (*model.SuppressionImport, error) {
+	err := validate(core.validator, bulk)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.SuppressionImport{Errors: []model.ImportError{}}
+	suppressions := make([]model.Suppression, 0, len(bulk.Suppressions))
+	added := map[model.ID]int{}
+
+	for index, partial := range bulk.Suppressions {
+		result.Rows++
+
+		err := core.validator.Struct(partial)
+		if err != nil {
+			result.Invalid++
+
+			if len(result.Errors) < maxImportErrors {
+				result.Errors = append(result.Errors, model.ImportError{
+					Row:   index + 1,
+					Email: partial.Email,
+					Error: err.Error(),
+				})
+			}
+
+			continue
+		}
+
+		suppression := core.newSuppression(partial, userID, createdBy)
+
+		if position, exist := added[suppression.ID]; exist {
+			suppressions[position] = suppression
+
+			continue
+		}
+
+		added[suppression.ID] = len(suppressions)
+		suppressions = append(suppressions, suppression)
+	}
+
+	result.Suppressed = len(suppressions)
+
+	if len(suppressions) == 0 {
+		return result, nil
+	}
+
+	err = core.database.Save(suppressions)
+	if err != nil {
+		return nil, fmt.Errorf("error saving suppressions in database: %w", err)
+	}
+
+	return result, nil
+}
+
+func (core *Suppression) GetAll(userID model.ID) ([]model.Suppression, error) {
+	suppressions, err := core.database.GetAll(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting suppressions from database: %w", err)
+	}
+
+	return suppressions, nil
+}
+
+func (core *Suppression) Delete(email string, userID model.ID, deletedBy model.ID) error {
+	email = normalizeEmail(email)
+
+	exist, err := core.database.Exist(email, userID)
+	if err != nil {
+		return fmt.Errorf("error checking if suppression exist in database: %w", err)
+	}
+
+	if !exist {
+		return ErrSuppressionDoesNotExist
+	}
+
+	suppression, err := core.database.Get(email, userID)
+	if err != nil {
+		return fmt.Errorf("error getting suppression from database: %w", err)
+	}
+
+	suppression.DeletedAt = time.Now()
+	suppression.DeletedBy = deletedBy
+
+	err = core.database.Update(*suppression)
+	if err != nil {
+		return fmt.Errorf("error deleting suppression: %w", err)
+	}
+
+	return nil
+}
+
+// suppressed returns the emails suppressed for the user or globally.
+func (core *Suppression) suppressed(emails []string, userID model.ID) (map[string]struct{}, error) {
+	suppressed := map[string]struct{}{}
+
+	if len(emails) == 0 {
+		return suppressed, nil
+	}
+
+	normalized := make([]string, 0, len(emails))
+	for _, email := range emails {
+		normalized = append(normalized, normalizeEmail(email))
+	}
+
+	suppressions, err := core.database.GetSuppressed(normalized, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting suppressions from database: %w", err)
+	}
+
+	for _, suppression := range suppressions {
+		suppressed[suppression.Email] = struct{}{}
+	}
+
+	return suppressed, nil
+}
+
+// ParseSuppressionCSV reads the suppressions from a CSV with a header, the
+// reason column is optional and defaultReason is used when it is empty.
+func ParseSuppressionCSV(reader io.Reader, defaultReason string) ([]model.SuppressionPartial, error) {
+	if defaultReason == "" {
+		defaultReason = model.SuppressionManual
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, errors.Join(ErrInvalidCSV, err)
+	}
+
+	emailColumn, reasonColumn := -1, -1
+
+	for index, column := range header {
+		switch strings.TrimSpace(column) {
+		case csvEmailColumn:
+			emailColumn = index
+		case csvReasonColumn:
+			reasonColumn = index
+		}
+	}
+
+	if emailColumn < 0 {
+		return nil, fmt.Errorf("%w: missing email column", ErrInvalidCSV)
+	}
+
+	suppressions := []model.SuppressionPartial{}
+
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Join(ErrInvalidCSV, err)
+		}
+
+		suppression := model.SuppressionPartial{Reason: defaultReason}
+
+		if emailColumn < len(record) {
+			suppression.Email = strings.TrimSpace(record[emailColumn])
+		}
+
+		if reasonColumn >= 0 && reasonColumn < len(record) && record[reasonColumn] != "" {
+			suppression.Reason = strings.TrimSpace(record[reasonColumn])
+		}
+
+		suppressions = append(suppressions, suppression)
+	}
+
+	return suppressions, nil
+}
+
+func newSuppression(database *data.Suppression, validate *validator.Validate) *Suppression {
+	return &Suppression{
+		database:  database,
+		validator: validate,
+	}
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+func TestParseSuppressionCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		csv           string
+		defaultReason string
+		want          []model.SuppressionPartial
+		err           error
+	}{
+		"email column": {
+			csv: "email\nuser@example.com\n other@example.com \n",
+			want: []model.SuppressionPartial{
+				{Email: "user@example.com", Reason: model.SuppressionManual},
+				{Email: "other@example.com", Reason: model.SuppressionManual},
+			},
+		},
+		"reason column": {
+			csv:           "name,reason,email\nuser,bounced,user@example.com\nother,,other@example.com\n",
+			defaultReason: model.SuppressionComplained,
+			want: []model.SuppressionPartial{
+				{Email: "user@example.com", Reason: model.SuppressionBounced},
+				{Email: "other@example.com", Reason: model.SuppressionComplained},
+			},
+		},
+		"short record": {
+			csv:  "reason,email\nbounced\n",
+			want: []model.SuppressionPartial{{Email: "", Reason: model.SuppressionBounced}},
+		},
+		"only header":          {csv: "email\n", want: []model.SuppressionPartial{}},
+		"missing email column": {csv: "name,reason\nuser,bounced\n", err: ErrInvalidCSV},
+		"empty":                {csv: "", err: ErrInvalidCSV},
+		"invalid quote":        {csv: "email\n\"user@example.com\n", err: ErrInvalidCSV},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSuppressionCSV(strings.NewReader(test.csv), test.defaultReason)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("ParseSuppressionCSV() error = %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseSuppressionCSV() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -56,7 +56,7 @@ func (core *Template) Exist(name string) (bool, error) {
 	return exist, nil
 }
 
-func (core *Template) Create(partial model.TemplatePartial, userID model.ID) error {
+func (core *Template) Create(partial model.TemplatePartial, ownerID model.ID, userID model.ID) error {
 	err := validate(core.validate, partial)
 	if err != nil {
 		return err
@@ -76,14 +76,15 @@ func (core *Template) Create(partial model.TemplatePartial, userID model.ID) err
 	}
 
 	template := model.Template{
-		ID:        model.NewID(),
-		Name:      partial.Name,
-		Template:  partial.Template,
-		Fields:    core.getFields(partial.Template),
-		CreatedAt: time.Now(),
-		CreatedBy: userID,
-		DeletedAt: time.Time{},
-		DeletedBy: model.ID{},
+		ID:             model.NewID(),
+		Name:           partial.Name,
+		Template:       partial.Template,
+		Fields:         core.getFields(partial.Template),
+		OrganizationID: organizationOf(ownerID, userID),
+		CreatedAt:      time.Now(),
+		CreatedBy:      userID,
+		DeletedAt:      time.Time{},
+		DeletedBy:      model.ID{},
 	}
 
 	templateReader := strings.NewReader(template.Template)
@@ -119,7 +120,9 @@ func (core *Template) GetAll() ([]model.Template, error) {
 	return templates, nil
 }
 
-func (core *Template) Get(name string) (*model.Template, error) {
+// Get returns the template of the owner, without an owner any template is
+// returned.
+func (core *Template) Get(name string, ownerID model.ID) (*model.Template, error) {
 	if len(name) == 0 {
 		return nil, ErrInvalidName
 	}
@@ -138,11 +141,15 @@ func (core *Template) Get(name string) (*model.Template, error) {
 		return nil, fmt.Errorf("error getting template from database: %w", err)
 	}
 
+	if ownerID != (model.ID{}) && !owned(template.OrganizationID, template.CreatedBy, ownerID) {
+		return nil, ErrTemplateDoesNotExist
+	}
+
 	return template, nil
 }
 
-func (core *Template) GetFields(name string) ([]string, error) {
-	template, err := core.Get(name)
+func (core *Template) GetFields(name string, ownerID model.ID) ([]string, error) {
+	template, err := core.Get(name, ownerID)
 	if err != nil {
 		return nil, err
 	}
@@ -150,8 +157,8 @@ func (core *Template) GetFields(name string) ([]string, error) {
 	return template.Fields, nil
 }
 
-func (core *Template) GetByUser(userID model.ID) ([]model.Template, error) {
-	templates, err := core.database.GetByUser(userID)
+func (core *Template) GetByOwner(ownerID model.ID) ([]model.Template, error) {
+	templates, err := core.database.GetByOwner(ownerID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting user templates from databas: %w", err)
 	}
@@ -159,7 +166,7 @@ func (core *Template) GetByUser(userID model.ID) ([]model.Template, error) {
 	return templates, nil
 }
 
-func (core *Template) Update(name string, partial model.TemplatePartial) error {
+func (core *Template) Update(name string, ownerID model.ID, partial model.TemplatePartial) error {
 	err := validate(core.validate, partial)
 	if err != nil {
 		return err
@@ -169,7 +176,7 @@ func (core *Template) Update(name string, partial model.TemplatePartial) error {
 		return ErrMaxSizeTemplate
 	}
 
-	template, err := core.Get(name)
+	template, err := core.Get(name, ownerID)
 	if err != nil {
 		return fmt.Errorf("error getting template: %w", err)
 	}
@@ -201,12 +208,12 @@ func (core *Template) Update(name string, partial model.TemplatePartial) error {
 	return nil
 }
 
-func (core *Template) Delete(name string, userID model.ID) error {
+func (core *Template) Delete(name string, ownerID model.ID, userID model.ID) error {
 	if len(name) == 0 {
 		return ErrInvalidName
 	}
 
-	template, err := core.Get(name)
+	template, err := core.Get(name, ownerID)
 	if err != nil {
 		return err
 	}
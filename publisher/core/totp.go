@@ -0,0 +1,323 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+// The TOTP follows the RFC 6238 with the defaults of the authenticator apps.
+const (
+	totpIssuer         = "Emails Publisher"
+	totpSecretSize     = 20
+	totpPeriod         = 30
+	totpDigits         = 6
+	totpSkew           = 1
+	recoveryCodesCount = 10
+	recoveryCodeSize   = 5
+	totpMaxFailures    = 5
+	totpLockout        = 15 * time.Minute
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// totpCode is the HOTP (RFC 4226) of the step.
+func totpCode(secret []byte, step int64) string {
+	counter := make([]byte, 8) //nolint:gomnd
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f                                    //nolint:gomnd
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff //nolint:gomnd
+
+	code := strconv.FormatUint(uint64(value), 10)
+	if len(code) < totpDigits {
+		code = strings.Repeat("0", totpDigits-len(code)) + code
+	}
+
+	return code[len(code)-totpDigits:]
+}
+
+// totpStep returns the step of the code, the codes of the previous and next
+// steps are accepted to allow clock drift. The step is zero when the code is
+// invalid.
+func totpStep(secret string, code string, now time.Time) int64 {
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		return 0
+	}
+
+	current := now.Unix() / totpPeriod
+
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step
+		}
+	}
+
+	return 0
+}
+
+func totpURI(secret string, account string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(totpDigits))
+	query.Set("period", strconv.Itoa(totpPeriod))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + totpIssuer + ":" + account,
+		RawQuery: strings.ReplaceAll(query.Encode(), "+", "%20"),
+	}
+
+	return uri.String()
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// newRecoveryCodes returns the codes and their hashes.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+
+	for len(codes) < recoveryCodesCount {
+		random := make([]byte, recoveryCodeSize)
+
+		_, err := rand.Read(random)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error generating recovery code: %w", err)
+		}
+
+		code := strings.ToLower(totpEncoding.EncodeToString(random))
+
+		codes = append(codes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, hashToken(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// checkSecondFactor accepts a TOTP code newer than the last one used or an
+// unused recovery code, the code is used at once so it is accepted by only one
+// request. The second factor is locked after totpMaxFailures wrong codes.
+func (core *User) checkSecondFactor(user *model.User, code string) error {
+	if code == "" {
+		return ErrUserTOTPCodeRequired
+	}
+
+	now := time.Now()
+
+	if user.TOTPLockedUntil.After(now) {
+		return ErrUserTOTPLocked
+	}
+
+	var (
+		used bool
+		err  error
+	)
+
+	step := totpStep(user.TOTPSecret, code, now)
+	if step != 0 {
+		used, err = core.database.UseTOTPStep(user.ID, step)
+	} else {
+		used, err = core.database.UseRecoveryCode(user.ID, hashToken(normalizeRecoveryCode(code)))
+	}
+
+	if err != nil {
+		return fmt.Errorf("error using second factor: %w", err)
+	}
+
+	if !used {
+		err = core.database.FailSecondFactor(user.ID, totpMaxFailures, now.Add(totpLockout))
+		if err != nil {
+			return fmt.Errorf("error counting wrong second factor: %w", err)
+		}
+
+		return ErrUserWrongTOTPCode
+	}
+
+	if step != 0 {
+		user.TOTPLastStep = step
+	}
+
+	return nil
+}
+
+// EnrollTOTP creates a new secret, the two-factor authentication is enabled
+// after the first code is verified.
+func (core *User) EnrollTOTP(userID model.ID) (*model.TOTPEnrollment, error) {
+	user, err := core.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrUserTOTPAlreadyEnabled
+	}
+
+	random := make([]byte, totpSecretSize)
+
+	_, err = rand.Read(random)
+	if err != nil {
+		return nil, fmt.Errorf("error generating TOTP secret: %w", err)
+	}
+
+	user.TOTPSecret = totpEncoding.EncodeToString(random)
+	user.TOTPLastStep = 0
+
+	err = core.database.UpdateTOTP(*user)
+	if err != nil {
+		return nil, fmt.Errorf("error updating user in database: %w", err)
+	}
+
+	return &model.TOTPEnrollment{
+		Secret: user.TOTPSecret,
+		URI:    totpURI(user.TOTPSecret, user.Email),
+	}, nil
+}
+
+// EnableTOTP verifies the first code of the enrolled secret and returns the
+// recovery codes.
+func (core *User) EnableTOTP(userID model.ID, partial model.TOTPCode) (*model.RecoveryCodes, error) {
+	err := validate(core.validator, partial)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := core.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPEnabled {
+		return nil, ErrUserTOTPAlreadyEnabled
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, ErrUserTOTPNotEnrolled
+	}
+
+	step := totpStep(user.TOTPSecret, partial.Code, time.Now())
+	if step == 0 {
+		return nil, ErrUserWrongTOTPCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPEnabled = true
+	user.TOTPLastStep = step
+	user.RecoveryCodes = hashes
+
+	err = core.database.UpdateTOTP(*user)
+	if err != nil {
+		return nil, fmt.Errorf("error updating user in database: %w", err)
+	}
+
+	return &model.RecoveryCodes{Codes: codes}, nil
+}
+
+func (core *User) DisableTOTP(userID model.ID, partial model.TOTPCode) error {
+	err := validate(core.validator, partial)
+	if err != nil {
+		return err
+	}
+
+	user, err := core.GetByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.TOTPEnabled {
+		return ErrUserTOTPNotEnabled
+	}
+
+	err = core.checkSecondFactor(user, partial.Code)
+	if err != nil {
+		return err
+	}
+
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	user.RecoveryCodes = nil
+
+	err = core.database.UpdateTOTP(*user)
+	if err != nil {
+		return fmt.Errorf("error updating user in database: %w", err)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes of the user.
+func (core *User) RegenerateRecoveryCodes(userID model.ID, partial model.TOTPCode) (*model.RecoveryCodes, error) {
+	err := validate(core.validator, partial)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := core.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.TOTPEnabled {
+		return nil, ErrUserTOTPNotEnabled
+	}
+
+	err = core.checkSecondFactor(user, partial.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = core.database.SaveRecoveryCodes(user.ID, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("error updating user in database: %w", err)
+	}
+
+	return &model.RecoveryCodes{Codes: codes}, nil
+}
+
+func (core *User) GetSettings() (*model.Settings, error) {
+	settings, err := core.database.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("error getting settings from database: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (core *User) UpdateSettings(settings model.Settings) error {
+	err := core.database.SaveSettings(settings)
+	if err != nil {
+		return fmt.Errorf("error saving settings in database: %w", err)
+	}
+
+	return nil
+}
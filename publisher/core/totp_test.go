@@ -0,0 +1,64 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 secret of the RFC 6238 test vectors.
+var rfcSecret = []byte("12345678901234567890") //nolint:gochecknoglobals
+
+func TestTOTPCode(t *testing.T) {
+	t.Parallel()
+
+	// The RFC 6238 SHA1 vectors with the last 6 digits.
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, want := range vectors {
+		if got := totpCode(rfcSecret, unix/totpPeriod); got != want {
+			t.Errorf("totpCode at %d = %s, want %s", unix, got, want)
+		}
+	}
+}
+
+func TestTOTPStep(t *testing.T) {
+	t.Parallel()
+
+	secret := totpEncoding.EncodeToString(rfcSecret)
+	step := int64(2000000000 / totpPeriod)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		now    time.Time
+		want   int64
+	}{
+		{"current step", secret, "279037", time.Unix(2000000000, 0), step},
+		{"previous step", secret, "279037", time.Unix(2000000000+totpPeriod, 0), step},
+		{"next step", secret, "279037", time.Unix(2000000000-totpPeriod, 0), step},
+		{"expired step", secret, "279037", time.Unix(2000000000+2*totpPeriod, 0), 0},
+		{"wrong code", secret, "123456", time.Unix(2000000000, 0), 0},
+		{"short code", secret, "79037", time.Unix(2000000000, 0), 0},
+		{"invalid secret", "1", "279037", time.Unix(2000000000, 0), 0},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := totpStep(test.secret, test.code, test.now); got != test.want {
+				t.Errorf("totpStep() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
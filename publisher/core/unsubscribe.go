@@ -0,0 +1,62 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/thiago-felipe-99/mail/publisher/model"
+)
+
+// templateUnsubscribeField is the template data with the unsubscribe link.
+const templateUnsubscribeField = "unsubscribe"
+
+const unsubscribeRoute = "/unsubscribe/"
+
+// UnsubscribeURL returns the signed link that removes the email from the list.
+func (core *EmailList) UnsubscribeURL(listID, emailID model.ID) string {
+	expires := time.Now().Add(core.unsubscribeExpiration)
+
+	return core.links.link(unsubscribeRoute, core.links.token(linkUnsubscribe, listID, emailID, expires))
+}
+
+// GetUnsubscribe returns the list and the email of the unsubscribe token.
+func (core *EmailList) GetUnsubscribe(token string) (*model.Unsubscribe, error) {
+	emailList, emailID, err := core.linkList(linkUnsubscribe, token)
+	if err != nil {
+		return nil, err
+	}
+
+	exist, err := core.database.ExistContact(emailList.ID, emailID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if email is on the list: %w", err)
+	}
+
+	unsubscribe := &model.Unsubscribe{List: emailList.Name, Email: "", Subscribed: exist}
+
+	if exist {
+		contact, err := core.database.GetContact(emailList.ID, emailID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting email from the list: %w", err)
+		}
+
+		unsubscribe.Email = contact.Email
+	}
+
+	return unsubscribe, nil
+}
+
+// Unsubscribe removes the email of the token from the list, an email already
+// removed is not an error.
+func (core *EmailList) Unsubscribe(token string) error {
+	emailList, emailID, err := core.linkList(linkUnsubscribe, token)
+	if err != nil {
+		return err
+	}
+
+	_, err = core.database.RemoveContact(emailList.ID, emailID)
+	if err != nil {
+		return fmt.Errorf("error unsubscribing email: %w", err)
+	}
+
+	return nil
+}
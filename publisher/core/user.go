@@ -9,6 +9,8 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/thiago-felipe-99/mail/publisher/data"
 	"github.com/thiago-felipe-99/mail/publisher/model"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type User struct {
@@ -132,7 +134,13 @@ func (core *User) Update(userID model.ID, partial model.UserPartial) error {
 		return fmt.Errorf("error checking if user exist in database: %w", err)
 	}
 
-	hash, err := argon2id.CreateHash(partial.Password, &core.argon2id)
+	return core.setPassword(user, partial.Password)
+}
+
+// setPassword saves the hash of the new password and revokes the sessions of
+// the user.
+func (core *User) setPassword(user *model.User, password string) error {
+	hash, err := argon2id.CreateHash(password, &core.argon2id)
 	if err != nil {
 		return fmt.Errorf("error creating password hash: %w", err)
 	}
@@ -144,6 +152,11 @@ func (core *User) Update(userID model.ID, partial model.UserPartial) error {
 		return fmt.Errorf("error updating user in database: %w", err)
 	}
 
+	err = core.RevokeSessions(user.ID)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -165,16 +178,36 @@ func (core *User) Delete(userID model.ID, deleteByID model.ID) error {
 		return fmt.Errorf("error deleting user from database: %w", err)
 	}
 
+	err = core.RevokeSessions(userID)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// IsAdmin is false with ErrAdminTOTPRequired when the admins must have the
+// two-factor authentication and the user does not have it enabled.
 func (core *User) IsAdmin(userID model.ID) (bool, error) {
 	user, err := core.GetByID(userID)
 	if err != nil {
 		return false, err
 	}
 
-	return user.IsAdmin, nil
+	if !user.IsAdmin || user.TOTPEnabled {
+		return user.IsAdmin, nil
+	}
+
+	settings, err := core.GetSettings()
+	if err != nil {
+		return false, err
+	}
+
+	if settings.RequireAdminTOTP {
+		return false, ErrAdminTOTPRequired
+	}
+
+	return true, nil
 }
 
 func (core *User) NewAdmin(userID model.ID) error {
@@ -234,7 +267,11 @@ func (core *User) Protected(userID model.ID) error {
 	return nil
 }
 
-func (core *User) NewSession(partial model.UserSessionPartial) (*model.UserSession, error) {
+func (core *User) NewSession(
+	partial model.UserSessionPartial,
+	ip string,
+	userAgent string,
+) (*model.UserSession, error) {
 	err := validate(core.validator, partial)
 	if err != nil {
 		return nil, err
@@ -254,12 +291,25 @@ func (core *User) NewSession(partial model.UserSessionPartial) (*model.UserSessi
 		return nil, ErrUserWrongPassword
 	}
 
+	if user.TOTPEnabled {
+		err = core.checkSecondFactor(user, partial.Code)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+
 	session := model.UserSession{
 		ID:        model.NewID(),
 		UserID:    user.ID,
-		CreateaAt: time.Now(),
-		Expires:   time.Now().Add(core.durationSession),
-		DeletedAt: time.Now().Add(core.durationSession),
+		LoginID:   model.NewID(),
+		IP:        ip,
+		UserAgent: userAgent,
+		LoggedAt:  now,
+		CreateaAt: now,
+		Expires:   now.Add(core.durationSession),
+		DeletedAt: now.Add(core.durationSession),
 	}
 
 	err = core.database.SaveSession(session)
@@ -301,7 +351,13 @@ func (core *User) GetSession(sessionID model.ID) (*model.UserSession, error) {
 	return session, nil
 }
 
-func (core *User) ReplaceSession(sessionID model.ID) (*model.UserSession, error) {
+// ReplaceSession creates a new session of the same login, the IP and user
+// agent are of the last request.
+func (core *User) ReplaceSession(
+	sessionID model.ID,
+	ip string,
+	userAgent string,
+) (*model.UserSession, error) {
 	currentSession, err := core.GetSession(sessionID)
 	if err != nil && !errors.Is(err, ErrUserSessionDeleted) {
 		return nil, err
@@ -316,12 +372,18 @@ func (core *User) ReplaceSession(sessionID model.ID) (*model.UserSession, error)
 		}
 	}
 
+	now := time.Now()
+
 	newSession := model.UserSession{
 		ID:        model.NewID(),
 		UserID:    currentSession.UserID,
-		CreateaAt: time.Now(),
-		Expires:   time.Now().Add(core.durationSession),
-		DeletedAt: time.Now().Add(core.durationSession),
+		LoginID:   currentSession.LoginID,
+		IP:        ip,
+		UserAgent: userAgent,
+		LoggedAt:  currentSession.LoggedAt,
+		CreateaAt: now,
+		Expires:   now.Add(core.durationSession),
+		DeletedAt: now.Add(core.durationSession),
 	}
 
 	err = core.database.SaveSession(newSession)
@@ -332,6 +394,110 @@ func (core *User) ReplaceSession(sessionID model.ID) (*model.UserSession, error)
 	return &newSession, nil
 }
 
+// GetSessions returns the logins of the user with the last session of each
+// one, the most recently used first.
+func (core *User) GetSessions(userID model.ID, currentSessionID model.ID) ([]model.ActiveSession, error) {
+	sessions, err := core.database.GetSessions(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sessions from database: %w", err)
+	}
+
+	logins := map[model.ID]model.ActiveSession{}
+
+	for _, session := range sessions {
+		if session.LoginID == (model.ID{}) {
+			continue
+		}
+
+		login, exist := logins[session.LoginID]
+		current := login.Current || session.ID == currentSessionID
+
+		if !exist || session.CreateaAt.After(login.LastUsedAt) {
+			login = model.ActiveSession{
+				ID:         session.LoginID,
+				IP:         session.IP,
+				UserAgent:  session.UserAgent,
+				LoggedAt:   session.LoggedAt,
+				LastUsedAt: session.CreateaAt,
+				Expires:    session.Expires,
+			}
+		}
+
+		login.Current = current
+		logins[session.LoginID] = login
+	}
+
+	active := maps.Values(logins)
+
+	slices.SortFunc(active, func(a, b model.ActiveSession) bool { return a.LastUsedAt.After(b.LastUsedAt) })
+
+	return active, nil
+}
+
+// RevokeSession revokes all sessions of the login.
+func (core *User) RevokeSession(userID model.ID, loginID model.ID) error {
+	exist, err := core.database.ExistLogin(userID, loginID)
+	if err != nil {
+		return fmt.Errorf("error checking if session exist in database: %w", err)
+	}
+
+	if !exist {
+		return ErrUserSessionDoesNotExist
+	}
+
+	err = core.database.RevokeLogin(userID, loginID)
+	if err != nil {
+		return fmt.Errorf("error revoking session in database: %w", err)
+	}
+
+	return nil
+}
+
+func (core *User) RevokeSessions(userID model.ID) error {
+	err := core.database.RevokeSessions(userID)
+	if err != nil {
+		return fmt.Errorf("error revoking sessions in database: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeUserSessions revokes all sessions of a user by an admin.
+func (core *User) RevokeUserSessions(userID model.ID) error {
+	exist, err := core.existByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if !exist {
+		return ErrUserDoesNotExist
+	}
+
+	return core.RevokeSessions(userID)
+}
+
+// Logout revokes the login of the session, the sessions created before the
+// logins are revoked by the ID.
+func (core *User) Logout(sessionID model.ID) error {
+	session, err := core.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session.LoginID == (model.ID{}) {
+		session.DeletedAt = time.Now()
+
+		err = core.database.UpdateSession(*session)
+		if err != nil {
+			return fmt.Errorf("error revoking session in database: %w", err)
+		}
+
+		return nil
+	}
+
+	return core.RevokeSession(session.UserID, session.LoginID)
+}
+
 func newUser(
 	database *data.User,
 	validate *validator.Validate,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/thiago-felipe-99/mail/publisher/model"
@@ -12,12 +13,32 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var ErrDuplicatedKey = errors.New("data with the same key already exist")
+
 type mongo[T any] struct {
 	collection *mongodb.Collection
 }
 
 func (database *mongo[T]) create(data T) error {
 	_, err := database.collection.InsertOne(context.Background(), data)
+	if err != nil {
+		if mongodb.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %w", ErrDuplicatedKey, err)
+		}
+
+		return fmt.Errorf("error creating data in database: %w", err)
+	}
+
+	return nil
+}
+
+func (database *mongo[T]) createMultiples(data []T) error {
+	documents := make([]any, 0, len(data))
+	for _, document := range data {
+		documents = append(documents, document)
+	}
+
+	_, err := database.collection.InsertMany(context.Background(), documents)
 	if err != nil {
 		return fmt.Errorf("error creating data in database: %w", err)
 	}
@@ -80,13 +101,47 @@ func (database *mongo[T]) update(dataID model.ID, update bson.D) error {
 	return nil
 }
 
+// upsertMultiples replaces the documents with the same IDs or creates them.
+func (database *mongo[T]) upsertMultiples(ids []model.ID, data []T) error {
+	models := make([]mongodb.WriteModel, 0, len(data))
+	for index, document := range data {
+		models = append(models, mongodb.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "_id", Value: ids[index]}}).
+			SetReplacement(document).
+			SetUpsert(true))
+	}
+
+	_, err := database.collection.BulkWrite(context.Background(), models)
+	if err != nil {
+		return fmt.Errorf("error upserting data in database: %w", err)
+	}
+
+	return nil
+}
+
 func createMongoDatabase[T any](client *mongodb.Client, database, collection string) *mongo[T] {
 	return &mongo[T]{client.Database(database).Collection(collection)}
 }
 
+// ownerFilter matches the documents of the organization or the personal ones
+// of the user, the documents created before the organizations do not have the
+// organization field.
+func ownerFilter(userField string, ownerID model.ID) bson.E {
+	return bson.E{Key: "$or", Value: bson.A{
+		bson.D{{Key: "organization_id", Value: ownerID}},
+		bson.D{
+			{Key: userField, Value: ownerID},
+			{Key: "organization_id", Value: bson.D{{Key: "$in", Value: bson.A{model.ID{}, nil}}}},
+		},
+	}}
+}
+
 type User struct {
 	users    *mongo[model.User]
 	sessions *mongo[model.UserSession]
+	resets   *mongo[model.PasswordReset]
+	forgot   *mongo[model.PasswordForgotRequest]
+	settings *mongo[model.Settings]
 }
 
 func (database *User) Create(user model.User) error {
@@ -153,6 +208,112 @@ func (database *User) Update(user model.User) error {
 	return database.users.update(user.ID, update)
 }
 
+// UpdateTOTP saves the two-factor authentication of the user and clears the
+// wrong codes.
+func (database *User) UpdateTOTP(user model.User) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "totp_enabled", Value: user.TOTPEnabled},
+			{Key: "totp_secret", Value: user.TOTPSecret},
+			{Key: "totp_last_step", Value: user.TOTPLastStep},
+			{Key: "totp_failures", Value: 0},
+			{Key: "totp_locked_until", Value: time.Time{}},
+			{Key: "recovery_codes", Value: user.RecoveryCodes},
+		}},
+	}
+
+	return database.users.update(user.ID, update)
+}
+
+func (database *User) SaveRecoveryCodes(userID model.ID, hashes []string) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "recovery_codes", Value: hashes},
+		}},
+	}
+
+	return database.users.update(userID, update)
+}
+
+// useSecondFactor also clears the wrong codes.
+func (database *User) useSecondFactor(filter bson.D, update bson.D) (bool, error) {
+	result, err := database.users.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, fmt.Errorf("error using second factor: %w", err)
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// UseTOTPStep saves the step of the TOTP code, it is false when a code of the
+// step or of a newer one was already used.
+func (database *User) UseTOTPStep(userID model.ID, step int64) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: userID},
+		{Key: "totp_last_step", Value: bson.D{{Key: "$lt", Value: step}}},
+	}
+
+	return database.useSecondFactor(filter, bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "totp_last_step", Value: step},
+			{Key: "totp_failures", Value: 0},
+		}},
+	})
+}
+
+// UseRecoveryCode removes the recovery code, it is false when the code was
+// already used.
+func (database *User) UseRecoveryCode(userID model.ID, hash string) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: userID},
+		{Key: "recovery_codes", Value: hash},
+	}
+
+	return database.useSecondFactor(filter, bson.D{
+		{Key: "$pull", Value: bson.D{{Key: "recovery_codes", Value: hash}}},
+		{Key: "$set", Value: bson.D{{Key: "totp_failures", Value: 0}}},
+	})
+}
+
+// FailSecondFactor counts a wrong code, the second factor is locked until
+// lockedUntil after maxFailures wrong codes in a row.
+func (database *User) FailSecondFactor(userID model.ID, maxFailures int, lockedUntil time.Time) error {
+	user := &model.User{}
+
+	err := database.users.collection.FindOneAndUpdate(
+		context.Background(),
+		bson.D{{Key: "_id", Value: userID}},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "totp_failures", Value: 1}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(user)
+	if err != nil {
+		return fmt.Errorf("error counting wrong second factor: %w", err)
+	}
+
+	if user.TOTPFailures < maxFailures {
+		return nil
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: userID},
+		{Key: "totp_failures", Value: bson.D{{Key: "$gte", Value: maxFailures}}},
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "totp_failures", Value: 0},
+			{Key: "totp_locked_until", Value: lockedUntil},
+		}},
+	}
+
+	_, err = database.users.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return fmt.Errorf("error locking second factor: %w", err)
+	}
+
+	return nil
+}
+
 func (database *User) SaveSession(session model.UserSession) error {
 	return database.sessions.create(session)
 }
@@ -192,16 +353,196 @@ func (database *User) UpdateSession(session model.UserSession) error {
 	return database.sessions.update(session.ID, update)
 }
 
+// GetSessions returns the sessions that were not expired or revoked.
+func (database *User) GetSessions(userID model.ID) ([]model.UserSession, error) {
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$gt", Value: time.Now()}}},
+	}
+
+	return database.sessions.getMultiples(filter)
+}
+
+func (database *User) ExistLogin(userID model.ID, loginID model.ID) (bool, error) {
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "login_id", Value: loginID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$gt", Value: time.Now()}}},
+	}
+
+	return database.sessions.exist(filter)
+}
+
+func (database *User) revokeSessions(filter bson.D) error {
+	now := time.Now()
+
+	filter = append(filter, bson.E{Key: "deleted_at", Value: bson.D{{Key: "$gt", Value: now}}})
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "deleted_at", Value: now},
+		}},
+	}
+
+	_, err := database.sessions.collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return fmt.Errorf("error revoking sessions: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeLogin revokes all sessions of the login.
+func (database *User) RevokeLogin(userID model.ID, loginID model.ID) error {
+	return database.revokeSessions(bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "login_id", Value: loginID},
+	})
+}
+
+func (database *User) RevokeSessions(userID model.ID) error {
+	return database.revokeSessions(bson.D{{Key: "user_id", Value: userID}})
+}
+
+func (database *User) SaveReset(reset model.PasswordReset) error {
+	return database.resets.create(reset)
+}
+
+// ClaimReset marks the token as used and returns it, it is nil when the token
+// does not exist, was used or has expired.
+func (database *User) ClaimReset(hash string) (*model.PasswordReset, error) {
+	now := time.Now()
+
+	filter := bson.D{
+		{Key: "hash", Value: hash},
+		{Key: "used_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+		{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: now}}},
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "used_at", Value: now},
+		}},
+	}
+
+	reset := &model.PasswordReset{}
+
+	err := database.resets.collection.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(reset)
+	if errors.Is(err, mongodb.ErrNoDocuments) {
+		return nil, nil //nolint:nilnil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error claiming password reset: %w", err)
+	}
+
+	return reset, nil
+}
+
+// UseResets marks all tokens of the user as used.
+func (database *User) UseResets(userID model.ID) error {
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "used_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "used_at", Value: time.Now()},
+		}},
+	}
+
+	_, err := database.resets.collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return fmt.Errorf("error using password resets: %w", err)
+	}
+
+	return nil
+}
+
+func (database *User) SaveForgot(request model.PasswordForgotRequest) error {
+	return database.forgot.create(request)
+}
+
+func (database *User) countForgot(filter bson.D, since time.Time) (int, error) {
+	filter = append(filter, bson.E{Key: "requested_at", Value: bson.D{{Key: "$gt", Value: since}}})
+
+	count, err := database.forgot.collection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return 0, fmt.Errorf("error counting password reset requests in database: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// CountForgotByIP counts the password reset requests of the IP after since.
+func (database *User) CountForgotByIP(requestIP string, since time.Time) (int, error) {
+	return database.countForgot(bson.D{{Key: "request_ip", Value: requestIP}}, since)
+}
+
+// CountForgotByKey counts the password reset requests to the email after
+// since.
+func (database *User) CountForgotByKey(key string, since time.Time) (int, error) {
+	return database.countForgot(bson.D{{Key: "key", Value: key}}, since)
+}
+
+func (database *User) setup() error {
+	_, err := database.forgot.collection.Indexes().CreateMany(context.Background(), []mongodb.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{Keys: bson.D{{Key: "request_ip", Value: 1}, {Key: "requested_at", Value: 1}}},
+		{Keys: bson.D{{Key: "key", Value: 1}, {Key: "requested_at", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating password reset requests indexes: %w", err)
+	}
+
+	return nil
+}
+
+// GetSettings returns the default settings when they were never saved.
+func (database *User) GetSettings() (*model.Settings, error) {
+	filter := bson.D{{Key: "_id", Value: model.ID{}}}
+
+	exist, err := database.settings.exist(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exist {
+		return &model.Settings{}, nil
+	}
+
+	return database.settings.get(filter)
+}
+
+func (database *User) SaveSettings(settings model.Settings) error {
+	settings.ID = model.ID{}
+
+	return database.settings.upsertMultiples([]model.ID{settings.ID}, []model.Settings{settings})
+}
+
 func newUserDatabase(client *mongodb.Client) *User {
 	return &User{
 		createMongoDatabase[model.User](client, "users", "users"),
 		createMongoDatabase[model.UserSession](client, "users", "sessions"),
+		createMongoDatabase[model.PasswordReset](client, "users", "password_resets"),
+		createMongoDatabase[model.PasswordForgotRequest](client, "users", "password_forgot"),
+		createMongoDatabase[model.Settings](client, "users", "settings"),
 	}
 }
 
 type Queue struct {
 	queues *mongo[model.Queue]
 	emails *mongo[model.Email]
+	jobs   *mongo[model.BulkJob]
 }
 
 func (database *Queue) Create(queue model.Queue) error {
@@ -245,10 +586,159 @@ func (database *Queue) SaveEmail(email model.Email) error {
 	return database.emails.create(email)
 }
 
+func (database *Queue) SaveEmails(emails []model.Email) error {
+	return database.emails.createMultiples(emails)
+}
+
+func (database *Queue) SaveBulkJob(job model.BulkJob) error {
+	return database.jobs.create(job)
+}
+
+func (database *Queue) GetBulkJob(jobID model.ID, userID model.ID) (*model.BulkJob, error) {
+	filter := bson.D{
+		{Key: "_id", Value: jobID},
+		{Key: "user_id", Value: userID},
+	}
+
+	return database.jobs.get(filter)
+}
+
+func (database *Queue) ExistBulkJob(jobID model.ID, userID model.ID) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: jobID},
+		{Key: "user_id", Value: userID},
+	}
+
+	return database.jobs.exist(filter)
+}
+
+func (database *Queue) GetEmail(emailID model.ID) (*model.Email, error) {
+	filter := bson.D{
+		{Key: "_id", Value: emailID},
+	}
+
+	return database.emails.get(filter)
+}
+
+// ClaimEmail leases a pending email or a relaying email with the lease expired
+// until leaseExpiresAt, it is nil when there is not an email to relay.
+func (database *Queue) ClaimEmail(leaseExpiresAt time.Time) (*model.Email, error) {
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "status", Value: model.EmailPending}},
+		bson.D{
+			{Key: "status", Value: model.EmailRelaying},
+			{Key: "lease_expires_at", Value: bson.D{{Key: "$lt", Value: time.Now()}}},
+		},
+	}}}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: model.EmailRelaying},
+			{Key: "lease_expires_at", Value: leaseExpiresAt},
+		}},
+	}
+
+	email := &model.Email{}
+
+	err := database.emails.collection.FindOneAndUpdate(
+		context.Background(),
+		filter,
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(email)
+	if errors.Is(err, mongodb.ErrNoDocuments) {
+		return nil, nil //nolint:nilnil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error claiming email: %w", err)
+	}
+
+	return email, nil
+}
+
+// PublishedEmail marks the relaying email as published, an email already
+// bounced is not changed.
+func (database *Queue) PublishedEmail(emailID model.ID, publishedAt time.Time) error {
+	filter := bson.D{
+		{Key: "_id", Value: emailID},
+		{Key: "status", Value: model.EmailRelaying},
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: model.EmailPublished},
+			{Key: "published_at", Value: publishedAt},
+			{Key: "lease_expires_at", Value: time.Time{}},
+		}},
+	}
+
+	_, err := database.emails.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return fmt.Errorf("error marking email as published: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseEmail moves the relaying email back to pending after its publish
+// failed, so the outbox does not wait the lease to relay it.
+func (database *Queue) ReleaseEmail(emailID model.ID) error {
+	filter := bson.D{
+		{Key: "_id", Value: emailID},
+		{Key: "status", Value: model.EmailRelaying},
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: model.EmailPending},
+			{Key: "lease_expires_at", Value: time.Time{}},
+		}},
+	}
+
+	_, err := database.emails.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return fmt.Errorf("error releasing email: %w", err)
+	}
+
+	return nil
+}
+
+// AddBounce saves the bounce once, the same bounce processed again is ignored.
+func (database *Queue) AddBounce(emailID model.ID, bounce model.Bounce, status string) error {
+	update := bson.D{
+		{Key: "$addToSet", Value: bson.D{{Key: "bounces", Value: bounce}}},
+	}
+
+	if status != "" {
+		update = append(update, bson.E{Key: "$set", Value: bson.D{{Key: "status", Value: status}}})
+	}
+
+	return database.emails.update(emailID, update)
+}
+
+// GetEmailsByQueue returns the emails sent to the queues.
+func (database *Queue) GetEmailsByQueue(queues []string) ([]model.Email, error) {
+	filter := bson.D{
+		{Key: "queue", Value: bson.D{{Key: "$in", Value: queues}}},
+	}
+
+	return database.emails.getMultiples(filter)
+}
+
+func (database *Queue) ExistEmail(emailID model.ID) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: emailID},
+	}
+
+	return database.emails.exist(filter)
+}
+
 func newQueueDatabase(client *mongodb.Client) *Queue {
 	return &Queue{
 		createMongoDatabase[model.Queue](client, "email", "queues"),
 		createMongoDatabase[model.Email](client, "email", "sent"),
+		createMongoDatabase[model.BulkJob](client, "email", "jobs"),
 	}
 }
 
@@ -293,9 +783,9 @@ func (database *Template) Get(name string) (*model.Template, error) {
 	return database.templates.get(filter)
 }
 
-func (database *Template) GetByUser(userID model.ID) ([]model.Template, error) {
+func (database *Template) GetByOwner(ownerID model.ID) ([]model.Template, error) {
 	filter := bson.D{
-		{Key: "created_by", Value: userID},
+		ownerFilter("created_by", ownerID),
 		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
 	}
 
@@ -320,28 +810,28 @@ func (database *Attachment) Create(attachment model.Attachment) error {
 	return database.attachments.create(attachment)
 }
 
-func (database *Attachment) Exist(id model.ID, userID model.ID) (bool, error) {
+func (database *Attachment) Exist(id model.ID, ownerID model.ID) (bool, error) {
 	filter := bson.D{
 		{Key: "_id", Value: id},
-		{Key: "user_id", Value: userID},
+		ownerFilter("user_id", ownerID),
 	}
 
 	return database.attachments.exist(filter)
 }
 
-func (database *Attachment) ExistByName(id model.ID, minioName string) (bool, error) {
+func (database *Attachment) ExistByName(ownerID model.ID, minioName string) (bool, error) {
 	filter := bson.D{
-		{Key: "user_id", Value: id},
+		ownerFilter("user_id", ownerID),
 		{Key: "minio_name", Value: minioName},
 	}
 
 	return database.attachments.exist(filter)
 }
 
-func (database *Attachment) Get(id model.ID, userID model.ID) (*model.Attachment, error) {
+func (database *Attachment) Get(id model.ID, ownerID model.ID) (*model.Attachment, error) {
 	filter := bson.D{
 		{Key: "_id", Value: id},
-		{Key: "user_id", Value: userID},
+		ownerFilter("user_id", ownerID),
 	}
 
 	return database.attachments.get(filter)
@@ -355,8 +845,8 @@ func (database *Attachment) GetByMinioName(minioName string) (*model.Attachment,
 	return database.attachments.get(filter)
 }
 
-func (database *Attachment) GetAttachments(userID model.ID) ([]model.Attachment, error) {
-	filter := bson.D{{Key: "user_id", Value: userID}}
+func (database *Attachment) GetAttachments(ownerID model.ID) ([]model.Attachment, error) {
+	filter := bson.D{ownerFilter("user_id", ownerID)}
 
 	return database.attachments.getMultiples(filter)
 }
@@ -378,7 +868,9 @@ func newAttachmenteDatabase(client *mongodb.Client) *Attachment {
 }
 
 type EmailList struct {
-	lists *mongo[model.EmailList]
+	lists    *mongo[model.EmailList]
+	contacts *mongo[model.ListContact]
+	pending  *mongo[model.PendingContact]
 }
 
 func (database *EmailList) Create(emailList model.EmailList) error {
@@ -394,43 +886,51 @@ func (database *EmailList) Exist(listID model.ID) (bool, error) {
 	return database.lists.exist(filter)
 }
 
-func (database *EmailList) ExistByName(name string, userID model.ID) (bool, error) {
+func (database *EmailList) ExistByName(name string, ownerID model.ID) (bool, error) {
 	filter := bson.D{
 		{Key: "name", Value: name},
-		{Key: "created_by", Value: userID},
+		ownerFilter("created_by", ownerID),
 		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
 	}
 
 	return database.lists.exist(filter)
 }
 
-func (database *EmailList) GetAllUser(userID model.ID) ([]model.EmailList, error) {
+func (database *EmailList) GetAllUser(ownerID model.ID) ([]model.EmailList, error) {
 	filter := bson.D{
-		{Key: "created_by", Value: userID},
+		ownerFilter("created_by", ownerID),
 		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
 	}
 
 	return database.lists.getMultiples(filter)
 }
 
-func (database *EmailList) GetByName(name string, userID model.ID) (*model.EmailList, error) {
+func (database *EmailList) GetByName(name string, ownerID model.ID) (*model.EmailList, error) {
 	filter := bson.D{
 		{Key: "name", Value: name},
-		{Key: "created_by", Value: userID},
+		ownerFilter("created_by", ownerID),
 		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
 	}
 
 	return database.lists.get(filter)
 }
 
-func (database *EmailList) GetAll() ([]model.EmailList, error) {
-	return database.lists.getAll()
+func (database *EmailList) GetByID(listID model.ID) (*model.EmailList, error) {
+	filter := bson.D{
+		{Key: "_id", Value: listID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.lists.get(filter)
+}
+
+func (database *EmailList) GetAll() ([]model.EmailList, error) {
+	return database.lists.getAll()
 }
 
 func (database *EmailList) Update(list model.EmailList) error {
 	update := bson.D{
 		{Key: "$set", Value: bson.D{
-			{Key: "emails", Value: list.Emails},
 			{Key: "email_alias", Value: list.EmailAlias},
 			{Key: "description", Value: list.Description},
 			{Key: "deleted_at", Value: list.DeletedAt},
@@ -453,9 +953,339 @@ func (database *EmailList) UpdateInfo(listID model.ID, info model.EmailListInfo)
 	return database.lists.update(listID, update)
 }
 
+func contactsFilter(listID model.ID) bson.D {
+	return bson.D{{Key: "list_id", Value: listID}}
+}
+
+// EachContact calls each with the contacts of the list sorted by email, the
+// contacts are not loaded at once.
+func (database *EmailList) EachContact(listID model.ID, each func(model.ListContact) error) error {
+	ctx := context.Background()
+
+	cursor, err := database.contacts.collection.Find(
+		ctx,
+		contactsFilter(listID),
+		options.Find().SetSort(bson.D{{Key: "key", Value: 1}}),
+	)
+	if err != nil {
+		return fmt.Errorf("error getting contacts from database: %w", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		contact := model.ListContact{}
+
+		err := cursor.Decode(&contact)
+		if err != nil {
+			return fmt.Errorf("error parsing contact: %w", err)
+		}
+
+		err = each(contact)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = cursor.Err()
+	if err != nil {
+		return fmt.Errorf("error reading contacts from database: %w", err)
+	}
+
+	return nil
+}
+
+func (database *EmailList) CountContacts(listID model.ID) (int, error) {
+	count, err := database.contacts.collection.CountDocuments(context.Background(), contactsFilter(listID))
+	if err != nil {
+		return 0, fmt.Errorf("error counting contacts in database: %w", err)
+	}
+
+	return int(count), nil
+}
+
+func (database *EmailList) GetContactsByKeys(listID model.ID, keys []string) ([]model.ListContact, error) {
+	filter := append(contactsFilter(listID), bson.E{Key: "key", Value: bson.D{{Key: "$in", Value: keys}}})
+
+	return database.contacts.getMultiples(filter)
+}
+
+func (database *EmailList) ExistContactByKey(listID model.ID, key string) (bool, error) {
+	return database.contacts.exist(append(contactsFilter(listID), bson.E{Key: "key", Value: key}))
+}
+
+func (database *EmailList) ExistContact(listID model.ID, contactID model.ID) (bool, error) {
+	return database.contacts.exist(append(contactsFilter(listID), bson.E{Key: "_id", Value: contactID}))
+}
+
+func (database *EmailList) GetContact(listID model.ID, contactID model.ID) (*model.ListContact, error) {
+	return database.contacts.get(append(contactsFilter(listID), bson.E{Key: "_id", Value: contactID}))
+}
+
+func (database *EmailList) writeContacts(models []mongodb.WriteModel) error {
+	if len(models) == 0 {
+		return nil
+	}
+
+	_, err := database.contacts.collection.BulkWrite(
+		context.Background(),
+		models,
+		options.BulkWrite().SetOrdered(false),
+	)
+	if err != nil {
+		return fmt.Errorf("error saving contacts in database: %w", err)
+	}
+
+	return nil
+}
+
+// AddContacts creates the contacts that are not on the list, the saved
+// contacts are not changed.
+func (database *EmailList) AddContacts(contacts []model.ListContact) error {
+	models := make([]mongodb.WriteModel, 0, len(contacts))
+
+	for _, contact := range contacts {
+		models = append(models, mongodb.NewUpdateOneModel().
+			SetFilter(append(contactsFilter(contact.ListID), bson.E{Key: "key", Value: contact.Key})).
+			SetUpdate(bson.D{{Key: "$setOnInsert", Value: bson.D{
+				{Key: "_id", Value: contact.ID},
+				{Key: "email", Value: contact.Email},
+				{Key: "name", Value: contact.Name},
+				{Key: "attributes", Value: contact.Attributes},
+				{Key: "consent", Value: contact.Consent},
+			}}}).
+			SetUpsert(true))
+	}
+
+	return database.writeContacts(models)
+}
+
+// SaveContacts creates the contacts or replaces the name and attributes of
+// the saved ones, the consent is only replaced when it is sent.
+func (database *EmailList) SaveContacts(contacts []model.ListContact) error {
+	models := make([]mongodb.WriteModel, 0, len(contacts))
+
+	for _, contact := range contacts {
+		set := bson.D{
+			{Key: "name", Value: contact.Name},
+			{Key: "attributes", Value: contact.Attributes},
+		}
+
+		if contact.Consent != nil {
+			set = append(set, bson.E{Key: "consent", Value: contact.Consent})
+		}
+
+		models = append(models, mongodb.NewUpdateOneModel().
+			SetFilter(append(contactsFilter(contact.ListID), bson.E{Key: "key", Value: contact.Key})).
+			SetUpdate(bson.D{
+				{Key: "$set", Value: set},
+				{Key: "$setOnInsert", Value: bson.D{
+					{Key: "_id", Value: contact.ID},
+					{Key: "email", Value: contact.Email},
+				}},
+			}).
+			SetUpsert(true))
+	}
+
+	return database.writeContacts(models)
+}
+
+func (database *EmailList) RemoveContacts(listID model.ID, keys []string) error {
+	filter := append(contactsFilter(listID), bson.E{Key: "key", Value: bson.D{{Key: "$in", Value: keys}}})
+
+	_, err := database.contacts.collection.DeleteMany(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("error removing contacts from database: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveContact is false when the contact was not on the list.
+func (database *EmailList) RemoveContact(listID model.ID, contactID model.ID) (bool, error) {
+	filter := append(contactsFilter(listID), bson.E{Key: "_id", Value: contactID})
+
+	result, err := database.contacts.collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return false, fmt.Errorf("error removing contact from database: %w", err)
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// ContactAttributes returns the attributes names of the list contacts.
+func (database *EmailList) ContactAttributes(listID model.ID) ([]string, error) {
+	ctx := context.Background()
+
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: contactsFilter(listID)}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "attributes", Value: bson.D{{Key: "$objectToArray", Value: bson.D{
+				{Key: "$ifNull", Value: bson.A{"$attributes", bson.D{}}},
+			}}}},
+		}}},
+		bson.D{{Key: "$unwind", Value: "$attributes"}},
+		bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$attributes.k"}}}},
+	}
+
+	cursor, err := database.contacts.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error getting contacts attributes from database: %w", err)
+	}
+
+	results := []struct {
+		Name string `bson:"_id"`
+	}{}
+
+	err = cursor.All(ctx, &results)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing contacts attributes: %w", err)
+	}
+
+	attributes := make([]string, 0, len(results))
+	for _, result := range results {
+		attributes = append(attributes, result.Name)
+	}
+
+	return attributes, nil
+}
+
+func (database *EmailList) CreatePending(pending model.PendingContact) error {
+	return database.pending.create(pending)
+}
+
+func (database *EmailList) GetPending(listID model.ID, pendingID model.ID) (*model.PendingContact, error) {
+	return database.pending.get(append(contactsFilter(listID), bson.E{Key: "_id", Value: pendingID}))
+}
+
+func (database *EmailList) ExistPending(listID model.ID, pendingID model.ID) (bool, error) {
+	return database.pending.exist(append(contactsFilter(listID), bson.E{Key: "_id", Value: pendingID}))
+}
+
+func (database *EmailList) RemovePending(listID model.ID, pendingID model.ID) error {
+	filter := append(contactsFilter(listID), bson.E{Key: "_id", Value: pendingID})
+
+	_, err := database.pending.collection.DeleteOne(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("error removing pending subscription from database: %w", err)
+	}
+
+	return nil
+}
+
+func (database *EmailList) countPending(filter bson.D, since time.Time) (int, error) {
+	filter = append(filter, bson.E{Key: "requested_at", Value: bson.D{{Key: "$gt", Value: since}}})
+
+	count, err := database.pending.collection.CountDocuments(context.Background(), filter)
+	if err != nil {
+		return 0, fmt.Errorf("error counting pending subscriptions in database: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// CountPendingByList counts the subscriptions of the list requested after
+// since.
+func (database *EmailList) CountPendingByList(listID model.ID, since time.Time) (int, error) {
+	return database.countPending(contactsFilter(listID), since)
+}
+
+// CountPendingByIP counts the subscriptions of all lists requested by the IP
+// after since.
+func (database *EmailList) CountPendingByIP(requestIP string, since time.Time) (int, error) {
+	return database.countPending(bson.D{{Key: "request_ip", Value: requestIP}}, since)
+}
+
+// CountPendingByKey counts the subscriptions of all lists requested to the
+// email after since.
+func (database *EmailList) CountPendingByKey(key string, since time.Time) (int, error) {
+	return database.countPending(bson.D{{Key: "key", Value: key}}, since)
+}
+
+// migrateEmails moves the emails saved on the lists documents to the
+// contacts, keeping their IDs so the sent unsubscribe links still work.
+func (database *EmailList) migrateEmails() error {
+	ctx := context.Background()
+	filter := bson.D{{Key: "emails", Value: bson.D{{Key: "$exists", Value: true}}}}
+
+	cursor, err := database.lists.collection.Find(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("error getting email lists to migrate: %w", err)
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		list := struct {
+			ID     model.ID            `bson:"_id"`
+			Emails map[model.ID]string `bson:"emails"`
+		}{}
+
+		err := cursor.Decode(&list)
+		if err != nil {
+			return fmt.Errorf("error parsing email list to migrate: %w", err)
+		}
+
+		contacts := make([]model.ListContact, 0, len(list.Emails))
+
+		for id, email := range list.Emails {
+			contacts = append(contacts, model.ListContact{
+				ID:     id,
+				ListID: list.ID,
+				Email:  email,
+				Key:    strings.ToLower(strings.TrimSpace(email)),
+			})
+		}
+
+		err = database.AddContacts(contacts)
+		if err != nil {
+			return err
+		}
+
+		err = database.lists.update(list.ID, bson.D{{Key: "$unset", Value: bson.D{{Key: "emails", Value: ""}}}})
+		if err != nil {
+			return fmt.Errorf("error removing migrated emails from email list: %w", err)
+		}
+	}
+
+	err = cursor.Err()
+	if err != nil {
+		return fmt.Errorf("error reading email lists to migrate: %w", err)
+	}
+
+	return nil
+}
+
+func (database *EmailList) setup() error {
+	_, err := database.contacts.collection.Indexes().CreateOne(context.Background(), mongodb.IndexModel{
+		Keys:    bson.D{{Key: "list_id", Value: 1}, {Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("error creating contacts index: %w", err)
+	}
+
+	_, err = database.pending.collection.Indexes().CreateMany(context.Background(), []mongodb.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{Keys: bson.D{{Key: "list_id", Value: 1}, {Key: "requested_at", Value: 1}}},
+		{Keys: bson.D{{Key: "request_ip", Value: 1}, {Key: "requested_at", Value: 1}}},
+		{Keys: bson.D{{Key: "key", Value: 1}, {Key: "requested_at", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating pending subscriptions indexes: %w", err)
+	}
+
+	return database.migrateEmails()
+}
+
 func newEmailListDatabase(vlient *mongodb.Client) *EmailList {
 	return &EmailList{
 		createMongoDatabase[model.EmailList](vlient, "email_lists", "lists"),
+		createMongoDatabase[model.ListContact](vlient, "email_lists", "contacts"),
+		createMongoDatabase[model.PendingContact](vlient, "email_lists", "pending"),
 	}
 }
 
@@ -473,20 +1303,393 @@ func NewMongoClient(uri string) (*mongodb.Client, error) {
 	return connection, nil
 }
 
+type Suppression struct {
+	suppressions *mongo[model.Suppression]
+}
+
+// Save creates the suppressions, the suppressions with the same ID are
+// replaced.
+func (database *Suppression) Save(suppressions []model.Suppression) error {
+	ids := make([]model.ID, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		ids = append(ids, suppression.ID)
+	}
+
+	return database.suppressions.upsertMultiples(ids, suppressions)
+}
+
+// Insert creates the suppression, ErrDuplicatedKey is returned when a
+// suppression with the same ID exist, even if it was deleted.
+func (database *Suppression) Insert(suppression model.Suppression) error {
+	return database.suppressions.create(suppression)
+}
+
+func (database *Suppression) Exist(email string, userID model.ID) (bool, error) {
+	filter := bson.D{
+		{Key: "email", Value: email},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.suppressions.exist(filter)
+}
+
+func (database *Suppression) Get(email string, userID model.ID) (*model.Suppression, error) {
+	filter := bson.D{
+		{Key: "email", Value: email},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.suppressions.get(filter)
+}
+
+func (database *Suppression) GetAll(userID model.ID) ([]model.Suppression, error) {
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.suppressions.getMultiples(filter)
+}
+
+// GetSuppressed returns the suppressions of the user and the global ones of
+// the emails.
+func (database *Suppression) GetSuppressed(
+	emails []string,
+	userID model.ID,
+) ([]model.Suppression, error) {
+	filter := bson.D{
+		{Key: "email", Value: bson.D{{Key: "$in", Value: emails}}},
+		{Key: "user_id", Value: bson.D{{Key: "$in", Value: bson.A{userID, model.ID{}}}}},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.suppressions.getMultiples(filter)
+}
+
+func (database *Suppression) Update(suppression model.Suppression) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "reason", Value: suppression.Reason},
+			{Key: "deleted_at", Value: suppression.DeletedAt},
+			{Key: "deleted_by", Value: suppression.DeletedBy},
+		}},
+	}
+
+	return database.suppressions.update(suppression.ID, update)
+}
+
+func newSuppressionDatabase(client *mongodb.Client) *Suppression {
+	return &Suppression{
+		createMongoDatabase[model.Suppression](client, "email", "suppressions"),
+	}
+}
+
+type APIKey struct {
+	keys *mongo[model.APIKey]
+}
+
+func (database *APIKey) Create(key model.APIKey) error {
+	return database.keys.create(key)
+}
+
+func (database *APIKey) ExistByHash(hash string) (bool, error) {
+	filter := bson.D{
+		{Key: "hash", Value: hash},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.keys.exist(filter)
+}
+
+func (database *APIKey) GetByHash(hash string) (*model.APIKey, error) {
+	filter := bson.D{
+		{Key: "hash", Value: hash},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.keys.get(filter)
+}
+
+func (database *APIKey) Exist(keyID model.ID, userID model.ID) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: keyID},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.keys.exist(filter)
+}
+
+func (database *APIKey) Get(keyID model.ID, userID model.ID) (*model.APIKey, error) {
+	filter := bson.D{
+		{Key: "_id", Value: keyID},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.keys.get(filter)
+}
+
+func (database *APIKey) GetAll(userID model.ID) ([]model.APIKey, error) {
+	filter := bson.D{
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.keys.getMultiples(filter)
+}
+
+func (database *APIKey) Update(key model.APIKey) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "deleted_at", Value: key.DeletedAt},
+			{Key: "deleted_by", Value: key.DeletedBy},
+		}},
+	}
+
+	return database.keys.update(key.ID, update)
+}
+
+func newAPIKeyDatabase(client *mongodb.Client) *APIKey {
+	return &APIKey{
+		createMongoDatabase[model.APIKey](client, "users", "api_keys"),
+	}
+}
+
+type Permission struct {
+	roles  *mongo[model.Role]
+	grants *mongo[model.QueueGrant]
+}
+
+func (database *Permission) CreateRole(role model.Role) error {
+	return database.roles.create(role)
+}
+
+func (database *Permission) ExistRole(name string) (bool, error) {
+	filter := bson.D{
+		{Key: "name", Value: name},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.roles.exist(filter)
+}
+
+func (database *Permission) GetRole(name string) (*model.Role, error) {
+	filter := bson.D{
+		{Key: "name", Value: name},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.roles.get(filter)
+}
+
+func (database *Permission) GetRoles() ([]model.Role, error) {
+	filter := bson.D{
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.roles.getMultiples(filter)
+}
+
+func (database *Permission) UpdateRole(role model.Role) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "permissions", Value: role.Permissions},
+			{Key: "deleted_at", Value: role.DeletedAt},
+			{Key: "deleted_by", Value: role.DeletedBy},
+		}},
+	}
+
+	return database.roles.update(role.ID, update)
+}
+
+// SaveGrant creates the grant, a grant with the same ID is replaced.
+func (database *Permission) SaveGrant(grant model.QueueGrant) error {
+	return database.grants.upsertMultiples([]model.ID{grant.ID}, []model.QueueGrant{grant})
+}
+
+func (database *Permission) ExistGrant(queue string, userID model.ID) (bool, error) {
+	filter := bson.D{
+		{Key: "queue", Value: queue},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.grants.exist(filter)
+}
+
+func (database *Permission) GetGrant(queue string, userID model.ID) (*model.QueueGrant, error) {
+	filter := bson.D{
+		{Key: "queue", Value: queue},
+		{Key: "user_id", Value: userID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.grants.get(filter)
+}
+
+func (database *Permission) GetGrants(queue string) ([]model.QueueGrant, error) {
+	filter := bson.D{
+		{Key: "queue", Value: queue},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.grants.getMultiples(filter)
+}
+
+// DeleteGrants deletes all grants of the queue.
+func (database *Permission) DeleteGrants(queue string, deletedAt time.Time, deletedBy model.ID) error {
+	filter := bson.D{
+		{Key: "queue", Value: queue},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "deleted_at", Value: deletedAt},
+			{Key: "deleted_by", Value: deletedBy},
+		}},
+	}
+
+	_, err := database.grants.collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return fmt.Errorf("error deleting grants: %w", err)
+	}
+
+	return nil
+}
+
+func (database *Permission) UpdateGrant(grant model.QueueGrant) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "deleted_at", Value: grant.DeletedAt},
+			{Key: "deleted_by", Value: grant.DeletedBy},
+		}},
+	}
+
+	return database.grants.update(grant.ID, update)
+}
+
+func newPermissionDatabase(client *mongodb.Client) *Permission {
+	return &Permission{
+		createMongoDatabase[model.Role](client, "users", "roles"),
+		createMongoDatabase[model.QueueGrant](client, "users", "queue_grants"),
+	}
+}
+
+type Organization struct {
+	organizations *mongo[model.Organization]
+}
+
+func (database *Organization) Create(organization model.Organization) error {
+	return database.organizations.create(organization)
+}
+
+func (database *Organization) Exist(organizationID model.ID) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: organizationID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.organizations.exist(filter)
+}
+
+func (database *Organization) Get(organizationID model.ID) (*model.Organization, error) {
+	filter := bson.D{
+		{Key: "_id", Value: organizationID},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.organizations.get(filter)
+}
+
+// GetByMember returns the organizations of the user, the members are saved by
+// the hex of the ID.
+func (database *Organization) GetByMember(userID model.ID) ([]model.Organization, error) {
+	key, _ := userID.MarshalKey()
+
+	filter := bson.D{
+		{Key: "members." + key, Value: bson.D{{Key: "$exists", Value: true}}},
+		{Key: "deleted_at", Value: bson.D{{Key: "$eq", Value: time.Time{}}}},
+	}
+
+	return database.organizations.getMultiples(filter)
+}
+
+func (database *Organization) Update(organization model.Organization) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "name", Value: organization.Name},
+			{Key: "members", Value: organization.Members},
+			{Key: "deleted_at", Value: organization.DeletedAt},
+			{Key: "deleted_by", Value: organization.DeletedBy},
+		}},
+	}
+
+	return database.organizations.update(organization.ID, update)
+}
+
+func newOrganizationDatabase(client *mongodb.Client) *Organization {
+	return &Organization{
+		createMongoDatabase[model.Organization](client, "users", "organizations"),
+	}
+}
+
 type Databases struct {
 	*User
 	*Queue
 	*Template
 	*Attachment
 	*EmailList
+	*Suppression
+	*APIKey
+	*Permission
+	*Organization
+	client *mongodb.Client
+}
+
+func (databases *Databases) Ping(ctx context.Context) error {
+	err := databases.client.Ping(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error ping server: %w", err)
+	}
+
+	return nil
+}
+
+func (databases *Databases) Disconnect(ctx context.Context) error {
+	err := databases.client.Disconnect(ctx)
+	if err != nil {
+		return fmt.Errorf("error disconnecting from the database: %w", err)
+	}
+
+	return nil
+}
+
+// Setup creates the indexes and migrates the data saved by the previous
+// versions.
+func (databases *Databases) Setup() error {
+	err := databases.User.setup()
+	if err != nil {
+		return err
+	}
+
+	return databases.EmailList.setup()
 }
 
 func NewDatabases(client *mongodb.Client) *Databases {
 	return &Databases{
-		User:       newUserDatabase(client),
-		Queue:      newQueueDatabase(client),
-		Template:   newTemplateDatabase(client),
-		Attachment: newAttachmenteDatabase(client),
-		EmailList:  newEmailListDatabase(client),
+		client:       client,
+		User:         newUserDatabase(client),
+		Queue:        newQueueDatabase(client),
+		Template:     newTemplateDatabase(client),
+		Attachment:   newAttachmenteDatabase(client),
+		EmailList:    newEmailListDatabase(client),
+		Suppression:  newSuppressionDatabase(client),
+		APIKey:       newAPIKeyDatabase(client),
+		Permission:   newPermissionDatabase(client),
+		Organization: newOrganizationDatabase(client),
 	}
 }
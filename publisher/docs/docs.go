@@ -29,6 +29,14 @@ const docTemplate = `{
                     "attachment"
                 ],
                 "summary": "Get user attachments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "all attachments",
@@ -74,6 +82,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.AttachmentPartial"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -124,6 +138,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -178,6 +198,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -234,6 +260,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -270,9 +302,62 @@ const docTemplate = `{
                 }
             }
         },
+        "/email/bulk/{id}": {
+            "get": {
+                "description": "Get a bulk job with the result of each recipient.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Get bulk job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "job id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "bulk job",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkJob"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a invalid job ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "bulk job does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
         "/email/list": {
             "get": {
-                "description": "Get all user email list.",
+                "description": "Get all user email list, the emails of a list are returned by the list route.",
                 "consumes": [
                     "application/json"
                 ],
@@ -283,6 +368,14 @@ const docTemplate = `{
                     "emailList"
                 ],
                 "summary": "Get all user email list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "email list",
@@ -328,6 +421,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.EmailListPartial"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -384,13 +483,19 @@ const docTemplate = `{
                         "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "email list",
                         "schema": {
-                            "$ref": "#/definitions/model.EmailList"
+                            "$ref": "#/definitions/model.EmailListContacts"
                         }
                     },
                     "401": {
@@ -441,6 +546,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.EmailListInfo"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -501,6 +612,12 @@ const docTemplate = `{
                         "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -560,6 +677,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.EmailListEmails"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -596,6 +719,156 @@ const docTemplate = `{
                 }
             }
         },
+        "/email/list/{name}/export": {
+            "get": {
+                "description": "Export email list to CSV.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "emailList"
+                ],
+                "summary": "Export email list to CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "email list name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV with email, name and the contact attributes columns",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "email list does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/email/list/{name}/import": {
+            "post": {
+                "description": "Import emails from CSV to email list, the invalid and duplicated rows are reported and ignored.\nThe upload is streamed, the form fields must be sent before the file. The emails are compared\nwithout case and the rows that do not change the contacts are counted as unchanged.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "emailList"
+                ],
+                "summary": "Import emails from CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "email list name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV with the emails",
+                        "name": "emails",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "email column, default is email",
+                        "name": "emailColumn",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name column, default is name",
+                        "name": "nameColumn",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "columns saved as contact attributes, default is all",
+                        "name": "attributes",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only validates the CSV",
+                        "name": "dryRun",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "import report",
+                        "schema": {
+                            "$ref": "#/definitions/model.EmailListImport"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid CSV was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "email list does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
         "/email/list/{name}/remove": {
             "delete": {
                 "description": "Remove emails to email list.",
@@ -625,6 +898,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.EmailListEmails"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -677,7 +956,7 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "user id from emailist",
+                        "description": "user or organization id from email list",
                         "name": "user_id",
                         "in": "path",
                         "required": true
@@ -757,7 +1036,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a RabbitMQ queue with DLX.",
+                "description": "Create a RabbitMQ queue with DLX, the queue of a organization is only used by its members.\nThe queue name can not end with -high or -dlx, they are used by the high priority and DLX queues.",
                 "consumes": [
                     "application/json"
                 ],
@@ -804,6 +1083,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
+                    "404": {
+                        "description": "organization does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
                     "409": {
                         "description": "queue already exist",
                         "schema": {
@@ -821,7 +1106,7 @@ const docTemplate = `{
         },
         "/email/queue/{name}": {
             "delete": {
-                "description": "Delete a queue with DLX.",
+                "description": "Delete a queue with DLX, the permissions granted on the queue are revoked.",
                 "consumes": [
                     "application/json"
                 ],
@@ -875,9 +1160,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/email/queue/{name}/send": {
+        "/email/queue/{name}/bulk": {
             "post": {
-                "description": "Sends an email to the RabbitMQ queue.",
+                "description": "Sends a template email to each recipient with its own template data, the emails of the email lists are recipients with the contact attributes as template data. The recipients with the emails of the lists have a max of 10000.",
                 "consumes": [
                     "application/json"
                 ],
@@ -887,7 +1172,7 @@ const docTemplate = `{
                 "tags": [
                     "queue"
                 ],
-                "summary": "Sends email",
+                "summary": "Sends bulk email",
                 "parameters": [
                     {
                         "type": "string",
@@ -897,24 +1182,30 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "email",
-                        "name": "queue",
+                        "description": "bulk params",
+                        "name": "bulk",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Email"
+                            "$ref": "#/definitions/model.BulkEmailPartial"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "email sent successfully",
+                        "description": "job with the result of each recipient",
                         "schema": {
-                            "$ref": "#/definitions/controllers.sent"
+                            "$ref": "#/definitions/model.BulkJob"
                         }
                     },
                     "400": {
-                        "description": "an invalid email param was sent",
+                        "description": "an invalid bulk param was sent",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -926,7 +1217,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "current user is not admin",
+                        "description": "user does not have permission on the queue",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -946,27 +1237,92 @@ const docTemplate = `{
                 }
             }
         },
-        "/email/template": {
-            "get": {
-                "description": "Get all user templates.",
+        "/email/queue/{name}/bulk/csv": {
+            "post": {
+                "description": "Sends a template email to each row of a CSV, the name and email columns are the receiver and the other columns are the template data. The rows with the emails of the lists have a max of 10000.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "template"
+                    "queue"
+                ],
+                "summary": "Sends bulk email from CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "email subject",
+                        "name": "subject",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "template name",
+                        "name": "template",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "high or low",
+                        "name": "priority",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "attachments IDs",
+                        "name": "attachments",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "email lists names",
+                        "name": "emailLists",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV with name, email and the template fields columns",
+                        "name": "recipients",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Get user template",
                 "responses": {
                     "200": {
-                        "description": "all user templates",
+                        "description": "job with the result of each row",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Template"
-                            }
+                            "$ref": "#/definitions/model.BulkJob"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid bulk param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
                         }
                     },
                     "401": {
@@ -975,6 +1331,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
+                    "404": {
+                        "description": "queue does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -982,51 +1344,48 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a email template.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/email/queue/{name}/dlx": {
+            "get": {
+                "description": "Get the number of emails that were not sent after the max retries, the user needs the dlx permission on the queue.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "template"
+                    "queue"
                 ],
-                "summary": "Creating template",
+                "summary": "Get DLX",
                 "parameters": [
                     {
-                        "description": "template params",
-                        "name": "template",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.TemplatePartial"
-                        }
+                        "type": "string",
+                        "description": "queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "create template successfully",
+                    "200": {
+                        "description": "number of emails on the DLX",
                         "schema": {
-                            "$ref": "#/definitions/controllers.sent"
+                            "$ref": "#/definitions/model.DLX"
                         }
                     },
-                    "400": {
-                        "description": "an invalid template param was sent",
+                    "401": {
+                        "description": "user session has expired",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "401": {
-                        "description": "user session has expired",
+                    "403": {
+                        "description": "user does not have permission on the queue",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "409": {
-                        "description": "template name already exist",
+                    "404": {
+                        "description": "queue does not exist",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1038,29 +1397,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/email/template/all": {
-            "get": {
-                "description": "Get all email templates.",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Remove the emails of the DLX, the user needs the dlx permission on the queue.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "template"
+                    "queue"
+                ],
+                "summary": "Purge DLX",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get templates",
                 "responses": {
                     "200": {
-                        "description": "all templates",
+                        "description": "number of emails removed",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Template"
-                            }
+                            "$ref": "#/definitions/model.DLX"
                         }
                     },
                     "401": {
@@ -1069,6 +1429,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
+                    "403": {
+                        "description": "user does not have permission on the queue",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "queue does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -1078,23 +1450,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/email/template/{name}": {
-            "get": {
-                "description": "Get a email template.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/email/queue/{name}/dlx/requeue": {
+            "post": {
+                "description": "Send the emails of the DLX to the queue again, the user needs the dlx permission on the queue.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "template"
+                    "queue"
                 ],
-                "summary": "Get template",
+                "summary": "Requeue DLX",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "template name",
+                        "description": "queue name",
                         "name": "name",
                         "in": "path",
                         "required": true
@@ -1102,9 +1471,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "all templates",
+                        "description": "number of emails sent to the queue again",
                         "schema": {
-                            "$ref": "#/definitions/model.Template"
+                            "$ref": "#/definitions/model.DLX"
                         }
                     },
                     "401": {
@@ -1113,13 +1482,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
+                    "403": {
+                        "description": "user does not have permission on the queue",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
                     "404": {
-                        "description": "template does not exist",
+                        "description": "queue does not exist",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/controllers.sent"
-                            }
+                            "$ref": "#/definitions/controllers.sent"
                         }
                     },
                     "500": {
@@ -1129,58 +1501,51 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a email template.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/email/queue/{name}/emails": {
+            "get": {
+                "description": "Get the emails sent to a queue, the user needs the history permission on the queue.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "template"
+                    "queue"
                 ],
-                "summary": "Update template",
+                "summary": "Get queue emails",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "template name",
+                        "description": "queue name",
                         "name": "name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "template params",
-                        "name": "template",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.TemplatePartial"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "template updated",
+                        "description": "emails sent to the queue, the newest first",
                         "schema": {
-                            "$ref": "#/definitions/controllers.sent"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Email"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "an invalid template param was sent",
+                    "401": {
+                        "description": "user session has expired",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "401": {
-                        "description": "user session has expired",
+                    "403": {
+                        "description": "user does not have permission on the queue",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
                     "404": {
-                        "description": "template does not exist",
+                        "description": "queue does not exist",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1192,23 +1557,22 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a email template.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/email/queue/{name}/grant": {
+            "get": {
+                "description": "Get the users with a role on the queue.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "template"
+                    "permission"
                 ],
-                "summary": "Delete template",
+                "summary": "Get queue grants",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "template name",
+                        "description": "queue name",
                         "name": "name",
                         "in": "path",
                         "required": true
@@ -1216,9 +1580,12 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "template deleted",
+                        "description": "queue grants",
                         "schema": {
-                            "$ref": "#/definitions/controllers.sent"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.QueueGrant"
+                            }
                         }
                     },
                     "401": {
@@ -1227,8 +1594,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "404": {
-                        "description": "template does not exist",
+                    "403": {
+                        "description": "current user is not admin",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1242,9 +1609,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
-            "get": {
-                "description": "Get current user informations.",
+        "/email/queue/{name}/grant/{userID}": {
+            "post": {
+                "description": "Grant a role on the queue to the user, it replaces the current role of the user on the queue.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1252,12 +1619,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "permission"
+                ],
+                "summary": "Grant role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "user ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "grant params",
+                        "name": "grant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.QueueGrantPartial"
+                        }
+                    }
                 ],
-                "summary": "Get user",
                 "responses": {
-                    "200": {
-                        "description": "user informations",
+                    "201": {
+                        "description": "role granted",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid grant param was sent",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1268,8 +1666,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
                     "404": {
-                        "description": "user does not exist",
+                        "description": "queue, user or role does not exist",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1282,38 +1686,40 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update user informatios.",
-                "consumes": [
-                    "application/json"
-                ],
+            "delete": {
+                "description": "Revoke the role on the queue from the user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "permission"
                 ],
-                "summary": "Update user",
+                "summary": "Revoke role",
                 "parameters": [
                     {
-                        "description": "user params",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.UserPartial"
-                        }
+                        "type": "string",
+                        "description": "queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "user ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "user updated",
+                        "description": "role revoked",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
                     "400": {
-                        "description": "an invalid user param was sent",
+                        "description": "was sent a invalid user ID",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1324,8 +1730,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
                     "404": {
-                        "description": "user does not exist",
+                        "description": "grant does not exist",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1337,9 +1749,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/email/queue/{name}/send": {
             "post": {
-                "description": "Create a user in application.",
+                "description": "Saves the email and sends it to the RabbitMQ queue, a pending or relaying email is published later by the outbox. The suppressed receivers are removed and reported.\nThe contacts of the email lists are blind receivers of one email, so it has no unsubscribe link or List-Unsubscribe header, the bulk send adds them to each contact.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1347,29 +1761,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "queue"
                 ],
-                "summary": "Create user",
+                "summary": "Sends email",
                 "parameters": [
                     {
-                        "description": "user params",
-                        "name": "user",
+                        "type": "string",
+                        "description": "queue name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "emails with the same key are sent only once",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "email",
+                        "name": "queue",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UserPartial"
+                            "$ref": "#/definitions/model.Email"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "user created successfully",
+                    "200": {
+                        "description": "email saved, it is pending until published to the queue",
                         "schema": {
-                            "$ref": "#/definitions/controllers.sent"
+                            "$ref": "#/definitions/model.EmailSent"
                         }
                     },
                     "400": {
-                        "description": "an invalid user param was sent",
+                        "description": "an invalid email param was sent",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1381,13 +1814,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "current user is not admin",
+                        "description": "user does not have permission on the queue",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "409": {
-                        "description": "user already exist",
+                    "404": {
+                        "description": "queue does not exist",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1399,9 +1832,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete current user.",
+            }
+        },
+        "/email/template": {
+            "get": {
+                "description": "Get all user templates.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1409,14 +1844,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "template"
+                ],
+                "summary": "Get user template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Delete user",
                 "responses": {
                     "200": {
-                        "description": "user deleted",
+                        "description": "all user templates",
                         "schema": {
-                            "$ref": "#/definitions/controllers.sent"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Template"
+                            }
                         }
                     },
                     "401": {
@@ -1425,18 +1871,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "403": {
-                        "description": "current user is protected",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.sent"
-                        }
-                    },
-                    "404": {
-                        "description": "user does not exist",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.sent"
-                        }
-                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -1444,11 +1878,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/user/admin/{userID}": {
+            },
             "post": {
-                "description": "Create a user admin.",
+                "description": "Create a email template.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1456,27 +1888,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "template"
                 ],
-                "summary": "Create admin",
+                "summary": "Creating template",
                 "parameters": [
+                    {
+                        "description": "template params",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TemplatePartial"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "user id to be promoted to admin",
-                        "name": "userID",
-                        "in": "path",
-                        "required": true
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "admin created successfully",
+                        "description": "create template successfully",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
                     "400": {
-                        "description": "was sent a invalid user ID",
+                        "description": "an invalid template param was sent",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1487,14 +1927,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "403": {
-                        "description": "current user is not admin",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.sent"
-                        }
-                    },
-                    "404": {
-                        "description": "user does not exist",
+                    "409": {
+                        "description": "template name already exist",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1506,9 +1940,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove the admin role from the user.",
+            }
+        },
+        "/email/template/all": {
+            "get": {
+                "description": "Get all email templates.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1516,29 +1952,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
-                ],
-                "summary": "Remove admin",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "user id to be removed from admin role",
-                        "name": "userID",
-                        "in": "path",
-                        "required": true
-                    }
+                    "template"
                 ],
+                "summary": "Get templates",
                 "responses": {
                     "200": {
-                        "description": "admin role removed",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.sent"
-                        }
-                    },
-                    "400": {
-                        "description": "was sent a invalid user ID",
+                        "description": "all templates",
                         "schema": {
-                            "$ref": "#/definitions/controllers.sent"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Template"
+                            }
                         }
                     },
                     "401": {
@@ -1547,18 +1971,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "403": {
-                        "description": "current user is not admin",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.sent"
-                        }
-                    },
-                    "404": {
-                        "description": "user does not exist",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.sent"
-                        }
-                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -1568,9 +1980,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/admin/{userID}/user": {
+        "/email/template/{name}": {
             "get": {
-                "description": "Get user by admin.",
+                "description": "Get a email template.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1578,23 +1990,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "template"
                 ],
-                "summary": "Get user",
+                "summary": "Get template",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "user id",
-                        "name": "userID",
+                        "description": "template name",
+                        "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "user informations",
+                        "description": "all templates",
                         "schema": {
-                            "$ref": "#/definitions/controllers.sent"
+                            "$ref": "#/definitions/model.Template"
                         }
                     },
                     "401": {
@@ -1603,16 +2021,13 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "403": {
-                        "description": "current user is not admin",
-                        "schema": {
-                            "$ref": "#/definitions/controllers.sent"
-                        }
-                    },
                     "404": {
-                        "description": "user does not exist",
+                        "description": "template does not exist",
                         "schema": {
-                            "$ref": "#/definitions/controllers.sent"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controllers.sent"
+                            }
                         }
                     },
                     "500": {
@@ -1623,8 +2038,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
-                "description": "Delete user by admin.",
+            "put": {
+                "description": "Update a email template.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1632,39 +2047,54 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "template"
                 ],
-                "summary": "Delete user",
+                "summary": "Update template",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "user id to be deleted",
-                        "name": "userID",
+                        "description": "template name",
+                        "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "template params",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TemplatePartial"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "user deleted",
+                        "description": "template updated",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "401": {
-                        "description": "user session has expired",
+                    "400": {
+                        "description": "an invalid template param was sent",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "403": {
-                        "description": "current user is protected",
+                    "401": {
+                        "description": "user session has expired",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
                     "404": {
-                        "description": "user does not exist",
+                        "description": "template does not exist",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1676,11 +2106,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/user/all": {
-            "get": {
-                "description": "Get all users informations.",
+            },
+            "delete": {
+                "description": "Delete a email template.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1688,12 +2116,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "template"
+                ],
+                "summary": "Delete template",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "template name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "organization of the resources",
+                        "name": "X-Organization-ID",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Get all users",
                 "responses": {
                     "200": {
-                        "description": "user informations",
+                        "description": "template deleted",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1704,8 +2147,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "403": {
-                        "description": "current user is not admin",
+                    "404": {
+                        "description": "template does not exist",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1719,25 +2162,71 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/session": {
-            "put": {
-                "description": "Refresh a user session and set in the response cookie.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/health/live": {
+            "get": {
+                "description": "Check if the publisher is alive.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "health"
                 ],
-                "summary": "Refresh session",
+                "summary": "Liveness",
                 "responses": {
                     "200": {
-                        "description": "user session refreshed successfully",
+                        "description": "publisher is alive",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Check MongoDB, RabbitMQ connection and Minio buckets, returning the status of each one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness",
+                "responses": {
+                    "200": {
+                        "description": "all dependencies are healthy",
+                        "schema": {
+                            "$ref": "#/definitions/model.Health"
+                        }
+                    },
+                    "503": {
+                        "description": "some dependency is unhealthy",
+                        "schema": {
+                            "$ref": "#/definitions/model.Health"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization": {
+            "get": {
+                "description": "Get the organizations of the current user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get organizations",
+                "responses": {
+                    "200": {
+                        "description": "user organizations",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Organization"
+                            }
+                        }
                     },
                     "401": {
                         "description": "user session has expired",
@@ -1754,7 +2243,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a user session and set in the response cookie.",
+                "description": "Create a organization, the current user is its owner.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1762,35 +2251,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "organization"
                 ],
-                "summary": "Create session",
+                "summary": "Create organization",
                 "parameters": [
                     {
-                        "description": "user params",
-                        "name": "user",
+                        "description": "organization params",
+                        "name": "organization",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.UserSessionPartial"
+                            "$ref": "#/definitions/model.OrganizationPartial"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "session created successfully",
+                        "description": "organization created",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
                     "400": {
-                        "description": "an invalid user param was sent",
+                        "description": "an invalid organization param was sent",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
                     },
-                    "404": {
-                        "description": "user does not exist",
+                    "401": {
+                        "description": "user session has expired",
                         "schema": {
                             "$ref": "#/definitions/controllers.sent"
                         }
@@ -1803,134 +2292,3393 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.sent": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string"
+        },
+        "/organization/{id}": {
+            "get": {
+                "description": "Get a organization of the current user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "organization",
+                        "schema": {
+                            "$ref": "#/definitions/model.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a invalid organization ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "organization does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a organization, only the owners can delete it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Delete organization",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "organization deleted",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a invalid organization ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not owner of the organization",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "organization does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
                 }
             }
         },
-        "model.Attachment": {
-            "type": "object",
-            "properties": {
-                "confirmedUpload": {
-                    "type": "boolean"
-                },
-                "contentType": {
-                    "type": "string"
-                },
-                "createdAt": {
+        "/organization/{id}/member/{userID}": {
+            "post": {
+                "description": "Add a user to the organization as owner or member, it replaces the current role of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Add organization member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "user ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "member params",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OrganizationMemberPartial"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "member added",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid member param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not owner of the organization",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "organization or user does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "409": {
+                        "description": "organization must have an owner",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a user from the organization, the owners can remove any member and a member can leave.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Remove organization member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "user ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "member removed",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "user is not owner of the organization",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "organization does not exist or user is not member",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "409": {
+                        "description": "organization must have an owner",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/role": {
+            "get": {
+                "description": "Get all roles.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permission"
+                ],
+                "summary": "Get roles",
+                "responses": {
+                    "200": {
+                        "description": "all roles",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Role"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a role with the permissions on the queues: send, history and dlx.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permission"
+                ],
+                "summary": "Create role",
+                "parameters": [
+                    {
+                        "description": "role params",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RolePartial"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "role created",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid role param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "409": {
+                        "description": "role already exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/role/{name}": {
+            "delete": {
+                "description": "Delete a role, the grants with the role do not have permissions anymore.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permission"
+                ],
+                "summary": "Delete role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "role name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "role deleted",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "role does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/settings": {
+            "get": {
+                "description": "Get the settings of the publisher.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get settings",
+                "responses": {
+                    "200": {
+                        "description": "settings",
+                        "schema": {
+                            "$ref": "#/definitions/model.Settings"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the settings of the publisher, requiring the two-factor authentication blocks the admin\nroutes for the admins without it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update settings",
+                "parameters": [
+                    {
+                        "description": "settings params",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Settings"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "settings updated",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid settings param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscribe/confirm/{token}": {
+            "get": {
+                "description": "Page asking to confirm the subscription to the email list.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Subscription confirmation page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "confirmation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "confirmation page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid or expired token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "subscription does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds the contact to the email list with the IP and time of the consent.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Confirm subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "confirmation token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "subscription confirmed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid or expired token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "subscription does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscribe/{user_id}/{name}": {
+            "post": {
+                "description": "Saves the contact as pending and sends the confirmation email, the contact is added to the email list when the link is confirmed. The subscriptions are limited by IP and by email in an hour and by the pending subscriptions of the list.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Subscribe to email list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user or organization id from email list",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "email list name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "contact",
+                        "name": "contact",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ContactPartial"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "confirmation email sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid contact param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "email list does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "429": {
+                        "description": "too many subscriptions",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppression": {
+            "get": {
+                "description": "Get the emails that do not receive emails from the user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Get user suppressions",
+                "responses": {
+                    "200": {
+                        "description": "user suppressions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Suppression"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Suppress an email, it is removed from the receivers of the user emails.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Suppress email",
+                "parameters": [
+                    {
+                        "description": "suppression params",
+                        "name": "suppression",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SuppressionPartial"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "email suppressed",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid suppression param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppression/bulk": {
+            "post": {
+                "description": "Suppress emails, the invalid ones are reported.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Suppress emails",
+                "parameters": [
+                    {
+                        "description": "suppressions",
+                        "name": "suppressions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SuppressionBulk"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "import report",
+                        "schema": {
+                            "$ref": "#/definitions/model.SuppressionImport"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid suppression param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppression/bulk/csv": {
+            "post": {
+                "description": "Suppress the emails of a CSV, the invalid ones are reported.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Suppress emails from CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV with email and reason columns",
+                        "name": "suppressions",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "reason of the rows without it, default is manual",
+                        "name": "reason",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "import report",
+                        "schema": {
+                            "$ref": "#/definitions/model.SuppressionImport"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid CSV was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppression/global": {
+            "get": {
+                "description": "Get the emails that do not receive emails from any user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Get global suppressions",
+                "responses": {
+                    "200": {
+                        "description": "global suppressions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Suppression"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Suppress an email, it is removed from the receivers of all emails.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Suppress email globally",
+                "parameters": [
+                    {
+                        "description": "suppression params",
+                        "name": "suppression",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SuppressionPartial"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "email suppressed",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid suppression param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppression/global/bulk": {
+            "post": {
+                "description": "Suppress emails for all users, the invalid ones are reported.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Suppress emails globally",
+                "parameters": [
+                    {
+                        "description": "suppressions",
+                        "name": "suppressions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SuppressionBulk"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "import report",
+                        "schema": {
+                            "$ref": "#/definitions/model.SuppressionImport"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid suppression param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppression/global/bulk/csv": {
+            "post": {
+                "description": "Suppress the emails of a CSV for all users, the invalid ones are reported.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Suppress emails globally from CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV with email and reason columns",
+                        "name": "suppressions",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "reason of the rows without it, default is manual",
+                        "name": "reason",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "import report",
+                        "schema": {
+                            "$ref": "#/definitions/model.SuppressionImport"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid CSV was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppression/global/{email}": {
+            "delete": {
+                "description": "Delete a global suppression.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Delete global suppression",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "suppressed email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "suppression deleted",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "suppression does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppression/{email}": {
+            "delete": {
+                "description": "Delete a suppression, the email receives the user emails again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "suppression"
+                ],
+                "summary": "Delete suppression",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "suppressed email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "suppression deleted",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "suppression does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/unsubscribe/{token}": {
+            "get": {
+                "description": "Page asking to confirm removing the email from the email list.",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "emailList"
+                ],
+                "summary": "Unsubscribe confirmation page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unsubscribe token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "confirmation page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid or expired token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "email list does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Removes the email from the email list, it is the List-Unsubscribe-Post (RFC 8058) endpoint.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "emailList"
+                ],
+                "summary": "One-click unsubscribe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "unsubscribe token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "email unsubscribed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid or expired token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "email list does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "get": {
+                "description": "Get current user informations.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get user",
+                "responses": {
+                    "200": {
+                        "description": "user informations",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "user does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update user informatios, all sessions of the user are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update user",
+                "parameters": [
+                    {
+                        "description": "user params",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UserPartial"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user updated",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid user param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "user does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a user in application.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "description": "user params",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UserPartial"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "user created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid user param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "409": {
+                        "description": "user already exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete current user and revoke its sessions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete user",
+                "responses": {
+                    "200": {
+                        "description": "user deleted",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is protected",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "user does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/admin/{userID}": {
+            "post": {
+                "description": "Create a user admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id to be promoted to admin",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "admin created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "user does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the admin role from the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Remove admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id to be removed from admin role",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "admin role removed",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "user does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/admin/{userID}/session": {
+            "delete": {
+                "description": "Revoke all sessions of a user by admin.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Revoke user sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "sessions revoked",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "user does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/admin/{userID}/user": {
+            "get": {
+                "description": "Get user by admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user informations",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "user does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete user by admin and revoke its sessions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "user id to be deleted",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "user deleted",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is protected",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "user does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/all": {
+            "get": {
+                "description": "Get all users informations.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "user informations",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "403": {
+                        "description": "current user is not admin",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/apikey": {
+            "get": {
+                "description": "Get the API keys of the user that were not deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get API keys",
+                "responses": {
+                    "200": {
+                        "description": "user API keys",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.APIKey"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create an API key, it is sent on the header 'Authorization: Bearer \u003ckey\u003e'. A key\nwithout scopes can access the send, templates and lists routes. The send scope only sends emails\nand uploads attachments, the admin routes can not be accessed with API keys.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create API key",
+                "parameters": [
+                    {
+                        "description": "API key params",
+                        "name": "apiKey",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.APIKeyPartial"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "API key created, the key is only returned now",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIKeyCreated"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid API key param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/apikey/{id}": {
+            "delete": {
+                "description": "Delete an API key, the key can not be used anymore.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API key deleted",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a invalid API key ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "API key does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/password/forgot": {
+            "post": {
+                "description": "Sends an email with a single use token to reset the password in background, the response is the same when the email does not have a user. The requests are limited by IP and by email in an hour.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Forgot password",
+                "parameters": [
+                    {
+                        "description": "user email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PasswordForgot"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "reset email sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid email was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "password reset is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "429": {
+                        "description": "too many password reset requests",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/password/reset": {
+            "post": {
+                "description": "Sets the new password with the token sent by email, the sessions of the user are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "token and new password",
+                        "name": "reset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PasswordResetPartial"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "password updated",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid or expired token",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/session": {
+            "get": {
+                "description": "Get the active sessions of the current user with the IP and user agent of the last request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get sessions",
+                "responses": {
+                    "200": {
+                        "description": "active sessions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ActiveSession"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Refresh a user session and set in the response cookie.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Refresh session",
+                "responses": {
+                    "200": {
+                        "description": "user session refreshed successfully",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a user session and set in the response cookie. With two-factor authentication enabled the code\nof the authenticator app or a recovery code is required, the second factor is locked for some minutes\nafter many wrong codes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create session",
+                "parameters": [
+                    {
+                        "description": "user params",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UserSessionPartial"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "session created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "an invalid user param was sent",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "two-factor authentication code is required",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "user does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "429": {
+                        "description": "too many wrong two-factor authentication codes",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Revoke the current session and remove it from the cookie.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "200": {
+                        "description": "session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/session/all": {
+            "delete": {
+                "description": "Revoke all sessions of the current user, including the current one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Revoke sessions",
+                "responses": {
+                    "200": {
+                        "description": "sessions revoked",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/session/{id}": {
+            "delete": {
+                "description": "Revoke a session of the current user, the ID is of the active sessions list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Revoke session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a invalid session ID",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "session does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/totp": {
+            "post": {
+                "description": "Create a new TOTP secret for the current user, the URI can be shown as a QR code to the authenticator\napp. The two-factor authentication is enabled only after the first code is verified.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Enroll TOTP",
+                "responses": {
+                    "200": {
+                        "description": "TOTP secret and provisioning URI",
+                        "schema": {
+                            "$ref": "#/definitions/model.TOTPEnrollment"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "409": {
+                        "description": "two-factor authentication is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Disable the two-factor authentication of the current user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Disable TOTP",
+                "parameters": [
+                    {
+                        "description": "authenticator app or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TOTPCode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "two-factor authentication disabled",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "two-factor authentication is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "429": {
+                        "description": "too many wrong two-factor authentication codes",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/totp/recovery": {
+            "post": {
+                "description": "Replace all recovery codes of the current user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Regenerate recovery codes",
+                "parameters": [
+                    {
+                        "description": "authenticator app or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TOTPCode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "recovery codes",
+                        "schema": {
+                            "$ref": "#/definitions/model.RecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "two-factor authentication is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "429": {
+                        "description": "too many wrong two-factor authentication codes",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/totp/verify": {
+            "post": {
+                "description": "Verify the first code of the enrolled secret and enable the two-factor authentication. The recovery\ncodes are returned only once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Enable TOTP",
+                "parameters": [
+                    {
+                        "description": "authenticator app code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TOTPCode"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "recovery codes",
+                        "schema": {
+                            "$ref": "#/definitions/model.RecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "was sent a wrong code",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "401": {
+                        "description": "user session has expired",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "404": {
+                        "description": "two-factor authentication was not enrolled",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "409": {
+                        "description": "two-factor authentication is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.sent"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.sent": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.APIKey": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.APIKeyCreated": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.APIKeyPartial": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "expiresInDays": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.ActiveSession": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "boolean"
+                },
+                "expires": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "loggedAt": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Attachment": {
+            "type": "object",
+            "properties": {
+                "confirmedUpload": {
+                    "type": "boolean"
+                },
+                "contentType": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "minioName": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organizationId": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AttachmentPartial": {
+            "type": "object",
+            "required": [
+                "contentType",
+                "name",
+                "size"
+            ],
+            "properties": {
+                "contentType": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "model.AttachmentURL": {
+            "type": "object",
+            "properties": {
+                "formData": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "minioName": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Bounce": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "diagnostic": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "receivedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BulkEmailPartial": {
+            "type": "object",
+            "required": [
+                "subject",
+                "template"
+            ],
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "emailLists": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "high",
+                        "low"
+                    ]
+                },
+                "recipients": {
+                    "type": "array",
+                    "maxItems": 10000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.BulkRecipient"
+                    }
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "template": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BulkJob": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "queue": {
+                    "type": "string"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BulkRow"
+                    }
+                },
+                "template": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BulkRecipient": {
+            "type": "object",
+            "required": [
+                "email",
+                "name"
+            ],
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BulkRow": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "boolean"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "emailId": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Consent": {
+            "type": "object",
+            "properties": {
+                "confirmIp": {
+                    "type": "string"
+                },
+                "confirmedAt": {
+                    "type": "string"
+                },
+                "requestIp": {
+                    "type": "string"
+                },
+                "requestedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Contact": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "consent": {
+                    "$ref": "#/definitions/model.Consent"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ContactPartial": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DLX": {
+            "type": "object",
+            "properties": {
+                "dlx": {
+                    "type": "string"
+                },
+                "messages": {
+                    "type": "integer"
+                },
+                "queue": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DependencyHealth": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Email": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "blindReceivers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Receiver"
+                    }
+                },
+                "bounces": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Bounce"
+                    }
+                },
+                "emailLists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "idempotencyKey": {
+                    "type": "string"
+                },
+                "jobId": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "publishedAt": {
+                    "type": "string"
+                },
+                "queue": {
+                    "type": "string"
+                },
+                "receivers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Receiver"
+                    }
+                },
+                "sentAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "suppressed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "template": {
+                    "$ref": "#/definitions/model.TemplateData"
+                },
+                "unsubscribe": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EmailList": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "emailAlias": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organizationId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EmailListContacts": {
+            "type": "object",
+            "properties": {
+                "contacts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.Contact"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "type": "string"
+                },
+                "deletedBy": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "emailAlias": {
+                    "type": "string"
+                },
+                "emails": {
+                    "description": "Email uses map[id] to have the possibility to remove by ID without revealing which email will be removed",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organizationId": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EmailListEmails": {
+            "type": "object",
+            "properties": {
+                "contacts": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.ContactPartial"
+                    }
+                },
+                "emails": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.EmailListImport": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "integer"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "duplicated": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ImportError"
+                    }
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.EmailListInfo": {
+            "type": "object",
+            "required": [
+                "description",
+                "emailAlias",
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "emailAlias": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EmailListPartial": {
+            "type": "object",
+            "required": [
+                "description",
+                "emailAlias",
+                "name"
+            ],
+            "properties": {
+                "contacts": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.ContactPartial"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "emailAlias": {
+                    "type": "string"
+                },
+                "emails": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EmailSent": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "suppressed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.Health": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DependencyHealth"
+                    }
+                },
+                "healthy": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.ImportError": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Organization": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
                     "type": "string"
                 },
-                "id": {
+                "deletedAt": {
                     "type": "string"
                 },
-                "minioName": {
+                "deletedBy": {
                     "type": "string"
                 },
-                "name": {
+                "id": {
                     "type": "string"
                 },
-                "size": {
-                    "type": "integer"
+                "members": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "userId": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "model.AttachmentPartial": {
+        "model.OrganizationMemberPartial": {
             "type": "object",
             "required": [
-                "contentType",
-                "name",
-                "size"
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "member"
+                    ]
+                }
+            }
+        },
+        "model.OrganizationPartial": {
+            "type": "object",
+            "required": [
+                "name"
             ],
             "properties": {
-                "contentType": {
-                    "type": "string"
-                },
                 "name": {
                     "type": "string"
-                },
-                "size": {
-                    "type": "integer",
-                    "minimum": 1
                 }
             }
         },
-        "model.AttachmentURL": {
+        "model.PasswordForgot": {
             "type": "object",
+            "required": [
+                "email"
+            ],
             "properties": {
-                "formData": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "id": {
+                "email": {
                     "type": "string"
-                },
-                "minioName": {
+                }
+            }
+        },
+        "model.PasswordResetPartial": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
                     "type": "string"
                 },
-                "url": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "model.Email": {
+        "model.Queue": {
             "type": "object",
             "properties": {
-                "attachments": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "blindReceivers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.Receiver"
-                    }
-                },
-                "emailLists": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "createdAt": {
+                    "type": "string"
                 },
-                "id": {
+                "createdBy": {
                     "type": "string"
                 },
-                "message": {
+                "deletedAt": {
                     "type": "string"
                 },
-                "receivers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.Receiver"
-                    }
+                "deletedBy": {
+                    "type": "string"
                 },
-                "sentAt": {
+                "dlx": {
                     "type": "string"
                 },
-                "subject": {
+                "id": {
                     "type": "string"
                 },
-                "template": {
-                    "$ref": "#/definitions/model.TemplateData"
+                "maxRetries": {
+                    "type": "integer"
                 },
-                "userId": {
+                "name": {
+                    "type": "string"
+                },
+                "organizationId": {
                     "type": "string"
+                },
+                "priority": {
+                    "type": "boolean"
                 }
             }
         },
-        "model.EmailList": {
+        "model.QueueGrant": {
             "type": "object",
             "properties": {
                 "createdAt": {
@@ -1945,89 +5693,79 @@ const docTemplate = `{
                 "deletedBy": {
                     "type": "string"
                 },
-                "description": {
+                "id": {
                     "type": "string"
                 },
-                "emailAlias": {
+                "queue": {
                     "type": "string"
                 },
-                "emails": {
-                    "description": "Email uses map[id] to have the possibility to remove by ID without revealing which email will be removed",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "id": {
+                "role": {
                     "type": "string"
                 },
-                "name": {
+                "userId": {
                     "type": "string"
                 }
             }
         },
-        "model.EmailListEmails": {
+        "model.QueueGrantPartial": {
             "type": "object",
             "required": [
-                "emails"
+                "role"
             ],
             "properties": {
-                "emails": {
-                    "type": "array",
-                    "minItems": 1,
-                    "items": {
-                        "type": "string"
-                    }
+                "role": {
+                    "type": "string"
                 }
             }
         },
-        "model.EmailListInfo": {
+        "model.QueuePartial": {
             "type": "object",
             "required": [
-                "description",
-                "emailAlias",
                 "name"
             ],
             "properties": {
-                "description": {
-                    "type": "string"
+                "maxRetries": {
+                    "type": "integer",
+                    "minimum": 1
                 },
-                "emailAlias": {
+                "name": {
                     "type": "string"
                 },
-                "name": {
+                "organization": {
                     "type": "string"
+                },
+                "priority": {
+                    "type": "boolean"
                 }
             }
         },
-        "model.EmailListPartial": {
+        "model.Receiver": {
             "type": "object",
             "required": [
-                "description",
-                "emailAlias",
-                "emails",
+                "email",
                 "name"
             ],
             "properties": {
-                "description": {
+                "email": {
                     "type": "string"
                 },
-                "emailAlias": {
+                "name": {
                     "type": "string"
-                },
-                "emails": {
+                }
+            }
+        },
+        "model.RecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "codes": {
                     "type": "array",
-                    "minItems": 1,
                     "items": {
                         "type": "string"
                     }
-                },
-                "name": {
-                    "type": "string"
                 }
             }
         },
-        "model.Queue": {
+        "model.Role": {
             "type": "object",
             "properties": {
                 "createdAt": {
@@ -2042,46 +5780,149 @@ const docTemplate = `{
                 "deletedBy": {
                     "type": "string"
                 },
-                "dlx": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.RolePartial": {
+            "type": "object",
+            "required": [
+                "name",
+                "permissions"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.Settings": {
+            "type": "object",
+            "properties": {
+                "requireAdminTotp": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.Suppression": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
                     "type": "string"
                 },
+                "email": {
+                    "type": "string"
+                },
+                "global": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "string"
                 },
-                "maxRetries": {
-                    "type": "integer"
+                "reason": {
+                    "type": "string"
                 },
-                "name": {
+                "userId": {
                     "type": "string"
                 }
             }
         },
-        "model.QueuePartial": {
+        "model.SuppressionBulk": {
             "type": "object",
             "required": [
-                "name"
+                "suppressions"
             ],
             "properties": {
-                "maxRetries": {
-                    "type": "integer",
-                    "minimum": 1
+                "suppressions": {
+                    "type": "array",
+                    "maxItems": 10000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.SuppressionPartial"
+                    }
+                }
+            }
+        },
+        "model.SuppressionImport": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ImportError"
+                    }
                 },
-                "name": {
-                    "type": "string"
+                "invalid": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "suppressed": {
+                    "type": "integer"
                 }
             }
         },
-        "model.Receiver": {
+        "model.SuppressionPartial": {
             "type": "object",
             "required": [
                 "email",
-                "name"
+                "reason"
             ],
             "properties": {
                 "email": {
                     "type": "string"
                 },
-                "name": {
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "unsubscribed",
+                        "bounced",
+                        "complained",
+                        "manual"
+                    ]
+                }
+            }
+        },
+        "model.TOTPCode": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TOTPEnrollment": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "uri": {
                     "type": "string"
                 }
             }
@@ -2113,6 +5954,9 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "organizationId": {
+                    "type": "string"
+                },
                 "template": {
                     "type": "string"
                 }
@@ -2175,6 +6019,9 @@ const docTemplate = `{
                 "password"
             ],
             "properties": {
+                "code": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
@@ -0,0 +1,315 @@
+package mailbox
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const imapTimeout = time.Minute
+
+var ErrIMAP = errors.New("imap error")
+
+type imapResponse struct {
+	line    string
+	literal []byte
+}
+
+// IMAP reads the unseen messages of a mailbox, a processed message is marked
+// as seen. Only the commands needed are implemented.
+type IMAP struct {
+	address  string
+	user     string
+	password string
+	mailbox  string
+	useTLS   bool
+	conn     net.Conn
+	reader   *bufio.Reader
+	tag      int
+}
+
+func imapQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+
+	return `"` + value + `"`
+}
+
+func (client *IMAP) readLine() (string, error) {
+	line, err := client.reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("error reading from imap: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// literalSize returns the size of the literal at the end of the line.
+func literalSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+
+	start := strings.LastIndex(line, "{")
+	if start < 0 {
+		return 0, false
+	}
+
+	size, err := strconv.Atoi(line[start+1 : len(line)-1])
+	if err != nil {
+		return 0, false
+	}
+
+	return size, true
+}
+
+func (client *IMAP) read() (*imapResponse, error) {
+	line, err := client.readLine()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &imapResponse{line: line}
+
+	for size, found := literalSize(line); found; size, found = literalSize(line) {
+		literal := make([]byte, size)
+
+		_, err := io.ReadFull(client.reader, literal)
+		if err != nil {
+			return nil, fmt.Errorf("error reading literal from imap: %w", err)
+		}
+
+		response.literal = append(response.literal, literal...)
+
+		line, err = client.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		response.line += line
+	}
+
+	return response, nil
+}
+
+// command sends the command and returns the untagged responses.
+func (client *IMAP) command(command string) ([]imapResponse, error) {
+	client.tag++
+	tag := "a" + strconv.Itoa(client.tag)
+
+	err := client.conn.SetDeadline(time.Now().Add(imapTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("error setting imap deadline: %w", err)
+	}
+
+	_, err = fmt.Fprintf(client.conn, "%s %s\r\n", tag, command)
+	if err != nil {
+		return nil, fmt.Errorf("error writing to imap: %w", err)
+	}
+
+	responses := []imapResponse{}
+
+	for {
+		response, err := client.read()
+		if err != nil {
+			return nil, err
+		}
+
+		status, found := strings.CutPrefix(response.line, tag+" ")
+		if !found {
+			responses = append(responses, *response)
+
+			continue
+		}
+
+		if !strings.HasPrefix(status, "OK") {
+			return nil, fmt.Errorf("%w: %s", ErrIMAP, status)
+		}
+
+		return responses, nil
+	}
+}
+
+func (client *IMAP) connect(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: imapTimeout}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if client.useTLS {
+		host, _, _ := net.SplitHostPort(client.address)
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", client.address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", client.address)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error connecting to imap: %w", err)
+	}
+
+	client.conn = conn
+	client.reader = bufio.NewReader(conn)
+
+	greeting, err := client.readLine()
+	if err != nil {
+		client.disconnect()
+
+		return err
+	}
+
+	if !strings.HasPrefix(greeting, "* OK") {
+		client.disconnect()
+
+		return fmt.Errorf("%w: %s", ErrIMAP, greeting)
+	}
+
+	_, err = client.command("LOGIN " + imapQuote(client.user) + " " + imapQuote(client.password))
+	if err != nil {
+		client.disconnect()
+
+		return fmt.Errorf("error logging in imap: %w", err)
+	}
+
+	_, err = client.command("SELECT " + imapQuote(client.mailbox))
+	if err != nil {
+		client.disconnect()
+
+		return fmt.Errorf("error selecting imap mailbox: %w", err)
+	}
+
+	return nil
+}
+
+func (client *IMAP) disconnect() {
+	if client.conn != nil {
+		client.conn.Close()
+	}
+
+	client.conn = nil
+	client.reader = nil
+}
+
+func (client *IMAP) unseen() ([]string, error) {
+	responses, err := client.command("UID SEARCH UNSEEN")
+	if err != nil {
+		return nil, fmt.Errorf("error searching imap messages: %w", err)
+	}
+
+	uids := []string{}
+
+	for _, response := range responses {
+		found, exist := strings.CutPrefix(response.line, "* SEARCH")
+		if exist {
+			uids = append(uids, strings.Fields(found)...)
+		}
+	}
+
+	return uids, nil
+}
+
+func (client *IMAP) fetch(uid string) ([]byte, error) {
+	responses, err := client.command("UID FETCH " + uid + " BODY.PEEK[]")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching imap message: %w", err)
+	}
+
+	for _, response := range responses {
+		if strings.Contains(response.line, " FETCH ") && response.literal != nil {
+			return response.literal, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: message %s without body", ErrIMAP, uid)
+}
+
+// Fetch connects to the server when disconnected, a connection with error is
+// closed and connected again on the next call.
+func (client *IMAP) Fetch(ctx context.Context) ([]Message, error) {
+	if client.conn == nil {
+		err := client.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	uids, err := client.unseen()
+	if err != nil {
+		client.disconnect()
+
+		return nil, err
+	}
+
+	messages := make([]Message, 0, len(uids))
+
+	for _, uid := range uids {
+		if ctx.Err() != nil {
+			break
+		}
+
+		data, err := client.fetch(uid)
+		if err != nil {
+			client.disconnect()
+
+			return nil, err
+		}
+
+		messages = append(messages, Message{
+			Data: data,
+			Done: func() error {
+				if client.conn == nil {
+					return fmt.Errorf("%w: disconnected", ErrIMAP)
+				}
+
+				_, err := client.command("UID STORE " + uid + ` +FLAGS.SILENT (\Seen)`)
+				if err != nil {
+					client.disconnect()
+
+					return fmt.Errorf("error marking imap message as seen: %w", err)
+				}
+
+				return nil
+			},
+		})
+	}
+
+	return messages, nil
+}
+
+func (client *IMAP) Close() error {
+	if client.conn == nil {
+		return nil
+	}
+
+	_, err := client.command("LOGOUT")
+
+	client.disconnect()
+
+	if err != nil {
+		return fmt.Errorf("error logging out imap: %w", err)
+	}
+
+	return nil
+}
+
+func NewIMAP(host string, port int, user, password, mailbox string, useTLS bool) *IMAP {
+	return &IMAP{
+		address:  net.JoinHostPort(host, strconv.Itoa(port)),
+		user:     user,
+		password: password,
+		mailbox:  mailbox,
+		useTLS:   useTLS,
+		conn:     nil,
+		reader:   nil,
+		tag:      0,
+	}
+}
@@ -0,0 +1,16 @@
+// Package mailbox reads the messages of a Maildir, a mbox file or an IMAP
+// server.
+package mailbox
+
+import "context"
+
+// Message is a message not processed yet, Done marks it as processed.
+type Message struct {
+	Data []byte
+	Done func() error
+}
+
+type Mailbox interface {
+	Fetch(ctx context.Context) ([]Message, error)
+	Close() error
+}
@@ -0,0 +1,67 @@
+package mailbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Maildir reads the messages on the new directory, a processed message is
+// moved to the cur directory as seen.
+type Maildir struct {
+	path string
+}
+
+func (maildir *Maildir) Fetch(ctx context.Context) ([]Message, error) {
+	newDir := filepath.Join(maildir.path, "new")
+
+	entries, err := os.ReadDir(newDir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading maildir: %w", err)
+	}
+
+	messages := make([]Message, 0, len(entries))
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		name := entry.Name()
+
+		data, err := os.ReadFile(filepath.Join(newDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("error reading maildir message: %w", err)
+		}
+
+		messages = append(messages, Message{
+			Data: data,
+			Done: func() error {
+				err := os.Rename(
+					filepath.Join(newDir, name),
+					filepath.Join(maildir.path, "cur", name+":2,S"),
+				)
+				if err != nil {
+					return fmt.Errorf("error moving maildir message: %w", err)
+				}
+
+				return nil
+			},
+		})
+	}
+
+	return messages, nil
+}
+
+func (maildir *Maildir) Close() error {
+	return nil
+}
+
+func NewMaildir(path string) *Maildir {
+	return &Maildir{path: path}
+}
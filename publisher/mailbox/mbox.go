@@ -0,0 +1,152 @@
+package mailbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var mboxEscapedFrom = regexp.MustCompile(`(?m)^>(>*From )`)
+
+// Mbox reads the messages appended to a mbox file, the file is not changed
+// and the offset of the processed messages is saved on the state file, so the
+// messages are not processed again after a restart. A file smaller than the
+// offset was rotated and is read from the start.
+type Mbox struct {
+	path   string
+	state  string
+	offset int64
+}
+
+func (mbox *Mbox) loadOffset() error {
+	state, err := os.ReadFile(mbox.state)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error reading mbox state: %w", err)
+	}
+
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(state)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing mbox state: %w", err)
+	}
+
+	mbox.offset = offset
+
+	return nil
+}
+
+// saveOffset writes the state on a temporary file and renames it, a crash does
+// not leave a partial state.
+func (mbox *Mbox) saveOffset() error {
+	temporary := mbox.state + ".tmp"
+
+	err := os.WriteFile(temporary, []byte(strconv.FormatInt(mbox.offset, 10)), 0o600) //nolint:gomnd
+	if err != nil {
+		return fmt.Errorf("error writing mbox state: %w", err)
+	}
+
+	err = os.Rename(temporary, mbox.state)
+	if err != nil {
+		return fmt.Errorf("error saving mbox state: %w", err)
+	}
+
+	return nil
+}
+
+func (mbox *Mbox) Fetch(_ context.Context) ([]Message, error) {
+	file, err := os.Open(mbox.path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening mbox: %w", err)
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error getting mbox info: %w", err)
+	}
+
+	if info.Size() < mbox.offset {
+		mbox.offset = 0
+	}
+
+	_, err = file.Seek(mbox.offset, io.SeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("error seeking mbox: %w", err)
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading mbox: %w", err)
+	}
+
+	messages := []Message{}
+	start := mbox.offset
+
+	for len(data) > 0 {
+		end := bytes.Index(data, []byte("\n\nFrom "))
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += 2
+		}
+
+		message := data[:end]
+		data = data[end:]
+
+		messageStart, messageEnd := start, start+int64(end)
+		start = messageEnd
+
+		// The first line is the mbox separator.
+		_, body, found := bytes.Cut(message, []byte("\n"))
+		if !found || !bytes.HasPrefix(message, []byte("From ")) {
+			continue
+		}
+
+		messages = append(messages, Message{
+			Data: mboxEscapedFrom.ReplaceAll(body, []byte("$1")),
+			Done: func() error {
+				if mbox.offset != messageStart {
+					return nil
+				}
+
+				mbox.offset = messageEnd
+
+				return mbox.saveOffset()
+			},
+		})
+	}
+
+	return messages, nil
+}
+
+func (mbox *Mbox) Close() error {
+	return nil
+}
+
+// NewMbox reads the offset of the state file, without a state file it is
+// saved next to the mbox.
+func NewMbox(path string, state string) (*Mbox, error) {
+	if state == "" {
+		state = path + ".offset"
+	}
+
+	mbox := &Mbox{path: path, state: state, offset: 0}
+
+	err := mbox.loadOffset()
+	if err != nil {
+		return nil, err
+	}
+
+	return mbox, nil
+}
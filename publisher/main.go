@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -12,10 +15,37 @@ import (
 	"github.com/thiago-felipe-99/mail/publisher/core"
 	"github.com/thiago-felipe-99/mail/publisher/data"
 	_ "github.com/thiago-felipe-99/mail/publisher/docs"
+	"github.com/thiago-felipe-99/mail/publisher/mailbox"
 	"github.com/thiago-felipe-99/mail/publisher/model"
 	"github.com/thiago-felipe-99/mail/rabbit"
 )
 
+// newMailbox returns the mailbox of the bounces, nil when it is disabled.
+func newMailbox(configs bounceConfig) (mailbox.Mailbox, error) {
+	switch configs.Source {
+	case "maildir":
+		return mailbox.NewMaildir(configs.Path), nil
+	case "mbox":
+		mbox, err := mailbox.NewMbox(configs.Path, configs.State)
+		if err != nil {
+			return nil, fmt.Errorf("error creating mbox: %w", err)
+		}
+
+		return mbox, nil
+	case "imap":
+		return mailbox.NewIMAP(
+			configs.IMAPHost,
+			configs.IMAPPort,
+			configs.IMAPUser,
+			configs.IMAPPassword,
+			configs.IMAPMailbox,
+			configs.IMAPTLS,
+		), nil
+	default:
+		return nil, nil //nolint:nilnil
+	}
+}
+
 // Publisher main function
 //
 //	@title			Publisher Emails
@@ -40,7 +70,6 @@ func main() {
 	}
 
 	rabbitConnection := rabbit.New(rabbitConfig)
-	defer rabbitConnection.Close()
 
 	go rabbitConnection.HandleConnection()
 
@@ -90,6 +119,13 @@ func main() {
 
 	databases := data.NewDatabases(mongoClient)
 
+	err = databases.Setup()
+	if err != nil {
+		log.Printf("[ERROR] - Error setting up database: %s", err)
+
+		return
+	}
+
 	queues, err := databases.Queue.GetAll()
 	if err != nil {
 		log.Printf("[ERROR] - Error getting queues: %s", err)
@@ -98,7 +134,12 @@ func main() {
 	}
 
 	for _, queue := range queues {
-		err := rabbitConnection.CreateQueueWithDLX(queue.Name, queue.DLX, queue.MaxRetries)
+		err := rabbitConnection.CreateQueueWithDLX(
+			queue.Name,
+			queue.DLX,
+			queue.MaxRetries,
+			queue.Priority,
+		)
 		if err != nil {
 			log.Printf("[ERROR] - Error creating queue: %s", err)
 
@@ -108,16 +149,37 @@ func main() {
 
 	validate := validator.New()
 
-	cores := core.NewCores(
-		databases,
-		validate,
-		time.Duration(configs.Session.DurationMinutes)*time.Minute,
-		rabbitConnection,
-		minio,
-		configs.Minio.TemplateBucket,
-		configs.Minio.AttachmentBucket,
-		configs.Minio.MaxEntrySize,
-	)
+	cores := core.NewCores(databases, validate, rabbitConnection, minio, core.Configs{
+		SessionDuration: time.Duration(configs.Session.DurationMinutes) * time.Minute,
+		Storage: core.StorageConfig{
+			TemplateBucket:   configs.Minio.TemplateBucket,
+			AttachmentBucket: configs.Minio.AttachmentBucket,
+			MaxEntrySize:     configs.Minio.MaxEntrySize,
+		},
+		Unsubscribe: core.UnsubscribeConfig{
+			Secret:     configs.Unsubscribe.Secret,
+			URL:        configs.Unsubscribe.URL,
+			Expiration: time.Duration(configs.Unsubscribe.ExpirationDays) * 24 * time.Hour,
+		},
+		Subscription: core.SubscriptionConfig{
+			Queue:       configs.Subscribe.Queue,
+			Template:    configs.Subscribe.Template,
+			Subject:     configs.Subscribe.Subject,
+			Expiration:  time.Duration(configs.Subscribe.ExpirationHours) * time.Hour,
+			MaxPerIP:    configs.Subscribe.MaxPerIP,
+			MaxPerEmail: configs.Subscribe.MaxPerEmail,
+			MaxPending:  configs.Subscribe.MaxPending,
+		},
+		PasswordReset: core.PasswordResetConfig{
+			Queue:       configs.Reset.Queue,
+			Template:    configs.Reset.Template,
+			Subject:     configs.Reset.Subject,
+			URL:         configs.Reset.URL,
+			Expiration:  time.Duration(configs.Reset.ExpirationMinutes) * time.Minute,
+			MaxPerIP:    configs.Reset.MaxPerIP,
+			MaxPerEmail: configs.Reset.MaxPerEmail,
+		},
+	})
 
 	exist, err := cores.User.ExistByNameOrEmail(configs.Admin.Name, configs.Admin.Email)
 	if err != nil {
@@ -147,17 +209,66 @@ func main() {
 		}
 	}
 
-	server, err := controllers.CreateHTTPServer(validate, cores)
+	server, err := controllers.CreateHTTPServer(
+		validate,
+		cores,
+		configs.Server.BodyLimit,
+		configs.Server.ImportLimit,
+	)
 	if err != nil {
 		log.Printf("[ERROR] - Error create server: %s", err)
 
 		return
 	}
 
-	err = server.Listen(":8080")
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	go cores.Queue.RelayOutbox(ctx, time.Duration(configs.Outbox.Interval)*time.Second)
+
+	source, err := newMailbox(configs.Bounce)
 	if err != nil {
-		log.Printf("[ERROR] - Error listen HTTP server: %s", err)
+		log.Printf("[ERROR] - Error creating bounce mailbox: %s", err)
 
 		return
 	}
+
+	if source != nil {
+		go cores.Bounce.ProcessMailbox(ctx, source, time.Duration(configs.Bounce.Interval)*time.Second)
+	}
+
+	go func() {
+		err := server.Listen(":8080")
+		if err != nil {
+			log.Printf("[ERROR] - Error listen HTTP server: %s", err)
+		}
+
+		cancel()
+	}()
+
+	<-ctx.Done()
+
+	log.Printf("[INFO] - Shutting down the server")
+
+	timeout := time.Duration(configs.Shutdown.Timeout) * time.Second
+
+	err = server.ShutdownWithTimeout(timeout)
+	if err != nil {
+		log.Printf("[ERROR] - Error shutting down HTTP server: %s", err)
+	}
+
+	err = rabbitConnection.Close()
+	if err != nil {
+		log.Printf("[ERROR] - Error closing RabbitMQ connection: %s", err)
+	}
+
+	ctxMongo, cancelMongo := context.WithTimeout(context.Background(), timeout)
+	defer cancelMongo()
+
+	err = databases.Disconnect(ctxMongo)
+	if err != nil {
+		log.Printf("[ERROR] - Error closing MongoDB connection: %s", err)
+	}
+
+	log.Printf("[INFO] - Server stopped")
 }
@@ -53,47 +53,236 @@ type UserPartial struct {
 	Password string `config:"password" json:"password" validate:"required"`
 }
 
+// User has the TOTP secret saved while the two-factor authentication is
+// enrolled, it is only enabled after the first code is verified. The recovery
+// codes are saved as hashes and the second factor is locked until
+// TOTPLockedUntil after many wrong codes in a row.
 type User struct {
-	ID          ID        `json:"id"                    bson:"_id"`
-	Name        string    `json:"name"                  bson:"name"`
-	Email       string    `json:"email"                 bson:"email"`
-	Password    string    `json:"password,omitempty"    bson:"password"`
-	CreatedAt   time.Time `json:"createdAt"             bson:"created_at"`
-	CreatedBy   ID        `json:"createdBy"             bson:"created_by"`
-	DeletedAt   time.Time `json:"deletedAt,omitempty"   bson:"deleted_at"`
-	DeletedBy   ID        `json:"deletedBy,omitempty"   bson:"deleted_by"`
-	IsAdmin     bool      `json:"isAdmin,omitempty"     bson:"is_admin"`
-	IsProtected bool      `json:"isProtected,omitempty" bson:"is_protected"`
+	ID              ID        `json:"id"                    bson:"_id"`
+	Name            string    `json:"name"                  bson:"name"`
+	Email           string    `json:"email"                 bson:"email"`
+	Password        string    `json:"password,omitempty"    bson:"password"`
+	CreatedAt       time.Time `json:"createdAt"             bson:"created_at"`
+	CreatedBy       ID        `json:"createdBy"             bson:"created_by"`
+	DeletedAt       time.Time `json:"deletedAt,omitempty"   bson:"deleted_at"`
+	DeletedBy       ID        `json:"deletedBy,omitempty"   bson:"deleted_by"`
+	IsAdmin         bool      `json:"isAdmin,omitempty"     bson:"is_admin"`
+	IsProtected     bool      `json:"isProtected,omitempty" bson:"is_protected"`
+	TOTPEnabled     bool      `json:"totpEnabled"           bson:"totp_enabled"`
+	TOTPSecret      string    `json:"-"                     bson:"totp_secret"`
+	TOTPLastStep    int64     `json:"-"                     bson:"totp_last_step"`
+	TOTPFailures    int       `json:"-"                     bson:"totp_failures"`
+	TOTPLockedUntil time.Time `json:"-"                     bson:"totp_locked_until"`
+	RecoveryCodes   []string  `json:"-"                     bson:"recovery_codes"`
 }
 
+// UserSessionPartial has the TOTP or a recovery code when the user has the
+// two-factor authentication enabled.
 type UserSessionPartial struct {
-	Name     string `json:"name"     validate:"required_without=Email,excluded_with=Email"`
-	Email    string `json:"email"    validate:"required_without=Name,excluded_with=Name,omitempty,email"`
-	Password string `json:"password" validate:"required"`
+	Name     string `json:"name"           validate:"required_without=Email,excluded_with=Email"`
+	Email    string `json:"email"          validate:"required_without=Name,excluded_with=Name,omitempty,email"`
+	Password string `json:"password"       validate:"required"`
+	Code     string `json:"code,omitempty"`
+}
+
+type TOTPCode struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// TOTPEnrollment has the secret and the otpauth URI to be shown as a QR code
+// on the authenticator app.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// RecoveryCodes are shown only once, each one can be used once instead of a
+// TOTP code.
+type RecoveryCodes struct {
+	Codes []string `json:"codes"`
 }
 
+// Settings are the global settings changed by the admins.
+type Settings struct {
+	ID               ID   `json:"-"                bson:"_id"`
+	RequireAdminTOTP bool `json:"requireAdminTotp" bson:"require_admin_totp"`
+}
+
+// UserSession is replaced on each request, the sessions of the same login
+// have the same LoginID.
 type UserSession struct {
 	ID        ID        `json:"id"                  bson:"_id"`
 	UserID    ID        `json:"userId"              bson:"user_id"`
+	LoginID   ID        `json:"loginId"             bson:"login_id"`
+	IP        string    `json:"ip"                  bson:"ip"`
+	UserAgent string    `json:"userAgent"           bson:"user_agent"`
+	LoggedAt  time.Time `json:"loggedAt"            bson:"logged_at"`
 	CreateaAt time.Time `json:"createdAt"           bson:"created_at"`
 	Expires   time.Time `json:"expires"             bson:"expires"`
 	DeletedAt time.Time `json:"deletedAt,omitempty" bson:"deleted_at"`
 }
 
+// ActiveSession is a login of the user, the ID is the login ID and not the
+// session ID.
+type ActiveSession struct {
+	ID         ID        `json:"id"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	LoggedAt   time.Time `json:"loggedAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	Expires    time.Time `json:"expires"`
+	Current    bool      `json:"current"`
+}
+
+type PasswordForgot struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type PasswordResetPartial struct {
+	Token    string `json:"token"    validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// PasswordForgotRequest is a password reset request, it is saved even when the email
+// does not have a user to limit the requests and it is removed from the
+// database at ExpiresAt.
+type PasswordForgotRequest struct {
+	ID          ID        `bson:"_id"`
+	Key         string    `bson:"key"`
+	RequestIP   string    `bson:"request_ip"`
+	RequestedAt time.Time `bson:"requested_at"`
+	ExpiresAt   time.Time `bson:"expires_at"`
+}
+
+// PasswordReset is a single use token to set a new password, only the hash of
+// the token is saved.
+type PasswordReset struct {
+	ID        ID        `json:"id"               bson:"_id"`
+	UserID    ID        `json:"userId"           bson:"user_id"`
+	Hash      string    `json:"-"                bson:"hash"`
+	CreatedAt time.Time `json:"createdAt"        bson:"created_at"`
+	ExpiresAt time.Time `json:"expiresAt"        bson:"expires_at"`
+	UsedAt    time.Time `json:"usedAt,omitempty" bson:"used_at"`
+}
+
+const (
+	APIKeyScopeSend      = "send"
+	APIKeyScopeTemplates = "templates"
+	APIKeyScopeLists     = "lists"
+)
+
+type APIKeyPartial struct {
+	Name          string   `json:"name"                    validate:"required"`
+	Scopes        []string `json:"scopes,omitempty"        validate:"omitempty,unique,dive,oneof=send templates lists"`
+	ExpiresInDays int      `json:"expiresInDays,omitempty" validate:"omitempty,min=1"`
+}
+
+type APIKey struct {
+	ID        ID        `json:"id"                  bson:"_id"`
+	UserID    ID        `json:"userId"              bson:"user_id"`
+	Name      string    `json:"name"                bson:"name"`
+	Prefix    string    `json:"prefix"              bson:"prefix"`
+	Hash      string    `json:"-"                   bson:"hash"`
+	Scopes    []string  `json:"scopes,omitempty"    bson:"scopes"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty" bson:"expires_at"`
+	CreatedAt time.Time `json:"createdAt"           bson:"created_at"`
+	DeletedAt time.Time `json:"deletedAt,omitempty" bson:"deleted_at"`
+	DeletedBy ID        `json:"deletedBy,omitempty" bson:"deleted_by"`
+}
+
+// APIKeyCreated has the key, it is only returned when the key is created.
+type APIKeyCreated struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+const (
+	PermissionSend    = "send"
+	PermissionHistory = "history"
+	PermissionDLX     = "dlx"
+)
+
+type RolePartial struct {
+	Name        string   `json:"name"        validate:"required"`
+	Permissions []string `json:"permissions" validate:"required,min=1,unique,dive,oneof=send history dlx"`
+}
+
+type Role struct {
+	ID          ID        `json:"id"                  bson:"_id"`
+	Name        string    `json:"name"                bson:"name"`
+	Permissions []string  `json:"permissions"         bson:"permissions"`
+	CreatedAt   time.Time `json:"createdAt"           bson:"created_at"`
+	CreatedBy   ID        `json:"createdBy"           bson:"created_by"`
+	DeletedAt   time.Time `json:"deletedAt,omitempty" bson:"deleted_at"`
+	DeletedBy   ID        `json:"deletedBy,omitempty" bson:"deleted_by"`
+}
+
+type QueueGrantPartial struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// QueueGrant gives the permissions of the role on the queue to the user, the
+// user has one role per queue.
+type QueueGrant struct {
+	ID        ID        `json:"id"                  bson:"_id"`
+	UserID    ID        `json:"userId"              bson:"user_id"`
+	Queue     string    `json:"queue"               bson:"queue"`
+	Role      string    `json:"role"                bson:"role"`
+	CreatedAt time.Time `json:"createdAt"           bson:"created_at"`
+	CreatedBy ID        `json:"createdBy"           bson:"created_by"`
+	DeletedAt time.Time `json:"deletedAt,omitempty" bson:"deleted_at"`
+	DeletedBy ID        `json:"deletedBy,omitempty" bson:"deleted_by"`
+}
+
+type DLX struct {
+	Queue    string `json:"queue"`
+	DLX      string `json:"dlx"`
+	Messages int    `json:"messages"`
+}
+
+const (
+	OrganizationOwner  = "owner"
+	OrganizationMember = "member"
+)
+
+type OrganizationPartial struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type OrganizationMemberPartial struct {
+	Role string `json:"role" validate:"required,oneof=owner member"`
+}
+
+// Organization shares the templates, email lists, attachments and queues
+// with its members, Members has the role of each user.
+type Organization struct {
+	ID        ID            `json:"id"                  bson:"_id"`
+	Name      string        `json:"name"                bson:"name"`
+	Members   map[ID]string `json:"members"             bson:"members"`
+	CreatedAt time.Time     `json:"createdAt"           bson:"created_at"`
+	CreatedBy ID            `json:"createdBy"           bson:"created_by"`
+	DeletedAt time.Time     `json:"deletedAt,omitempty" bson:"deleted_at"`
+	DeletedBy ID            `json:"deletedBy,omitempty" bson:"deleted_by"`
+}
+
 type QueuePartial struct {
-	Name       string `json:"name"       validate:"required"`
-	MaxRetries int64  `json:"maxRetries" validate:"omitempty,min=1"`
+	Name         string `json:"name"                   validate:"required"`
+	MaxRetries   int64  `json:"maxRetries"             validate:"omitempty,min=1"`
+	Priority     bool   `json:"priority"`
+	Organization string `json:"organization,omitempty" validate:"omitempty,uuid"`
 }
 
 type Queue struct {
-	ID         ID        `json:"id"                  bson:"_id"`
-	Name       string    `json:"name"                bson:"name"`
-	DLX        string    `json:"dlx"                 bson:"dlx"`
-	MaxRetries int64     `json:"maxRetries"          bson:"max_retries"`
-	CreatedAt  time.Time `json:"createdAt"           bson:"created_at"`
-	CreatedBy  ID        `json:"createdBy"           bson:"created_by"`
-	DeletedAt  time.Time `json:"deletedAt,omitempty" bson:"deleted_at"`
-	DeletedBy  ID        `json:"deletedBy,omitempty" bson:"deleted_by"`
+	ID             ID        `json:"id"                       bson:"_id"`
+	Name           string    `json:"name"                     bson:"name"`
+	DLX            string    `json:"dlx"                      bson:"dlx"`
+	MaxRetries     int64     `json:"maxRetries"               bson:"max_retries"`
+	Priority       bool      `json:"priority"                 bson:"priority"`
+	OrganizationID ID        `json:"organizationId,omitempty" bson:"organization_id"`
+	CreatedAt      time.Time `json:"createdAt"                bson:"created_at"`
+	CreatedBy      ID        `json:"createdBy"                bson:"created_by"`
+	DeletedAt      time.Time `json:"deletedAt,omitempty"      bson:"deleted_at"`
+	DeletedBy      ID        `json:"deletedBy,omitempty"      bson:"deleted_by"`
 }
 
 type Receiver struct {
@@ -114,6 +303,32 @@ type EmailPartial struct {
 	Message        string        `json:"message,omitempty"        validate:"required_without=Template,excluded_with=Template"`
 	Template       *TemplateData `json:"template,omitempty"       validate:"required_without=Message,excluded_with=Message"`
 	Attachments    []string      `json:"attachments,omitempty"    validate:"-"`
+	Priority       string        `json:"priority,omitempty"       validate:"omitempty,oneof=high low"`
+	Unsubscribe    string        `json:"unsubscribe,omitempty"    validate:"-"                                                             swaggerignore:"true"`
+}
+
+// The emails are saved as pending on the outbox and they are relaying while
+// one publisher has the lease to publish them.
+const (
+	EmailPending   = "pending"
+	EmailRelaying  = "relaying"
+	EmailPublished = "published"
+	EmailBounced   = "bounced"
+)
+
+const (
+	BounceHard      = "hard"
+	BounceSoft      = "soft"
+	BounceComplaint = "complaint"
+)
+
+type Bounce struct {
+	Email      string    `json:"email"                bson:"email"`
+	Type       string    `json:"type"                 bson:"type"`
+	Action     string    `json:"action,omitempty"     bson:"action"`
+	Status     string    `json:"status,omitempty"     bson:"status"`
+	Diagnostic string    `json:"diagnostic,omitempty" bson:"diagnostic"`
+	ReceivedAt time.Time `json:"receivedAt"           bson:"received_at"`
 }
 
 type Email struct {
@@ -126,14 +341,113 @@ type Email struct {
 	Message        string        `json:"message,omitempty"        bson:"message"`
 	Template       *TemplateData `json:"template,omitempty"       bson:"template"`
 	Attachments    []string      `json:"attachments,omitempty"    bson:"attachments"`
+	Priority       string        `json:"priority,omitempty"       bson:"priority"`
+	IdempotencyKey string        `json:"idempotencyKey,omitempty" bson:"idempotency_key"`
+	JobID          ID            `json:"jobId,omitempty"          bson:"job_id"`
+	Queue          string        `json:"queue"                    bson:"queue"`
+	Status         string        `json:"status"                   bson:"status"`
 	SentAt         time.Time     `json:"sentAt"                   bson:"sent_at"`
+	PublishedAt    time.Time     `json:"publishedAt,omitempty"    bson:"published_at"`
+	LeaseExpiresAt time.Time     `json:"-"                        bson:"lease_expires_at"`
+	Unsubscribe    string        `json:"unsubscribe,omitempty"    bson:"unsubscribe"`
+	Suppressed     []string      `json:"suppressed,omitempty"     bson:"suppressed"`
+	Bounces        []Bounce      `json:"bounces,omitempty"        bson:"bounces"`
+}
+
+type EmailSent struct {
+	ID         ID       `json:"id"`
+	Status     string   `json:"status"`
+	Suppressed []string `json:"suppressed,omitempty"`
+}
+
+type BulkRecipient struct {
+	Name  string            `json:"name"  validate:"required"`
+	Email string            `json:"email" validate:"required,email"`
+	Data  map[string]string `json:"data"  validate:"-"`
+}
+
+type BulkEmailPartial struct {
+	Subject     string            `json:"subject"               form:"subject"     validate:"required"`
+	Template    string            `json:"template"              form:"template"    validate:"required"`
+	Data        map[string]string `json:"data,omitempty"        form:"-"           validate:"-"`
+	Attachments []string          `json:"attachments,omitempty" form:"attachments" validate:"-"`
+	Priority    string            `json:"priority,omitempty"    form:"priority"    validate:"omitempty,oneof=high low"`
+	EmailLists  []string          `json:"emailLists,omitempty"  form:"emailLists"  validate:"required_without=Recipients,omitempty,min=1"`
+	Recipients  []BulkRecipient   `json:"recipients,omitempty"  form:"-"           validate:"required_without=EmailLists,omitempty,min=1,max=10000"`
+}
+
+type BulkRow struct {
+	Row      int    `json:"row"               bson:"row"`
+	Email    string `json:"email"             bson:"email"`
+	Accepted bool   `json:"accepted"          bson:"accepted"`
+	EmailID  ID     `json:"emailId,omitempty" bson:"email_id"`
+	Error    string `json:"error,omitempty"   bson:"error"`
+}
+
+type BulkJob struct {
+	ID        ID        `json:"id"        bson:"_id"`
+	UserID    ID        `json:"userId"    bson:"user_id"`
+	Queue     string    `json:"queue"     bson:"queue"`
+	Template  string    `json:"template"  bson:"template"`
+	Accepted  int       `json:"accepted"  bson:"accepted"`
+	Rejected  int       `json:"rejected"  bson:"rejected"`
+	Rows      []BulkRow `json:"rows"      bson:"rows"`
+	CreatedAt time.Time `json:"createdAt" bson:"created_at"`
+}
+
+// Consent is the proof that the contact subscribed to the list.
+type Consent struct {
+	RequestIP   string    `json:"requestIp"   bson:"request_ip"`
+	RequestedAt time.Time `json:"requestedAt" bson:"requested_at"`
+	ConfirmIP   string    `json:"confirmIp"   bson:"confirm_ip"`
+	ConfirmedAt time.Time `json:"confirmedAt" bson:"confirmed_at"`
+}
+
+type Contact struct {
+	Name       string            `json:"name,omitempty"       bson:"name"`
+	Attributes map[string]string `json:"attributes,omitempty" bson:"attributes"`
+	Consent    *Consent          `json:"consent,omitempty"    bson:"consent"`
+}
+
+// PendingContact is a subscription waiting the confirmation, it is removed
+// from the database at ExpiresAt.
+type PendingContact struct {
+	ID          ID                `json:"id"                   bson:"_id"`
+	ListID      ID                `json:"-"                    bson:"list_id"`
+	Email       string            `json:"email"                bson:"email"`
+	Key         string            `json:"-"                    bson:"key"`
+	Name        string            `json:"name,omitempty"       bson:"name"`
+	Attributes  map[string]string `json:"attributes,omitempty" bson:"attributes"`
+	RequestIP   string            `json:"requestIp"            bson:"request_ip"`
+	RequestedAt time.Time         `json:"requestedAt"          bson:"requested_at"`
+	ExpiresAt   time.Time         `json:"-"                    bson:"expires_at"`
+}
+
+// ListContact is an email of a list, the contacts are saved apart from the
+// list so its size is not limited by the size of a document. The key is the
+// normalized email, an email is saved once on each list.
+type ListContact struct {
+	ID         ID                `json:"id"                   bson:"_id"`
+	ListID     ID                `json:"-"                    bson:"list_id"`
+	Email      string            `json:"email"                bson:"email"`
+	Key        string            `json:"-"                    bson:"key"`
+	Name       string            `json:"name,omitempty"       bson:"name"`
+	Attributes map[string]string `json:"attributes,omitempty" bson:"attributes"`
+	Consent    *Consent          `json:"consent,omitempty"    bson:"consent"`
+}
+
+type ContactPartial struct {
+	Email      string            `json:"email"                validate:"required,email"`
+	Name       string            `json:"name,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty" validate:"-"`
 }
 
 type EmailListPartial struct {
-	Emails      []string `json:"emails"      validate:"required,min=1,dive,email"`
-	Name        string   `json:"name"        validate:"required"`
-	EmailAlias  string   `json:"emailAlias"  validate:"required,email"`
-	Description string   `json:"description" validate:"required"`
+	Emails      []string         `json:"emails,omitempty"   validate:"required_without=Contacts,omitempty,min=1,dive,email"`
+	Contacts    []ContactPartial `json:"contacts,omitempty" validate:"required_without=Emails,omitempty,min=1,dive"`
+	Name        string           `json:"name"               validate:"required"`
+	EmailAlias  string           `json:"emailAlias"         validate:"required,email"`
+	Description string           `json:"description"        validate:"required"`
 }
 
 type EmailListInfo struct {
@@ -143,20 +457,103 @@ type EmailListInfo struct {
 }
 
 type EmailListEmails struct {
-	Emails []string `json:"emails" validate:"required,min=1,dive,email"`
+	Emails   []string         `json:"emails,omitempty"   validate:"required_without=Contacts,omitempty,min=1,dive,email"`
+	Contacts []ContactPartial `json:"contacts,omitempty" validate:"required_without=Emails,omitempty,min=1,dive"`
+}
+
+type EmailListImportOptions struct {
+	EmailColumn string   `json:"emailColumn" form:"emailColumn"`
+	NameColumn  string   `json:"nameColumn"  form:"nameColumn"`
+	Attributes  []string `json:"attributes"  form:"attributes"`
+	DryRun      bool     `json:"dryRun"      form:"dryRun"`
 }
 
+type ImportError struct {
+	Row   int    `json:"row"`
+	Email string `json:"email"`
+	Error string `json:"error"`
+}
+
+type EmailListImport struct {
+	Rows       int           `json:"rows"`
+	Added      int           `json:"added"`
+	Updated    int           `json:"updated"`
+	Unchanged  int           `json:"unchanged"`
+	Invalid    int           `json:"invalid"`
+	Duplicated int           `json:"duplicated"`
+	DryRun     bool          `json:"dryRun"`
+	Errors     []ImportError `json:"errors"`
+}
+
+const (
+	SuppressionUnsubscribed = "unsubscribed"
+	SuppressionBounced      = "bounced"
+	SuppressionComplained   = "complained"
+	SuppressionManual       = "manual"
+)
+
+type SuppressionPartial struct {
+	Email  string `json:"email"  validate:"required,email"`
+	Reason string `json:"reason" validate:"required,oneof=unsubscribed bounced complained manual"`
+}
+
+type SuppressionBulk struct {
+	Suppressions []SuppressionPartial `json:"suppressions" validate:"required,min=1,max=10000"`
+}
+
+// Suppression is an email that does not receive emails from the user, a
+// global suppression has an empty user ID.
+type Suppression struct {
+	ID        ID        `json:"id"        bson:"_id"`
+	Email     string    `json:"email"     bson:"email"`
+	UserID    ID        `json:"userId"    bson:"user_id"`
+	Global    bool      `json:"global"    bson:"global"`
+	Reason    string    `json:"reason"    bson:"reason"`
+	CreatedAt time.Time `json:"createdAt" bson:"created_at"`
+	CreatedBy ID        `json:"createdBy" bson:"created_by"`
+	DeletedAt time.Time `json:"-"         bson:"deleted_at"`
+	DeletedBy ID        `json:"-"         bson:"deleted_by"`
+}
+
+type SuppressionImport struct {
+	Rows       int           `json:"rows"`
+	Suppressed int           `json:"suppressed"`
+	Invalid    int           `json:"invalid"`
+	Errors     []ImportError `json:"errors"`
+}
+
+type Subscription struct {
+	List      string `json:"list"`
+	Email     string `json:"email"`
+	Confirmed bool   `json:"confirmed"`
+}
+
+type Unsubscribe struct {
+	List       string `json:"list"`
+	Email      string `json:"email"`
+	Subscribed bool   `json:"subscribed"`
+}
+
+// EmailList is saved without its emails and pending subscriptions, they are
+// saved as ListContact and PendingContact.
 type EmailList struct {
-	ID ID `json:"id"                  bson:"_id"`
+	ID             ID        `json:"id"                       bson:"_id"`
+	Name           string    `json:"name"                     bson:"name"`
+	EmailAlias     string    `json:"emailAlias"               bson:"email_alias"`
+	Description    string    `json:"description"              bson:"description"`
+	OrganizationID ID        `json:"organizationId,omitempty" bson:"organization_id"`
+	CreatedAt      time.Time `json:"createdAt"                bson:"created_at"`
+	CreatedBy      ID        `json:"createdBy"                bson:"created_by"`
+	DeletedAt      time.Time `json:"deletedAt,omitempty"      bson:"deleted_at"`
+	DeletedBy      ID        `json:"deletedBy,omitempty"      bson:"deleted_by"`
+}
+
+// EmailListContacts is the email list returned with its emails and contacts.
+type EmailListContacts struct {
+	EmailList
 	// Email uses map[id] to have the possibility to remove by ID without revealing which email will be removed
-	Emails      map[ID]string `json:"emails"              bson:"emails"`
-	Name        string        `json:"name"                bson:"name"`
-	EmailAlias  string        `json:"emailAlias"          bson:"email_alias"`
-	Description string        `json:"description"         bson:"description"`
-	CreatedAt   time.Time     `json:"createdAt"           bson:"created_at"`
-	CreatedBy   ID            `json:"createdBy"           bson:"created_by"`
-	DeletedAt   time.Time     `json:"deletedAt,omitempty" bson:"deleted_at"`
-	DeletedBy   ID            `json:"deletedBy,omitempty" bson:"deleted_by"`
+	Emails   map[ID]string  `json:"emails"`
+	Contacts map[ID]Contact `json:"contacts,omitempty"`
 }
 
 type TemplatePartial struct {
@@ -165,14 +562,15 @@ type TemplatePartial struct {
 }
 
 type Template struct {
-	ID        ID        `json:"id"                  bson:"_id"`
-	Name      string    `json:"name"                bson:"name"`
-	Template  string    `json:"template"            bson:"template"`
-	Fields    []string  `json:"fields,omitempty"    bson:"fields"`
-	CreatedAt time.Time `json:"createdAt"           bson:"created_at"`
-	CreatedBy ID        `json:"createdBy"           bson:"created_by"`
-	DeletedAt time.Time `json:"deletedAt,omitempty" bson:"deleted_at"`
-	DeletedBy ID        `json:"deletedBy,omitempty" bson:"deleted_by"`
+	ID             ID        `json:"id"                       bson:"_id"`
+	Name           string    `json:"name"                     bson:"name"`
+	Template       string    `json:"template"                 bson:"template"`
+	Fields         []string  `json:"fields,omitempty"         bson:"fields"`
+	OrganizationID ID        `json:"organizationId,omitempty" bson:"organization_id"`
+	CreatedAt      time.Time `json:"createdAt"                bson:"created_at"`
+	CreatedBy      ID        `json:"createdBy"                bson:"created_by"`
+	DeletedAt      time.Time `json:"deletedAt,omitempty"      bson:"deleted_at"`
+	DeletedBy      ID        `json:"deletedBy,omitempty"      bson:"deleted_by"`
 }
 
 type AttachmentPartial struct {
@@ -182,14 +580,15 @@ type AttachmentPartial struct {
 }
 
 type Attachment struct {
-	ID              ID        `json:"id"              bson:"_id"`
-	UserID          ID        `json:"userId"          bson:"user_id"`
-	CreatedAt       time.Time `json:"createdAt"       bson:"created_at"`
-	Name            string    `json:"name"            bson:"name"`
-	ContentType     string    `json:"contentType"     bson:"content_type"`
-	Size            int       `json:"size"            bson:"size"`
-	MinioName       string    `json:"minioName"       bson:"minio_name"`
-	ConfirmedUpload bool      `json:"confirmedUpload" bson:"confirmed_upload"`
+	ID              ID        `json:"id"                       bson:"_id"`
+	UserID          ID        `json:"userId"                   bson:"user_id"`
+	OrganizationID  ID        `json:"organizationId,omitempty" bson:"organization_id"`
+	CreatedAt       time.Time `json:"createdAt"                bson:"created_at"`
+	Name            string    `json:"name"                     bson:"name"`
+	ContentType     string    `json:"contentType"              bson:"content_type"`
+	Size            int       `json:"size"                     bson:"size"`
+	MinioName       string    `json:"minioName"                bson:"minio_name"`
+	ConfirmedUpload bool      `json:"confirmedUpload"          bson:"confirmed_upload"`
 }
 
 type AttachmentURL struct {
@@ -198,3 +597,14 @@ type AttachmentURL struct {
 	URL       string            `json:"url"`
 	FormData  map[string]string `json:"formData,omitempty"`
 }
+
+type DependencyHealth struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Health struct {
+	Healthy      bool               `json:"healthy"`
+	Dependencies []DependencyHealth `json:"dependencies"`
+}
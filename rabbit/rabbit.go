@@ -6,6 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	neturl "net/url"
+	"sync"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -20,6 +23,8 @@ var (
 	ErrSendingMessage   = errors.New("error sending message")
 	ErrTimeoutMessage   = errors.New("timeout sending message")
 	ErrMaxRetries       = errors.New("error max retries")
+	ErrNoManagement     = errors.New("RabbitMQ management port was not configured")
+	ErrListingQueues    = errors.New("error listing queues")
 )
 
 type MaxRetriesError struct {
@@ -37,23 +42,56 @@ func (maxRetriesError *MaxRetriesError) Error() string {
 }
 
 type Config struct {
-	User     string
-	Password string
-	Host     string
-	Port     string
-	Vhost    string
+	User           string
+	Password       string
+	Host           string
+	Port           string
+	Vhost          string
+	ManagementPort string
+}
+
+type QueueInfo struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// HasDLX reports if the queue was created with a dead letter exchange.
+func (info QueueInfo) HasDLX() bool {
+	_, exist := info.Arguments["x-dead-letter-exchange"]
+
+	return exist
+}
+
+// DLX returns the dead letter exchange of the queue, the DLX queue has the same
+// name.
+func (info QueueInfo) DLX() string {
+	dlx, _ := info.Arguments["x-dead-letter-exchange"].(string)
+
+	return dlx
 }
 
 type Rabbit struct {
 	url                string
+	managementURL      string
+	user               string
+	password           string
 	maxRetries         int
 	timeoutSendMessage time.Duration
 
 	close      bool
+	stopped    bool
 	connection *amqp.Connection
 }
 
+// IsConnected reports if the connection with RabbitMQ is open.
+func (rabbit *Rabbit) IsConnected() bool {
+	return !rabbit.close && rabbit.connection != nil && !rabbit.connection.IsClosed()
+}
+
+// Close closes the connection and stops HandleConnection from recreating it.
 func (rabbit *Rabbit) Close() error {
+	rabbit.stopped = true
+
 	if rabbit.close {
 		return ErrAlreadyClosed
 	}
@@ -96,17 +134,23 @@ func (rabbit *Rabbit) retries(maxRetries int, errsReturn []error, try func() err
 	return errMaxRetries
 }
 
-func (rabbit *Rabbit) CreateQueueWithDLX(name string, dlx string, maxRetries int64) error {
+// HighPriorityQueue is the queue with the high priority messages of a queue
+// created with priority, the consumers drain it before the queue.
+func HighPriorityQueue(name string) string {
+	return name + "-high"
+}
+
+func (rabbit *Rabbit) CreateQueueWithDLX(name string, dlx string, maxRetries int64, priority bool) error {
 	errsReturn := []error{}
 
 	createQueue := func() error {
-		return rabbit.createQueueWithDLX(name, dlx, maxRetries)
+		return rabbit.createQueueWithDLX(name, dlx, maxRetries, priority)
 	}
 
 	return rabbit.retries(rabbit.maxRetries, errsReturn, createQueue)
 }
 
-func (rabbit *Rabbit) createQueueWithDLX(name string, dlx string, maxRetries int64) error {
+func (rabbit *Rabbit) createQueueWithDLX(name string, dlx string, maxRetries int64, priority bool) error {
 	if rabbit.close {
 		return ErrConnectionClosed
 	}
@@ -130,6 +174,13 @@ func (rabbit *Rabbit) createQueueWithDLX(name string, dlx string, maxRetries int
 		return fmt.Errorf("error declaring RabbitMQ queue: %w", err)
 	}
 
+	if priority {
+		_, err = channel.QueueDeclare(HighPriorityQueue(name), true, false, false, false, queueArgs)
+		if err != nil {
+			return fmt.Errorf("error declaring RabbitMQ high priority queue: %w", err)
+		}
+	}
+
 	_, err = channel.QueueDeclare(dlx, true, false, false, false, nil)
 	if err != nil {
 		return fmt.Errorf("error declaring RabbitMQ dlx queue: %w", err)
@@ -148,17 +199,17 @@ func (rabbit *Rabbit) createQueueWithDLX(name string, dlx string, maxRetries int
 	return nil
 }
 
-func (rabbit *Rabbit) DeleteQueueWithDLX(name string, dlx string) error {
+func (rabbit *Rabbit) DeleteQueueWithDLX(name string, dlx string, priority bool) error {
 	errsReturn := []error{}
 
 	deleteQueue := func() error {
-		return rabbit.deleteQueueWithDLX(name, dlx)
+		return rabbit.deleteQueueWithDLX(name, dlx, priority)
 	}
 
 	return rabbit.retries(rabbit.maxRetries, errsReturn, deleteQueue)
 }
 
-func (rabbit *Rabbit) deleteQueueWithDLX(name string, dlx string) error {
+func (rabbit *Rabbit) deleteQueueWithDLX(name string, dlx string, priority bool) error {
 	channel, err := rabbit.connection.Channel()
 	if err != nil {
 		return fmt.Errorf("failed to open RabbitMQ channel: %w", err)
@@ -176,6 +227,13 @@ func (rabbit *Rabbit) deleteQueueWithDLX(name string, dlx string) error {
 		return fmt.Errorf("error deleting dlx queue: %w", err)
 	}
 
+	if priority {
+		_, err = channel.QueueDelete(HighPriorityQueue(name), false, false, false)
+		if err != nil {
+			return fmt.Errorf("error deleting high priority queue: %w", err)
+		}
+	}
+
 	_, err = channel.QueueDelete(name, false, false, false)
 	if err != nil {
 		return fmt.Errorf("error deleting queue: %w", err)
@@ -184,17 +242,19 @@ func (rabbit *Rabbit) deleteQueueWithDLX(name string, dlx string) error {
 	return nil
 }
 
-func (rabbit *Rabbit) SendMessage(ctx context.Context, queue string, message any) error {
+// SendMessage sends the message as JSON, the message ID is used by the
+// consumers to skip duplicated messages.
+func (rabbit *Rabbit) SendMessage(ctx context.Context, queue string, messageID string, message any) error {
 	errsReturn := []error{ErrEncondingMessage}
 
 	sendMessage := func() error {
-		return rabbit.sendMessage(ctx, queue, message)
+		return rabbit.sendMessage(ctx, queue, messageID, message)
 	}
 
 	return rabbit.retries(rabbit.maxRetries, errsReturn, sendMessage)
 }
 
-func (rabbit *Rabbit) sendMessage(ctx context.Context, queue string, message any) error {
+func (rabbit *Rabbit) sendMessage(ctx context.Context, queue string, messageID string, message any) error {
 	if rabbit.close {
 		return ErrConnectionClosed
 	}
@@ -221,6 +281,7 @@ func (rabbit *Rabbit) sendMessage(ctx context.Context, queue string, message any
 
 	publish := amqp.Publishing{
 		ContentType: "application/json",
+		MessageId:   messageID,
 		Body:        messageEncoding,
 	}
 
@@ -248,10 +309,167 @@ func (rabbit *Rabbit) sendMessage(ctx context.Context, queue string, message any
 	return nil
 }
 
+// CountMessages returns the number of messages ready on the queue.
+func (rabbit *Rabbit) CountMessages(name string) (int, error) {
+	if rabbit.close {
+		return 0, ErrConnectionClosed
+	}
+
+	channel, err := rabbit.connection.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+	}
+
+	defer channel.Close()
+
+	queue, err := channel.QueueDeclarePassive(name, true, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error inspecting RabbitMQ queue: %w", err)
+	}
+
+	return queue.Messages, nil
+}
+
+// HasHighPriority reports if the queue was created with a high priority queue.
+func (rabbit *Rabbit) HasHighPriority(name string) (bool, error) {
+	if rabbit.close {
+		return false, ErrConnectionClosed
+	}
+
+	channel, err := rabbit.connection.Channel()
+	if err != nil {
+		return false, fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+	}
+
+	defer channel.Close()
+
+	_, err = channel.QueueDeclarePassive(HighPriorityQueue(name), true, false, false, false, nil)
+
+	amqpErr := &amqp.Error{}
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("error inspecting RabbitMQ high priority queue: %w", err)
+	}
+
+	return true, nil
+}
+
+// PurgeMessages removes the messages ready on the queue.
+func (rabbit *Rabbit) PurgeMessages(name string) (int, error) {
+	if rabbit.close {
+		return 0, ErrConnectionClosed
+	}
+
+	channel, err := rabbit.connection.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+	}
+
+	defer channel.Close()
+
+	purged, err := channel.QueuePurge(name, false)
+	if err != nil {
+		return 0, fmt.Errorf("error purging RabbitMQ queue: %w", err)
+	}
+
+	return purged, nil
+}
+
+// MoveMessages publishes the messages of a queue on another one, a message is
+// only removed after its publishing is confirmed. Only the messages ready when
+// the move starts are moved, so the messages that come back to the queue while
+// it is moved are not moved again.
+func (rabbit *Rabbit) MoveMessages(ctx context.Context, from string, to string) (int, error) {
+	if rabbit.close {
+		return 0, ErrConnectionClosed
+	}
+
+	channel, err := rabbit.connection.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open RabbitMQ channel: %w", err)
+	}
+
+	defer channel.Close()
+
+	queue, err := channel.QueueDeclarePassive(from, true, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error inspecting RabbitMQ queue: %w", err)
+	}
+
+	err = channel.Confirm(false)
+	if err != nil {
+		return 0, fmt.Errorf("error confirm channel: %w", err)
+	}
+
+	moved := 0
+
+	for moved < queue.Messages && ctx.Err() == nil {
+		message, found, err := channel.Get(from, false)
+		if err != nil {
+			return moved, fmt.Errorf("error getting message: %w", err)
+		}
+
+		if !found {
+			break
+		}
+
+		err = rabbit.moveMessage(ctx, channel, message, to)
+		if err != nil {
+			nackErr := message.Nack(false, true)
+
+			return moved, errors.Join(err, nackErr)
+		}
+
+		moved++
+	}
+
+	return moved, nil
+}
+
+func (rabbit *Rabbit) moveMessage(
+	ctx context.Context,
+	channel *amqp.Channel,
+	message amqp.Delivery,
+	queue string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, rabbit.timeoutSendMessage)
+	defer cancel()
+
+	publish := amqp.Publishing{
+		ContentType: message.ContentType,
+		MessageId:   message.MessageId,
+		Body:        message.Body,
+	}
+
+	confirm, err := channel.PublishWithDeferredConfirmWithContext(ctx, "", queue, false, false, publish)
+	if err != nil {
+		return errors.Join(ErrSendingMessage, err)
+	}
+
+	done, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return errors.Join(ErrTimeoutMessage, err)
+	}
+
+	if !done {
+		return ErrTimeoutMessage
+	}
+
+	err = message.Ack(false)
+	if err != nil {
+		return fmt.Errorf("error acknowledging message: %w", err)
+	}
+
+	return nil
+}
+
 func (rabbit *Rabbit) HandleConnection() {
 	recreatDelay := time.Second
 
-	for {
+	for !rabbit.stopped {
 		log.Println("[INFO] - Trying to connect with RabbitMQ")
 
 		connectionClose, err := rabbit.createConnection()
@@ -270,8 +488,14 @@ func (rabbit *Rabbit) HandleConnection() {
 
 		<-connectionClose
 
+		if rabbit.stopped {
+			break
+		}
+
 		log.Printf("[INFO] - Connection was closed, recreating connection")
 	}
+
+	log.Printf("[INFO] - Connection was closed")
 }
 
 func (rabbit *Rabbit) createConnection() (chan *amqp.Error, error) {
@@ -291,7 +515,119 @@ func (rabbit *Rabbit) createConnection() (chan *amqp.Error, error) {
 	return connectionClose, nil
 }
 
-func (rabbit *Rabbit) Consume(name string, bufferSize int) (<-chan amqp.Delivery, error) {
+// ListQueues lists the queues of the vhost through the management API.
+func (rabbit *Rabbit) ListQueues(ctx context.Context) ([]QueueInfo, error) {
+	if rabbit.managementURL == "" {
+		return nil, ErrNoManagement
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, rabbit.managementURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	request.SetBasicAuth(rabbit.user, rabbit.password)
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, errors.Join(ErrListingQueues, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", ErrListingQueues, response.Status)
+	}
+
+	queues := []QueueInfo{}
+
+	err = json.NewDecoder(response.Body).Decode(&queues)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding queues: %w", err)
+	}
+
+	return queues, nil
+}
+
+// Consumer consumes a queue and, when it was created with priority, its high
+// priority queue, both consumers share the channel so they are closed together.
+type Consumer struct {
+	channel  *amqp.Channel
+	tags     []string
+	pending  sync.WaitGroup
+	High     <-chan amqp.Delivery
+	Messages <-chan amqp.Delivery
+}
+
+// acknowledger marks the message as done on the consumer after the first
+// acknowledgment, the later ones are still sent to the channel.
+type acknowledger struct {
+	amqp.Acknowledger
+	once    sync.Once
+	pending *sync.WaitGroup
+}
+
+func (ack *acknowledger) Ack(tag uint64, multiple bool) error {
+	defer ack.once.Do(ack.pending.Done)
+
+	return ack.Acknowledger.Ack(tag, multiple) //nolint:wrapcheck
+}
+
+func (ack *acknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	defer ack.once.Do(ack.pending.Done)
+
+	return ack.Acknowledger.Nack(tag, multiple, requeue) //nolint:wrapcheck
+}
+
+func (ack *acknowledger) Reject(tag uint64, requeue bool) error {
+	defer ack.once.Do(ack.pending.Done)
+
+	return ack.Acknowledger.Reject(tag, requeue) //nolint:wrapcheck
+}
+
+// Track returns the message that Close waits to be acknowledged, rejected or
+// not acknowledged before closing the channel. All tracked messages must be
+// acknowledged in some way, otherwise Close blocks.
+func (consumer *Consumer) Track(message Message) Message {
+	consumer.pending.Add(1)
+
+	message.Acknowledger = &acknowledger{
+		Acknowledger: message.Acknowledger,
+		pending:      &consumer.pending,
+	}
+
+	return message
+}
+
+// Cancel stops the deliveries of new messages, High and Messages are closed
+// after the messages already delivered were received.
+func (consumer *Consumer) Cancel() error {
+	errs := []error{}
+
+	for _, tag := range consumer.tags {
+		err := consumer.channel.Cancel(tag, false)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error canceling consumer '%s': %w", tag, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close waits the tracked messages to be acknowledged and closes the channel,
+// the messages not acknowledged are requeued.
+func (consumer *Consumer) Close() error {
+	consumer.pending.Wait()
+
+	err := consumer.channel.Close()
+	if err != nil && !errors.Is(err, amqp.ErrClosed) {
+		return fmt.Errorf("error closing consumer channel: %w", err)
+	}
+
+	return nil
+}
+
+func (rabbit *Rabbit) Consume(name string, bufferSize int, priority bool) (*Consumer, error) {
 	if rabbit.close {
 		return nil, ErrConnectionClosed
 	}
@@ -301,17 +637,38 @@ func (rabbit *Rabbit) Consume(name string, bufferSize int) (<-chan amqp.Delivery
 		return nil, fmt.Errorf("failed to open RabbitMQ channel: %w", err)
 	}
 
+	consumer := &Consumer{channel: channel}
+
 	err = channel.Qos(bufferSize, 0, false)
 	if err != nil {
+		channel.Close()
+
 		return nil, fmt.Errorf("error configuring consumer queue size: %w", err)
 	}
 
-	queue, err := channel.Consume(name, "", false, false, false, false, nil)
+	if priority {
+		tag := HighPriorityQueue(name)
+
+		consumer.High, err = channel.Consume(tag, tag, false, false, false, false, nil)
+		if err != nil {
+			channel.Close()
+
+			return nil, fmt.Errorf("error registering high priority consumer: %w", err)
+		}
+
+		consumer.tags = append(consumer.tags, tag)
+	}
+
+	consumer.Messages, err = channel.Consume(name, name, false, false, false, false, nil)
 	if err != nil {
+		channel.Close()
+
 		return nil, fmt.Errorf("error registering consumer: %w", err)
 	}
 
-	return queue, nil
+	consumer.tags = append(consumer.tags, name)
+
+	return consumer, nil
 }
 
 func New(config Config) *Rabbit {
@@ -324,9 +681,22 @@ func New(config Config) *Rabbit {
 		config.Vhost,
 	)
 
+	managementURL := ""
+	if config.ManagementPort != "" {
+		managementURL = fmt.Sprintf(
+			"http://%s:%s/api/queues/%s?columns=name,arguments",
+			config.Host,
+			config.ManagementPort,
+			neturl.PathEscape(config.Vhost),
+		)
+	}
+
 	//nolint: gomnd
 	rabbit := &Rabbit{
 		url:                url,
+		managementURL:      managementURL,
+		user:               config.User,
+		password:           config.Password,
 		maxRetries:         3,
 		timeoutSendMessage: 5 * time.Second,
 		close:              true,